@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/auth"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage API keys stored in the OS keychain",
+	Long: `Stores API keys in the operating system's keychain (macOS Keychain,
+Windows Credential Manager, or the Secret Service/libsecret on Linux) so they
+don't have to live in plaintext environment variables or .env files.
+
+Environment variables still take precedence over stored keys.`,
+}
+
+var authSetKeyCmd = &cobra.Command{
+	Use:       "set-key <openai|github>",
+	Short:     "Store an API key in the OS keychain",
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: auth.KeyNames(),
+	RunE:      runAuthSetKey,
+}
+
+var authDeleteKeyCmd = &cobra.Command{
+	Use:       "delete-key <openai|github>",
+	Short:     "Remove an API key from the OS keychain",
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: auth.KeyNames(),
+	RunE:      runAuthDeleteKey,
+}
+
+func init() {
+	authCmd.AddCommand(authSetKeyCmd)
+	authCmd.AddCommand(authDeleteKeyCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+func runAuthSetKey(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	var value string
+	err := huh.NewInput().
+		Title(fmt.Sprintf(i18n.T("Enter your %s key"), name)).
+		EchoMode(huh.EchoModePassword).
+		Value(&value).
+		Run()
+	if err != nil {
+		return fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return errors.New(i18n.T("no key entered"))
+	}
+
+	if err := auth.SetKey(name, value); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Stored %s key in the OS keychain."), name))
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Note: %s still takes precedence when set."), auth.KeyEnvVar(name)))
+	return nil
+}
+
+func runAuthDeleteKey(cmd *cobra.Command, args []string) error {
+	if err := auth.DeleteKey(args[0]); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Removed %s key from the OS keychain."), args[0]))
+	return nil
+}
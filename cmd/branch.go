@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var branchCmd = &cobra.Command{
+	Use:   `branch ["short description"]`,
+	Short: "Create and switch to a branch with an AI-suggested name",
+	Long: `Proposes a kebab-case branch name, e.g. feat/add-user-login, creates the
+branch and switches to it. Staged and unstaged changes are carried over.
+
+The name is based on the description, if given, or else on the staged
+changes, or else on the unstaged ones. It starts with one of the configured
+prefixes (branch.prefixes in .vibe.yaml, feat/, fix/, chore/, docs/ and
+refactor/ by default).
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	RunE: runBranch,
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+}
+
+func runBranch(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	description := strings.TrimSpace(strings.Join(args, " "))
+	var diff string
+	if description == "" {
+		if diff, err = branchDiff(repo); err != nil {
+			return err
+		}
+		diff, _ = git.FilterDiff(diff, cfg.Paths.Exclude)
+		if strings.TrimSpace(diff) == "" {
+			return errors.New(i18n.T(`nothing to name the branch after
+
+Describe the work, or make some changes first:
+  vibe branch "add rate limiting to the API"`))
+		}
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Suggesting a branch name"))
+	name, err := llmClient.SuggestBranchName(description, diff, cfg.Branch.BranchPrefixes())
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to suggest a branch name: %w"), err)
+	}
+
+	result, err := ui.ConfirmBranch(name)
+	if err != nil {
+		return err
+	}
+	if result.Action == ui.ActionCancel {
+		ui.ShowInfo(i18n.T("Branch not created."))
+		return errCancelled
+	}
+
+	if err := repo.CreateBranch(result.Name); err != nil {
+		return err
+	}
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Switched to new branch %s"), result.Name))
+	return nil
+}
+
+// branchDiff returns the staged changes, or the unstaged ones when nothing
+// is staged
+func branchDiff(repo *git.Repository) (string, error) {
+	hasStaged, err := repo.HasStagedChanges()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+	}
+	if hasStaged {
+		diff, err := repo.GetStagedDiff()
+		if err != nil {
+			return "", fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+		}
+		return diff, nil
+	}
+
+	diff, err := repo.WorktreeDiff(true)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to get unstaged changes: %w"), err)
+	}
+	return diff, nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/changelog"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var bumpCmd = &cobra.Command{
+	Use:   "bump",
+	Short: "Recommend the next semantic version from the commits since the last tag",
+	Long: `Analyzes the commits since the latest tag and recommends a major, minor
+or patch bump per semantic versioning, explaining which changes drove the
+decision. Nothing is changed; tag the release with vibe tag afterwards.
+
+Before 1.0.0, breaking changes bump the minor version.
+
+Requirements:
+- Must be in a git repository with a semantic version tag such as v1.2.3
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runBump,
+}
+
+func init() {
+	rootCmd.AddCommand(bumpCmd)
+}
+
+func runBump(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	previous, err := repo.PreviousTag()
+	if err != nil {
+		return err
+	}
+	if previous == "" {
+		return errors.New(i18n.T(`no tags found, so there is no version to bump
+
+Tag the first release yourself, e.g.:
+  vibe tag v0.1.0`))
+	}
+	// Fail on tags like "release-7" before spending an API call
+	if _, err := changelog.NextVersion(previous, changelog.Patch); err != nil {
+		return fmt.Errorf(i18n.T("the latest tag can't be bumped: %w"), err)
+	}
+
+	commits, err := repo.CommitsSinceTag(previous)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf(i18n.T("no commits since %s, there is nothing to release"), previous)
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Analyzing %d commit(s) since %s..."), len(commits), previous))
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Recommending the next version"))
+	suggestion, err := llmClient.SuggestBump(previous, describeCommits(commits))
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to suggest a version bump: %w"), err)
+	}
+
+	next, err := changelog.NextVersion(previous, suggestion.Level)
+	if err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Recommended bump: %s (%s -> %s)"), suggestion.Level, previous, next))
+	if suggestion.Reason != "" {
+		fmt.Printf("\n%s\n", suggestion.Reason)
+	}
+	fmt.Printf(i18n.T("\n  Tag the release with: vibe tag %s\n"), next)
+	return nil
+}
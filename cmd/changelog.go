@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/changelog"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	changelogSince   string
+	changelogFormat  string
+	changelogVersion string
+	changelogWrite   bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate a changelog section from the commits since the last tag",
+	Long: `Summarizes the commits since the previous tag (or --since) into a
+human-readable changelog section, grouped into breaking changes, features,
+fixes and other changes.
+
+The section follows Keep a Changelog (--format keepachangelog, the default)
+or the conventional-changelog layout (--format conventional). It is printed,
+or with --write, added to CHANGELOG.md in the repository root above the
+latest release. An existing section with the same heading is replaced.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Example: `  vibe changelog
+  vibe changelog --since v1.3.0 --version v1.4.0 --write
+  vibe changelog --format conventional`,
+	Args: cobra.NoArgs,
+	RunE: runChangelog,
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Tag to start from (default: the latest tag reachable from HEAD)")
+	changelogCmd.Flags().StringVar(&changelogFormat, "format", changelog.FormatKeepAChangelog, "Section format: "+strings.Join(changelog.Formats, " or "))
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", changelog.Unreleased, "Version the section is headed with")
+	changelogCmd.Flags().BoolVarP(&changelogWrite, "write", "w", false, "Add the section to "+changelog.FileName+" instead of printing it")
+	rootCmd.AddCommand(changelogCmd)
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	// Check the format before anything is generated
+	if _, err := changelog.Render(changelogFormat, changelogVersion, time.Now(), nil); err != nil {
+		return err
+	}
+
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	since := changelogSince
+	if since == "" {
+		if since, err = repo.PreviousTag(); err != nil {
+			return err
+		}
+	}
+
+	commits, err := repo.CommitsSinceTag(since)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf(i18n.T("no commits since %s"), since)
+	}
+
+	if since != "" {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Summarizing %d commit(s) since %s..."), len(commits), since))
+	} else {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Summarizing %d commit(s), there is no previous tag..."), len(commits)))
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Summarizing the commits"))
+	entries, err := llmClient.SummarizeChangelog(describeCommits(commits))
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate changelog: %w"), err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf(i18n.T("none of the commits since %s describe a user-visible change"), since)
+	}
+
+	section, err := changelog.Render(changelogFormat, changelogVersion, time.Now(), entries)
+	if err != nil {
+		return err
+	}
+
+	if !changelogWrite {
+		ui.Page("\n" + section)
+		return nil
+	}
+
+	path := filepath.Join(repo.Root(), changelog.FileName)
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf(i18n.T("failed to read %s: %w"), changelog.FileName, err)
+	}
+	if err := os.WriteFile(path, []byte(changelog.Prepend(string(content), section)), 0o644); err != nil {
+		return fmt.Errorf(i18n.T("failed to write %s: %w"), changelog.FileName, err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Updated %s"), changelog.FileName))
+	ui.Page("\n" + section)
+	return nil
+}
+
+// describeCommits formats commits for a prompt, with their bodies
+// indented below the subject, since breaking changes are often only noted
+// in a footer
+func describeCommits(commits []git.CommitInfo) []string {
+	lines := make([]string, 0, len(commits))
+	for _, c := range commits {
+		line := c.Hash + " " + c.Message
+		if c.Body != "" {
+			line += "\n    " + strings.ReplaceAll(c.Body, "\n", "\n    ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
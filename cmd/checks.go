@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+const (
+	// checksPollInterval is how often the PR's checks are polled
+	checksPollInterval = 10 * time.Second
+
+	// checksGracePeriod is how long to wait for the first check to be
+	// reported before assuming the repository has no CI
+	checksGracePeriod = 2 * time.Minute
+)
+
+// watchChecks polls the CI checks of a PR until they have all finished,
+// printing the summary whenever it changes. It returns an error if any
+// check failed.
+func watchChecks(remote *remoteForge, number int) error {
+	ui.ShowInfo(i18n.T("Waiting for CI checks (Ctrl-C to stop)..."))
+
+	start := time.Now()
+	var last github.ChecksSummary
+
+	for {
+		checks, err := remote.Forge.ListChecks(remote.Base.Owner, remote.Base.Name, number)
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to get checks: %w"), err)
+		}
+
+		if len(checks) == 0 {
+			if time.Since(start) > checksGracePeriod {
+				ui.ShowInfo(i18n.T("No checks were reported for this PR."))
+				return nil
+			}
+			time.Sleep(checksPollInterval)
+			continue
+		}
+
+		summary := github.Summarize(checks)
+		if summary != last {
+			ui.ShowInfo(fmt.Sprintf(i18n.T("Checks: %s"), summary))
+			last = summary
+		}
+
+		if summary.Done() {
+			return reportChecks(checks, summary)
+		}
+
+		time.Sleep(checksPollInterval)
+	}
+}
+
+// reportChecks prints the failed checks and returns an error if there are any
+func reportChecks(checks []github.Check, summary github.ChecksSummary) error {
+	if summary.Failed == 0 {
+		ui.ShowSuccess(fmt.Sprintf(i18n.T("All %d check(s) passed"), summary.Passed))
+		return nil
+	}
+
+	fmt.Println(i18n.T("\nFailed checks:"))
+	for _, check := range checks {
+		if check.State != github.CheckFailed {
+			continue
+		}
+		if check.URL != "" {
+			fmt.Printf("  - %s: %s\n", check.Name, check.URL)
+		} else {
+			fmt.Printf("  - %s\n", check.Name)
+		}
+	}
+
+	return fmt.Errorf(i18n.T("%d check(s) failed"), summary.Failed)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var cherryPickCmd = &cobra.Command{
+	Use:   "cherry-pick <commit>",
+	Short: "Cherry-pick a commit with a message adapted to the current branch",
+	Long: `Applies the changes of a commit to the current branch like git cherry-pick,
+and generates a message for the new commit from the original message and
+the changes as applied, adapted to the branch (e.g. noting a backport onto
+a release branch). The message ends with the same "(cherry picked from
+commit ...)" line as git cherry-pick -x, and the original author is kept.
+
+If the commit conflicts, the conflicts are left to resolve, e.g. with
+vibe resolve, and you commit the result yourself.
+
+Requirements:
+- Must be in a git repository, without staged changes
+- The git binary must be installed
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCherryPick,
+}
+
+func init() {
+	rootCmd.AddCommand(cherryPickCmd)
+}
+
+func runCherryPick(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	branch, err := repo.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get current branch: %w"), err)
+	}
+
+	hasStaged, err := repo.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+	}
+	if hasStaged {
+		return errors.New(i18n.T(`staged changes would be mixed into the cherry-picked commit
+
+Commit or unstage them first.`))
+	}
+
+	original, err := repo.CherryPick(args[0])
+	var conflict *git.ConflictError
+	if errors.As(err, &conflict) {
+		return fmt.Errorf(i18n.T(`cherry-picking %s %s stopped on %w
+
+Resolve them, then commit the result:
+  vibe resolve
+  vibe commit
+
+Or drop the picked changes:
+  git reset --merge`), original.Hash, original.Message, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	hasStaged, err = repo.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+	}
+	if !hasStaged {
+		return withExitCode(ExitNoChanges, fmt.Errorf(i18n.T("the changes of %s are already on %s, nothing to commit"), original.Hash, branch))
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+	}
+
+	recent, err := repo.RecentCommits(5)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to list recent commits: %w"), err)
+	}
+	subjects := make([]string, 0, len(recent))
+	for _, c := range recent {
+		subjects = append(subjects, c.Hash+" "+c.Message)
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	diff = prepareDiff(diff, cfg, report)
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Picked %s %s onto '%s'"), original.Hash, original.Message, branch))
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	template, _ := repo.CommitTemplate()
+	stop := ui.ShowSpinner(fmt.Sprintf(i18n.T("Adapting the message to %s"), branch))
+	message, err := llmClient.GenerateCherryPickMessage(strings.TrimSpace(original.Message+"\n\n"+original.Body), branch, subjects, diff, llm.CommitOptions{Template: template})
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T(`failed to generate message: %w
+
+The picked changes are still staged. Commit them with 'vibe commit', or drop them with 'git reset --merge'.`), err)
+	}
+
+	result, err := ui.ConfirmCommit(cherryPickMessage(message, original.FullHash), false)
+	if err != nil {
+		return err
+	}
+	if result.Action == ui.ActionCancel {
+		ui.ShowInfo(i18n.T(`Commit cancelled. The picked changes are still staged.
+
+Commit them with 'vibe commit', or drop them with 'git reset --merge'.`))
+		return errCancelled
+	}
+
+	author := &git.Author{Name: original.Author, Email: original.Email, When: original.When}
+	hash, err := repo.Commit(result.Message, git.CommitOptions{Signoff: cfg.Commit.Signoff, Author: author})
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create commit: %w"), err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Committed: %s"), hash))
+	return nil
+}
+
+// cherryPickMessage adds the "(cherry picked from commit ...)" line of git
+// cherry-pick -x to message, unless it is already there
+func cherryPickMessage(message, hash string) string {
+	line := fmt.Sprintf("(cherry picked from commit %s)", hash)
+	if strings.Contains(message, line) {
+		return message
+	}
+	return message + "\n\n" + line
+}
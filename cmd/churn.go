@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	churnSince    string
+	churnTop      int
+	churnMinLines int
+	churnExplain  bool
+)
+
+var churnCmd = &cobra.Command{
+	Use:   "churn",
+	Short: "Find the most changed files and refactor candidates",
+	Long: `Walks the history of the current branch over a period and lists the files
+changed by the most commits, with their added and deleted lines and current
+size. Files that change often and are large are flagged as refactor
+candidates.
+
+  vibe churn                   # the last 12 weeks
+  vibe churn --since 1w -n 20  # the top 20 files of the last week
+  vibe churn --explain         # with AI commentary on the hotspots
+
+Merge commits are skipped, and files excluded by paths.exclude in the
+config and files that no longer exist are left out.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set for --explain`,
+	Args: cobra.NoArgs,
+	RunE: runChurn,
+}
+
+func init() {
+	churnCmd.Flags().StringVarP(&churnSince, "since", "s", "12w", "Period to analyze, e.g. 4w, 90d or 2024-05-01")
+	churnCmd.Flags().IntVarP(&churnTop, "top", "n", 10, "Number of files to list")
+	churnCmd.Flags().IntVar(&churnMinLines, "min-lines", 300, "Lines from which an often changed file is a refactor candidate")
+	churnCmd.Flags().BoolVarP(&churnExplain, "explain", "e", false, "Have AI comment on the most changed files")
+	rootCmd.AddCommand(churnCmd)
+}
+
+func runChurn(cmd *cobra.Command, args []string) error {
+	if churnExplain {
+		if err := checkOpenAIKey(); err != nil {
+			return err
+		}
+	}
+	if churnTop < 1 {
+		return errors.New(i18n.T("--top must be at least 1"))
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	since, err := git.ParseSince(churnSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Walking history since %s..."), since.Format("Mon Jan 2, 2006")))
+
+	files, walked, err := repo.Churn(since, cfg.Paths.Exclude)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to analyze history: %w"), err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf(i18n.T(`no changed files since %s
+
+Look further back:
+  vibe churn --since 1y`), since.Format("Mon Jan 2, 2006"))
+	}
+
+	shown := files[:min(len(files), churnTop)]
+	lines := make([]string, 0, len(shown))
+	width := len(i18n.T("File"))
+	for _, f := range shown {
+		width = max(width, len(f.Path))
+	}
+
+	fmt.Printf(i18n.T("\nMost changed files (%d commit(s) walked):\n"), walked)
+	fmt.Printf("  %-*s  %7s  %15s  %6s\n", width, i18n.T("File"), i18n.T("Commits"), i18n.T("Lines changed"), i18n.T("Size"))
+	for _, f := range shown {
+		size := "-"
+		if f.Lines > 0 {
+			size = fmt.Sprintf("%d", f.Lines)
+		}
+		flag := ""
+		if f.Hotspot(churnMinLines) {
+			flag = i18n.T("  refactor candidate")
+		}
+		fmt.Printf("  %-*s  %7d  %15s  %6s%s\n", width, f.Path, f.Commits, fmt.Sprintf("+%d/-%d", f.Added, f.Deleted), size, flag)
+
+		lines = append(lines, fmt.Sprintf("%s: %d commits, +%d/-%d lines, %s lines long%s", f.Path, f.Commits, f.Added, f.Deleted, size, flag))
+	}
+	if rest := len(files) - len(shown); rest > 0 {
+		fmt.Printf(i18n.T("  ... and %d more\n"), rest)
+	}
+
+	if !churnExplain {
+		return nil
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Looking for refactor candidates"))
+	commentary, err := llmClient.ExplainChurn(lines)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to explain churn: %w"), err)
+	}
+
+	fmt.Printf("\n%s\n", commentary)
+	return nil
+}
@@ -1,113 +1,560 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/user/vibe/internal/cache"
+	"github.com/user/vibe/internal/config"
 	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
 	"github.com/user/vibe/internal/llm"
 	"github.com/user/vibe/internal/ui"
 )
 
+var (
+	commitAll    bool
+	commitSelect bool
+	commitPatch  bool
+	commitSplit  bool
+
+	commitAllowEmpty bool
+	commitMessage    string
+	commitSignoff    bool
+	commitNoVerify   bool
+	commitPrint      bool
+	commitJSONOutput bool
+
+	// commitOut collects the --json result
+	commitOut *commitJSON
+)
+
 var commitCmd = &cobra.Command{
 	Use:   "commit",
 	Short: "Generate an AI commit message for staged changes",
 	Long: `Analyzes your staged changes and generates a meaningful commit message using AI.
 
 The command will:
-1. Check for staged changes in your git repository
+1. Check for staged changes in your git repository (with --all, stage
+   modified and deleted tracked files first; with --select, pick the
+   files to commit from a list; with --patch, pick individual hunks)
 2. Generate a diff of the staged changes
-3. Use OpenAI to generate a commit message
+3. Use OpenAI to generate a commit message (with --split, propose a
+   series of commits for unrelated changes instead)
 4. Show you the message for review
 5. Allow you to accept, edit, or cancel
 6. Create the commit if accepted
 
+With --message, the given message is used as is and nothing is generated.
+With --print, only the generated message is written to stdout and nothing
+is committed, e.g. to pipe it into other tools:
+
+  vibe commit --print | git commit -F -
+
+With --json, the message is committed without asking and the result is
+written to stdout as JSON (message, commit hash, token usage, notes), for
+scripts, editors and bots. Combined with --print, nothing is committed.
+
+With --allow-empty, a commit is created even when nothing is staged, e.g.
+to trigger CI.
+
 Requirements:
 - Must be in a git repository
-- Must have staged changes (git add)
-- OPENAI_API_KEY environment variable must be set`,
+- Must have staged changes (git add), unless --allow-empty is used
+- OPENAI_API_KEY environment variable must be set, unless --message is used`,
 	RunE: runCommit,
 }
 
 func init() {
+	commitCmd.Flags().BoolVarP(&commitAll, "all", "a", false, "Stage all modified and deleted tracked files first, like git commit -a")
+	commitCmd.Flags().BoolVar(&commitSelect, "select", false, "Choose the files to commit from a list of changed files")
+	commitCmd.Flags().BoolVarP(&commitPatch, "patch", "p", false, "Choose the hunks to stage, like git add -p")
+	commitCmd.Flags().BoolVar(&commitSplit, "split", false, "Propose splitting unrelated staged changes into several commits")
+	commitCmd.Flags().BoolVar(&commitAllowEmpty, "allow-empty", false, "Create the commit even when nothing is staged")
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Use this commit message instead of generating one")
+	commitCmd.Flags().BoolVarP(&commitSignoff, "signoff", "s", false, "Add a Signed-off-by trailer for the author")
+	commitCmd.Flags().BoolVarP(&commitNoVerify, "no-verify", "n", false, "Skip the pre-commit and commit-msg hooks")
+	commitCmd.Flags().BoolVar(&commitPrint, "print", false, "Only write the generated message to stdout, don't commit")
+	commitCmd.MarkFlagsMutuallyExclusive("all", "select", "patch", "print")
+	commitCmd.MarkFlagsMutuallyExclusive("message", "split")
+	commitCmd.MarkFlagsMutuallyExclusive("message", "print")
+	commitCmd.MarkFlagsMutuallyExclusive("split", "print")
+	commitCmd.MarkFlagsMutuallyExclusive("allow-empty", "print")
+	commitCmd.Flags().BoolVar(&commitJSONOutput, "json", false, "Commit without asking and write the result as JSON to stdout")
+	commitCmd.MarkFlagsMutuallyExclusive("select", "json")
+	commitCmd.MarkFlagsMutuallyExclusive("patch", "json")
+	commitCmd.MarkFlagsMutuallyExclusive("split", "json")
+
 	rootCmd.AddCommand(commitCmd)
 }
 
-func runCommit(cmd *cobra.Command, args []string) error {
-	// Check for OpenAI API key
-	if err := checkOpenAIKey(); err != nil {
-		return err
+func runCommit(cmd *cobra.Command, args []string) (err error) {
+	if commitJSONOutput {
+		beginJSON()
+		commitOut = &commitJSON{}
+		defer func() {
+			if err == nil {
+				commitOut.collect()
+				err = writeJSON(commitOut)
+			}
+		}()
+	}
+
+	// Without a terminal to confirm the message on, only print it
+	if !ui.Interactive() && !assumeYes && commitMessage == "" && !commitJSONOutput &&
+		!commitAll && !commitSelect && !commitPatch && !commitSplit && !commitAllowEmpty {
+		fmt.Fprintln(os.Stderr, i18n.T("Not a terminal, printing the message instead of committing (use --yes to commit)"))
+		commitPrint = true
+	}
+
+	// Check for OpenAI API key, unless there's nothing to generate
+	if commitMessage == "" {
+		if err := checkOpenAIKey(); err != nil {
+			return err
+		}
 	}
 
 	// Open the git repository
 	repo, err := git.OpenCurrent()
 	if err != nil {
-		return fmt.Errorf("not a git repository: %w", err)
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("signoff") {
+		commitSignoff = cfg.Commit.Signoff
+	}
+
+	if commitAll {
+		staged, err := repo.StageTracked()
+		if err != nil {
+			return err
+		}
+		if len(staged) > 0 {
+			ui.ShowInfo(fmt.Sprintf(i18n.T("Staged %d tracked file(s)"), len(staged)))
+		}
+	}
+
+	if commitSelect {
+		if err := selectFiles(repo); err != nil {
+			return err
+		}
+	}
+
+	if commitPatch {
+		if err := stageHunks(repo); err != nil {
+			return err
+		}
 	}
 
 	// Check for staged changes
 	hasStaged, err := repo.HasStagedChanges()
 	if err != nil {
-		return fmt.Errorf("failed to check staged changes: %w", err)
+		return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
 	}
 
 	if !hasStaged {
-		return fmt.Errorf(`no staged changes found
+		if commitAllowEmpty {
+			return commitEmpty(repo, cfg)
+		}
+		return withExitCode(ExitNoChanges, errors.New(i18n.T(`no staged changes found
 
 To stage changes, use:
   git add <file>       # Stage specific file
   git add .            # Stage all changes
-  git add -p           # Stage interactively`)
+  git add -p           # Stage interactively
+  vibe commit --all    # Stage all tracked changes and commit
+
+To commit without changes, e.g. to trigger CI:
+  vibe commit --allow-empty -m "Trigger CI"`)))
+	}
+
+	if commitPrint {
+		return printCommitMessage(repo, cfg)
 	}
 
 	// Get the diff
-	ui.ShowInfo("Analyzing staged changes...")
+	ui.ShowInfo(i18n.T("Analyzing staged changes..."))
 
 	diff, err := repo.GetStagedDiff()
 	if err != nil {
-		return fmt.Errorf("failed to get staged diff: %w", err)
+		return fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
 	}
 
 	if diff == "" {
-		return fmt.Errorf("no diff content found for staged changes")
+		return withExitCode(ExitNoChanges, errors.New(i18n.T("no diff content found for staged changes")))
+	}
+
+	report := newCommitReport()
+	defer report.Show()
+
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return withExitCode(ExitNoChanges, errors.New(i18n.T("all staged changes are excluded by path rules")))
+	}
+	showDiffStat(diff)
+
+	if commitMessage != "" {
+		return createCommit(repo, commitMessage)
 	}
 
 	// Create OpenAI client and generate commit message
-	llmClient, err := llm.NewClient()
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	if commitOut != nil {
+		commitOut.client = llmClient
+	}
+
+	if commitSplit {
+		done, err := splitCommit(repo, llmClient, diff)
+		if err != nil || done {
+			return err
+		}
+	}
+
+	template, _ := repo.CommitTemplate()
+	message, cached, err := generateCommitMessage(llmClient, diff, template)
 	if err != nil {
-		return fmt.Errorf("failed to create AI client: %w", err)
+		return fmt.Errorf(i18n.T("failed to generate commit message: %w"), err)
+	}
+	if cached {
+		report.Add("commit message reused from cache (generated earlier for the same changes)")
+		if commitOut != nil {
+			commitOut.Cached = true
+		}
 	}
 
-	message, err := llmClient.GenerateCommitMessage(diff)
+	// Regenerating skips the cache, the cached message is the one rejected
+	return confirmCommit(repo, message, func(previous, hint string) (string, error) {
+		stop := ui.ShowSpinner(i18n.T("Regenerating commit message"))
+		defer stop()
+		return llmClient.GenerateCommitMessage(diff, llm.CommitOptions{Template: template, Previous: previous, Hint: hint})
+	})
+}
+
+// printCommitMessage writes only the message generated for the staged
+// changes to stdout, so it can be piped into git commit -F - or other
+// tools. Notes on the run go to stderr.
+func printCommitMessage(repo *git.Repository, cfg *config.Config) error {
+	diff, err := repo.GetStagedDiff()
 	if err != nil {
-		return fmt.Errorf("failed to generate commit message: %w", err)
+		return fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+	}
+
+	report := ui.NewReport()
+	defer report.ShowTo(os.Stderr)
+	if commitOut != nil {
+		commitOut.report = report
 	}
 
-	// Show the message and get user confirmation
-	result, err := ui.ConfirmCommit(message)
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return withExitCode(ExitNoChanges, errors.New(i18n.T("all staged changes are excluded by path rules")))
+	}
+
+	llmClient, err := llm.NewClient(cfg)
 	if err != nil {
-		return fmt.Errorf("prompt failed: %w", err)
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
 	}
 
-	switch result.Action {
-	case ui.ActionCancel:
-		ui.ShowInfo("Commit cancelled.")
+	template, _ := repo.CommitTemplate()
+	message, cached, err := generateCommitMessage(llmClient, diff, template)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate commit message: %w"), err)
+	}
+	if cached {
+		report.Add("commit message reused from cache (generated earlier for the same changes)")
+	}
+
+	if commitOut != nil {
+		commitOut.Message, commitOut.Cached, commitOut.client = message, cached, llmClient
 		return nil
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// commitEmpty creates a commit without changes, with the --message or a
+// generated message describing the empty commit
+func commitEmpty(repo *git.Repository, cfg *config.Config) error {
+	report := newCommitReport()
+	defer report.Show()
+
+	if commitMessage != "" {
+		return createCommit(repo, commitMessage)
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	if commitOut != nil {
+		commitOut.client = llmClient
+	}
+
+	// The branch and recent history are all there is to go on
+	branch, _ := repo.GetCurrentBranch()
+	recent, _ := repo.RecentCommits(5)
+	subjects := make([]string, len(recent))
+	for i, c := range recent {
+		subjects[i] = c.Message
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Generating a message for the empty commit"))
+	message, err := llmClient.GenerateEmptyCommitMessage(branch, subjects)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate commit message: %w"), err)
+	}
+	return confirmCommit(repo, message, nil)
+}
 
-	case ui.ActionAccept, ui.ActionEdit:
-		// Create the commit
-		hash, err := repo.Commit(result.Message)
+// confirmCommit shows the generated message for review and creates the
+// commit once accepted. regenerate, if set, generates another message from
+// the rejected one and the user's hint.
+func confirmCommit(repo *git.Repository, message string, regenerate func(previous, hint string) (string, error)) error {
+	// Scripts using --json can't answer prompts
+	if commitOut != nil {
+		return createCommit(repo, message)
+	}
+
+	for {
+		result, err := ui.ConfirmCommit(message, regenerate != nil)
 		if err != nil {
-			return fmt.Errorf("failed to create commit: %w", err)
+			return fmt.Errorf(i18n.T("prompt failed: %w"), err)
+		}
+
+		switch result.Action {
+		case ui.ActionCancel:
+			ui.ShowInfo(i18n.T("Commit cancelled."))
+			return errCancelled
+
+		case ui.ActionAccept, ui.ActionEdit:
+			return createCommit(repo, result.Message)
+
+		case ui.ActionRegenerate:
+			if message, err = regenerate(message, result.Hint); err != nil {
+				return fmt.Errorf(i18n.T("failed to generate commit message: %w"), err)
+			}
+
+		default:
+			return fmt.Errorf("unexpected action")
+		}
+	}
+}
+
+// newCommitReport creates the report of a run that commits, which is part
+// of the --json output, noting the hooks skipped with --no-verify
+func newCommitReport() *ui.Report {
+	report := ui.NewReport()
+	if commitOut != nil {
+		commitOut.report = report
+	}
+	if commitNoVerify {
+		report.Add("hooks skipped (--no-verify)")
+	}
+	return report
+}
+
+// createCommit commits with message using the commit flags
+func createCommit(repo *git.Repository, message string) error {
+	hash, err := repo.Commit(message, commitOptions())
+	if err != nil {
+		return commitError(err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Committed: %s"), hash))
+	fmt.Fprintf(os.Stdout, "\n  %s\n", message)
+	if commitOut != nil {
+		commitOut.Message, commitOut.Hash = message, hash
+	}
+	return nil
+}
+
+// commitOptions returns the commit options set by the commit flags
+func commitOptions() git.CommitOptions {
+	return git.CommitOptions{AllowEmpty: commitAllowEmpty, Signoff: commitSignoff, NoVerify: commitNoVerify}
+}
+
+// commitError explains a failed commit, pointing out --no-verify when a
+// hook rejected it
+func commitError(err error) error {
+	var hookErr *git.HookError
+	if errors.As(err, &hookErr) {
+		return fmt.Errorf(i18n.T(`failed to create commit: %w
+
+Fix the problems reported above, or skip the hooks with:
+  vibe commit --no-verify`), err)
+	}
+	return fmt.Errorf(i18n.T("failed to create commit: %w"), err)
+}
+
+// selectFiles lets the user pick the files to commit from all changed
+// files, staging the picked ones and unstaging the others. Files that are
+// already staged keep their staged content, so partial staging (git add -p)
+// is preserved.
+func selectFiles(repo *git.Repository) error {
+	files, err := repo.ChangedFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	choices := make([]ui.FileChoice, 0, len(files))
+	for _, f := range files {
+		choices = append(choices, ui.FileChoice{Path: f.Path, Status: f.Status, Selected: f.Staged()})
+	}
+
+	selected, err := ui.SelectFiles(choices)
+	if err != nil {
+		return err
+	}
+
+	picked := make(map[string]bool, len(selected))
+	for _, path := range selected {
+		picked[path] = true
+	}
+
+	var stage, unstage []string
+	for _, f := range files {
+		switch {
+		case picked[f.Path] && !f.Staged():
+			stage = append(stage, f.Path)
+		case !picked[f.Path] && f.Staged():
+			unstage = append(unstage, f.Path)
 		}
+	}
+
+	if err := repo.UnstageFiles(unstage); err != nil {
+		return err
+	}
+	return repo.StageFiles(stage)
+}
 
-		ui.ShowSuccess(fmt.Sprintf("Committed: %s", hash))
-		fmt.Fprintf(os.Stdout, "\n  %s\n", result.Message)
+// stageHunks walks the hunks of the unstaged changes and stages the ones
+// the user accepts, so the message is generated for exactly those
+func stageHunks(repo *git.Repository) error {
+	files, err := repo.UnstagedHunks()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		ui.ShowInfo(i18n.T("No unstaged changes to tracked files"))
 		return nil
+	}
+
+	staged := 0
+	for _, f := range files {
+		accepted := make([]bool, len(f.Hunks))
+		done := false
+	hunks:
+		for i, h := range f.Hunks {
+			action, err := ui.ConfirmHunk(f.Path, h.String(), i+1, len(f.Hunks))
+			if err != nil {
+				return err
+			}
+
+			switch action {
+			case ui.HunkStage:
+				accepted[i] = true
+			case ui.HunkStageFile:
+				for j := i; j < len(accepted); j++ {
+					accepted[j] = true
+				}
+				break hunks
+			case ui.HunkSkipFile:
+				break hunks
+			case ui.HunkDone:
+				done = true
+				break hunks
+			}
+		}
+
+		for _, ok := range accepted {
+			if ok {
+				staged++
+			}
+		}
+		if err := repo.StageHunks(f, accepted); err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+	}
 
-	default:
-		return fmt.Errorf("unexpected action")
+	if staged > 0 {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Staged %d hunk(s)"), staged))
 	}
+	return nil
+}
+
+// generateCommitMessage returns a commit message for diff following the
+// commit template, if any, reusing a previously generated message (e.g.
+// one prepared by vibe watch) when the same diff was already sent to the
+// same model. The returned bool reports whether the message came from the
+// cache.
+func generateCommitMessage(llmClient *llm.Client, diff, template string) (string, bool, error) {
+	opts := llm.CommitOptions{Template: template}
+
+	msgCache, err := cache.New()
+	if err != nil {
+		// The cache is an optimization only, fall back to generating
+		stop := ui.ShowSpinner(i18n.T("Generating commit message"))
+		message, err := llmClient.GenerateCommitMessage(diff, opts)
+		stop()
+		return message, false, err
+	}
+
+	key := cache.Key("commit", llmClient.Model(), llmClient.CommitPrompt(), template, diff)
+	if message, ok := msgCache.Get(key); ok {
+		return message, true, nil
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Generating commit message"))
+	message, err := llmClient.GenerateCommitMessage(diff, opts)
+	stop()
+	if err != nil {
+		return "", false, err
+	}
+
+	_ = msgCache.Put(key, message)
+	return message, false, nil
+}
+
+// prepareDiff applies the configured path rules to diff and records any
+// content that won't reach the model in report
+func prepareDiff(diff string, cfg *config.Config, report *ui.Report) string {
+	diff, excluded := git.FilterDiff(diff, cfg.Paths.Exclude)
+	if len(excluded) > 0 {
+		report.Add("%d file(s) excluded by path rules: %s", len(excluded), strings.Join(excluded, ", "))
+	}
+
+	// Lockfile diffs are noise to the model, the manifest changes say more
+	diff, deps := git.SummarizeDependencies(diff)
+	if len(deps.Lockfiles) > 0 {
+		report.Add("lockfile changes left out of the prompt: %s", strings.Join(deps.Lockfiles, ", "))
+	}
+	if !deps.Empty() {
+		diff = deps.String() + "\n\n" + diff
+	}
+
+	if _, truncated := llm.TruncateDiff(diff); truncated {
+		report.Add("diff truncated to fit the model's limit, the message is based on partial changes")
+	}
+
+	return diff
 }
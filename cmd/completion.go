@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+)
+
+// completeBranches completes --base with the local branches and the
+// branches of the remote to push to, which may be picked with --remote
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, repo, err := currentRepoConfig()
+	if err != nil || repo == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repo.UseRemote(cfg.PR.Remote)
+	if flag := cmd.Flags().Lookup("remote"); flag != nil {
+		repo.UseRemote(flag.Value.String())
+	}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matching(branches, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRemotes completes --remote with the configured remotes
+func completeRemotes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	remotes, err := repo.RemoteNames()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matching(remotes, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeModels completes --model with the default model and the models
+// set in the global and repository config files
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	models := []string{llm.DefaultModel}
+
+	var paths []string
+	if path, err := config.GlobalPath(); err == nil {
+		paths = append(paths, path)
+	}
+	if repo, err := git.OpenCurrent(); err == nil {
+		paths = append(paths, config.RepoPath(repo.Root()))
+	}
+	for _, path := range paths {
+		if cfg, err := config.LoadFile(path); err == nil && cfg.Model != "" {
+			models = append(models, cfg.Model)
+		}
+	}
+
+	return matching(models, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigSet completes the setting and, for booleans and the
+// locale, the value of vibe config set
+func completeConfigSet(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		var keys []string
+		for _, s := range config.Settings() {
+			keys = append(keys, s.Key)
+		}
+		return matching(keys, toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		switch args[0] {
+		case "model":
+			return completeModels(cmd, args, toComplete)
+		case "locale":
+			return matching(i18n.Locales(), toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, s := range config.Settings() {
+			if s.Key == args[0] && s.Type == config.TypeBool {
+				return matching([]string{"true", "false"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// matching returns the distinct candidates starting with prefix, sorted
+func matching(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	configImportRepo bool
+	configSetRepo    bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Share and manage vibe configuration",
+	Long: `Manages vibe's configuration.
+
+Settings are read from the global config file (~/.config/vibe/config.yaml)
+and then from .vibe.yaml in the repository root, which overrides the global
+file and can be committed so the whole team shares it.`,
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export the effective configuration as a shareable bundle",
+	Long: `Writes the effective configuration (model, prompts and path rules) as a
+single bundle file, or to stdout when no file is given.
+
+Secrets are never part of a bundle: API keys and tokens stay in the
+environment or the OS keychain.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigExport,
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a configuration bundle",
+	Long: `Reads a bundle created with 'vibe config export' and merges it into the
+global config file, or into the repository's .vibe.yaml with --repo. The
+bundle's settings replace yours, settings it doesn't have are kept, and so
+are your host tokens.
+
+Use '-' to read the bundle from stdin.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigImport,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the config file",
+	Long: `Changes a single setting in the global config file, or in the repository's
+.vibe.yaml with --repo. Keys name nested settings with dots, e.g.:
+
+  vibe config set model gpt-4o-mini
+  vibe config set pr.remote upstream
+  vibe config set pr.sections "Summary, Changes, Testing"
+  vibe config set --repo commit.signoff true
+
+Lists are separated by commas and an empty value clears the setting. The
+file is rewritten, so comments in it are not kept.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigSet,
+	RunE:              runConfigSet,
+}
+
+func init() {
+	configImportCmd.Flags().BoolVar(&configImportRepo, "repo", false, "Write to the repository's .vibe.yaml instead of the global config")
+	configSetCmd.Flags().BoolVar(&configSetRepo, "repo", false, "Change the repository's .vibe.yaml instead of the global config")
+
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+	configCmd.AddCommand(configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// loadConfig loads the global config layered with the config of repo and
+// sets up repo's git backend from it. repo may be nil when running outside
+// a repository.
+func loadConfig(repo *git.Repository) (*config.Config, error) {
+	root := ""
+	if repo != nil {
+		root = repo.Root()
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to load config: %w"), err)
+	}
+	if model != "" {
+		cfg.Model = model
+	}
+
+	if cfg.Locale != "" {
+		if !i18n.Supported(cfg.Locale) {
+			return nil, fmt.Errorf(i18n.T("invalid locale setting %q, available: %s"), cfg.Locale, strings.Join(i18n.Locales(), ", "))
+		}
+		i18n.SetLocale(cfg.Locale)
+	}
+
+	if repo != nil {
+		if err := repo.UseBackend(cfg.Git.Backend); err != nil {
+			return nil, fmt.Errorf(i18n.T("invalid git.backend setting: %w"), err)
+		}
+	}
+
+	ui.SetKeys(ui.Keys{
+		Accept:     cfg.Keys.Accept,
+		Edit:       cfg.Keys.Edit,
+		Regenerate: cfg.Keys.Regenerate,
+		Cancel:     cfg.Keys.Cancel,
+		Up:         cfg.Keys.Up,
+		Down:       cfg.Keys.Down,
+		Stage:      cfg.Keys.Stage,
+		Quit:       cfg.Keys.Quit,
+	})
+	return cfg, nil
+}
+
+// currentRepoConfig loads the config for the repository in the current
+// directory, falling back to the global config outside a repository
+func currentRepoConfig() (*config.Config, *git.Repository, error) {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		repo = nil
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, repo, nil
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	cfg, _, err := currentRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return config.Export(os.Stdout, cfg)
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create bundle: %w"), err)
+	}
+	defer file.Close()
+
+	if err := config.Export(file, cfg); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Exported configuration to %s"), args[0]))
+	return nil
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	var reader io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to open bundle: %w"), err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	target, err := configTarget(configImportRepo)
+	if err != nil {
+		return err
+	}
+
+	// Merge into the file so the settings the bundle doesn't have are kept
+	cfg, err := config.LoadFile(target)
+	if err != nil {
+		return err
+	}
+	if err := config.Import(reader, cfg); err != nil {
+		return err
+	}
+
+	if err := config.Save(target, cfg); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Imported configuration into %s"), target))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	if configSetRepo && strings.HasPrefix(key, "hosts.") && strings.HasSuffix(key, ".token") {
+		return errors.New(i18n.T(`tokens can't be set in .vibe.yaml, which is meant to be committed
+
+Keep tokens in the global config, or set GITHUB_TOKEN or GH_ENTERPRISE_TOKEN`))
+	}
+	if key == "locale" && value != "" && !i18n.Supported(value) {
+		return fmt.Errorf(i18n.T("invalid locale %q, available: %s"), value, strings.Join(i18n.Locales(), ", "))
+	}
+
+	target, err := configTarget(configSetRepo)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadFile(target)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+	if err := config.Save(target, cfg); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Set %s in %s"), key, target))
+	return nil
+}
+
+// configTarget returns the config file to write, the repository's
+// .vibe.yaml when repo is set or else the global config
+func configTarget(repo bool) (string, error) {
+	if !repo {
+		return config.GlobalPath()
+	}
+
+	r, err := git.OpenCurrent()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+	return config.RepoPath(r.Root()), nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	contributorsSince string
+	contributorsTop   int
+)
+
+var contributorsCmd = &cobra.Command{
+	Use:   "contributors",
+	Short: "Show commit statistics per author",
+	Long: `Walks the history of the current branch over a period and shows, for each
+author, the number of commits, the lines added and removed and the number of
+days with commits. Everything is computed from the local history, no GitHub
+access or API key is needed.
+
+  vibe contributors                   # the last 12 weeks
+  vibe contributors --since 1y        # the last year
+  vibe contributors --since all       # the whole history
+
+Authors are told apart by email. Merge commits are skipped, and lines of
+files excluded by paths.exclude in the config are not counted.
+
+Requirements:
+- Must be in a git repository`,
+	Args: cobra.NoArgs,
+	RunE: runContributors,
+}
+
+func init() {
+	contributorsCmd.Flags().StringVarP(&contributorsSince, "since", "s", "12w", "Period to analyze, e.g. 4w, 1y, 2024-05-01 or all")
+	contributorsCmd.Flags().IntVarP(&contributorsTop, "top", "n", 20, "Number of authors to list, 0 for all")
+	rootCmd.AddCommand(contributorsCmd)
+}
+
+func runContributors(cmd *cobra.Command, args []string) error {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	since, err := git.ParseSince(contributorsSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	period := i18n.T("over the whole history")
+	if !since.IsZero() {
+		period = fmt.Sprintf(i18n.T("since %s"), since.Format("Mon Jan 2, 2006"))
+	}
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Analyzing commits %s..."), period))
+
+	contributors, err := repo.Contributors(since, cfg.Paths.Exclude)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to analyze history: %w"), err)
+	}
+	if len(contributors) == 0 {
+		return fmt.Errorf(i18n.T(`no commits %s
+
+Look further back:
+  vibe contributors --since all`), period)
+	}
+
+	shown := contributors
+	if contributorsTop > 0 {
+		shown = contributors[:min(len(contributors), contributorsTop)]
+	}
+	width := len(i18n.T("Author"))
+	for _, c := range shown {
+		width = max(width, len(c.Name))
+	}
+
+	fmt.Printf(i18n.T("\nContributors %s (%d in total):\n"), period, len(contributors))
+	fmt.Printf("  %-*s  %7s  %8s  %8s  %11s  %s\n", width, i18n.T("Author"), i18n.T("Commits"), i18n.T("Added"), i18n.T("Removed"), i18n.T("Active days"), i18n.T("Last commit"))
+	for _, c := range shown {
+		fmt.Printf("  %-*s  %7d  %8s  %8s  %11d  %s\n", width, c.Name, c.Commits,
+			fmt.Sprintf("+%d", c.Added), fmt.Sprintf("-%d", c.Deleted), c.ActiveDays, c.Last.Format("Jan 2, 2006"))
+	}
+	if rest := len(contributors) - len(shown); rest > 0 {
+		fmt.Printf(i18n.T("  ... and %d more\n"), rest)
+	}
+	return nil
+}
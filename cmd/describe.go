@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [commit]",
+	Short: "Explain what a commit changed and why it might matter",
+	Long: `Sends a commit's message and diff to the model and prints a plain-English
+explanation of what it changed and why it might matter. Handy for
+archaeology on old commits.
+
+The commit can be any revision: a hash, tag, branch or HEAD~3. It defaults
+to HEAD. Merge commits are explained by the changes they brought in.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Example: `  vibe describe a1b2c3d
+  vibe describe v1.2.0
+  vibe describe HEAD~3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDescribe,
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	rev := "HEAD"
+	if len(args) > 0 {
+		rev = args[0]
+	}
+
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	commit, err := repo.ShowCommit(rev)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\ncommit %s\nAuthor: %s\nDate:   %s\n\n    %s\n\n", commit.Hash, commit.Author, commit.When.Format("Mon Jan 2 15:04:05 2006 -0700"), commit.Message)
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	diff := prepareDiff(commit.Diff, cfg, report)
+	if diff == "" {
+		diff = "(no file changes)"
+	} else {
+		showDiffStat(diff)
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	message := commit.Message
+	if commit.Body != "" {
+		message += "\n\n" + commit.Body
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Explaining the commit"))
+	explanation, err := llmClient.ExplainCommit(message, diff)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to explain commit: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", explanation))
+	return nil
+}
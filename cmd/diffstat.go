@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+// diffStatFiles is how many of the most changed files are listed
+const diffStatFiles = 5
+
+// showDiffStat prints a summary of the diff about to be sent to the model,
+// so a forgotten or unintended change can be caught before generation
+func showDiffStat(diff string) {
+	stats := git.DiffStat(diff)
+	if len(stats) == 0 {
+		return
+	}
+
+	added, deleted := 0, 0
+	for _, s := range stats {
+		added += s.Added
+		deleted += s.Deleted
+	}
+
+	summary := i18n.T("%d files changed, %s/%s lines")
+	if len(stats) == 1 {
+		summary = i18n.T("%d file changed, %s/%s lines")
+	}
+	lines := []string{fmt.Sprintf(summary, len(stats),
+		ui.Added(fmt.Sprintf("+%d", added)), ui.Removed(fmt.Sprintf("-%d", deleted)))}
+
+	// Most changed first, keeping the diff's order for ties
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Changed() > stats[j].Changed()
+	})
+
+	width := 0
+	for _, s := range stats[:min(len(stats), diffStatFiles)] {
+		width = max(width, len(s.Path))
+	}
+	for _, s := range stats[:min(len(stats), diffStatFiles)] {
+		change := ui.Added(fmt.Sprintf("+%d", s.Added)) + "/" + ui.Removed(fmt.Sprintf("-%d", s.Deleted))
+		if s.SizeOnly {
+			change = i18n.T("(size only)")
+		}
+		lines = append(lines, fmt.Sprintf("  %-*s  %s", width, s.Path, change))
+	}
+	if rest := len(stats) - diffStatFiles; rest > 0 {
+		lines = append(lines, fmt.Sprintf(i18n.T("  ... and %d more"), rest))
+	}
+
+	ui.ShowInfo(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate documentation for packagers",
+	Long: `Generates man pages or a markdown reference of all commands and flags,
+built from the same definitions as --help, for packages to ship.
+
+Set SOURCE_DATE_EPOCH for reproducible dates in man pages.`,
+	Hidden: true,
+}
+
+var docsManCmd = &cobra.Command{
+	Use:   "man <dir>",
+	Short: "Write a man page for each command into dir",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDocsMan,
+}
+
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown <dir>",
+	Short: "Write a markdown page for each command into dir",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDocsMarkdown,
+}
+
+func init() {
+	docsCmd.AddCommand(docsManCmd)
+	docsCmd.AddCommand(docsMarkdownCmd)
+	rootCmd.AddCommand(docsCmd)
+}
+
+func runDocsMan(cmd *cobra.Command, args []string) error {
+	dir, err := docsDir(args[0])
+	if err != nil {
+		return err
+	}
+
+	header := &doc.GenManHeader{
+		Title:   "VIBE",
+		Section: "1",
+		Source:  "vibe " + Version,
+		Manual:  "Vibe Manual",
+	}
+	if err := doc.GenManTree(rootCmd, header, dir); err != nil {
+		return fmt.Errorf(i18n.T("failed to generate man pages: %w"), err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Wrote man pages to %s"), dir))
+	return nil
+}
+
+func runDocsMarkdown(cmd *cobra.Command, args []string) error {
+	dir, err := docsDir(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := doc.GenMarkdownTree(rootCmd, dir); err != nil {
+		return fmt.Errorf(i18n.T("failed to generate markdown reference: %w"), err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Wrote markdown reference to %s"), dir))
+	return nil
+}
+
+// docsDir creates dir for generated docs. The generation date is left out
+// so the same sources always give the same files.
+func docsDir(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf(i18n.T("failed to create %s: %w"), dir, err)
+	}
+	rootCmd.DisableAutoGenTag = true
+	return dir, nil
+}
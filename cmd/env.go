@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+
+	"github.com/user/vibe/internal/git"
+)
+
+// loadEnvFiles loads environment variables from .env files, in order of
+// precedence:
+// 1. .env in the current directory
+// 2. .env in the repository root
+// 3. Global ~/.config/vibe/.env
+// Variables that are already set in the environment are never overridden,
+// so the real environment always wins over any file.
+func loadEnvFiles() {
+	for _, path := range envFilePaths() {
+		// godotenv.Load fails if the file is missing, which is fine
+		_ = godotenv.Load(path)
+	}
+}
+
+// envFilePaths returns the candidate .env file locations, most specific first
+func envFilePaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+
+	add := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		add(filepath.Join(cwd, ".env"))
+
+		if root, err := git.FindRoot(cwd); err == nil {
+			add(filepath.Join(root, ".env"))
+		}
+	}
+
+	if configDir, err := os.UserConfigDir(); err == nil {
+		add(filepath.Join(configDir, "vibe", ".env"))
+	}
+
+	return paths
+}
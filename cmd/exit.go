@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+// Exit codes, so that scripts can tell outcomes apart without parsing
+// error messages
+const (
+	ExitOK        = 0
+	ExitError     = 1
+	ExitUsage     = 2
+	ExitCancelled = 3
+	ExitNoChanges = 4
+	ExitAuth      = 5
+	ExitAPI       = 6
+	ExitGit       = 7
+)
+
+// errCancelled is returned when the user declines a prompt, after the
+// cancellation has been shown
+var errCancelled = errors.New("cancelled")
+
+// started is set once the command line is parsed and validated, errors
+// before are usage errors
+var started bool
+
+// exitError is an error with an explicit exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode makes err exit vibe with code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// cancelled reports whether err is a cancellation by the user, which
+// needs no error message
+func cancelled(err error) bool {
+	return errors.Is(err, errCancelled) || errors.Is(err, huh.ErrUserAborted)
+}
+
+// ExitCode returns the exit code for the error a command returned
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if cancelled(err) {
+		return ExitCancelled
+	}
+
+	var openAIErr *llm.APIError
+	var githubErr *github.APIError
+	switch {
+	case errors.As(err, &openAIErr):
+		if openAIErr.Auth {
+			return ExitAuth
+		}
+		return ExitAPI
+	case errors.As(err, &githubErr):
+		if githubErr.Auth {
+			return ExitAuth
+		}
+		return ExitAPI
+	case git.IsGitError(err):
+		return ExitGit
+	case errors.Is(err, ui.ErrNoTerminal):
+		return ExitUsage
+	case !started:
+		return ExitUsage
+	}
+	return ExitError
+}
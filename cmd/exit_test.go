@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	gogit "github.com/go-git/go-git/v5"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		started bool
+		want    int
+	}{
+		{name: "Success", err: nil, started: true, want: ExitOK},
+		{name: "Other error", err: errors.New("boom"), started: true, want: ExitError},
+		{name: "Usage error", err: errors.New("unknown flag"), started: false, want: ExitUsage},
+		{name: "No terminal", err: fmt.Errorf("prompt: %w", ui.ErrNoTerminal), started: true, want: ExitUsage},
+		{name: "Cancelled", err: errCancelled, started: true, want: ExitCancelled},
+		{name: "Aborted prompt", err: fmt.Errorf("prompt failed: %w", huh.ErrUserAborted), started: true, want: ExitCancelled},
+		{name: "Explicit code", err: withExitCode(ExitNoChanges, errors.New("no staged changes found")), started: true, want: ExitNoChanges},
+		{name: "Wrapped explicit code", err: fmt.Errorf("commit: %w", withExitCode(ExitAuth, errors.New("no key"))), started: true, want: ExitAuth},
+		{name: "Explicit code before start", err: withExitCode(ExitNoChanges, errors.New("nothing")), started: false, want: ExitNoChanges},
+		{name: "OpenAI key rejected", err: &llm.APIError{Auth: true}, started: true, want: ExitAuth},
+		{name: "OpenAI error", err: &llm.APIError{}, started: true, want: ExitAPI},
+		{name: "GitHub token rejected", err: &github.APIError{Auth: true}, started: true, want: ExitAuth},
+		{name: "GitHub error", err: &github.APIError{}, started: true, want: ExitAPI},
+		{name: "Not a repository", err: fmt.Errorf("not a git repository: %w", gogit.ErrRepositoryNotExists), started: true, want: ExitGit},
+		{name: "Hook rejected", err: &git.HookError{Hook: "pre-commit", Err: errors.New("exit status 1")}, started: true, want: ExitGit},
+		{name: "Conflicts", err: fmt.Errorf("cherry-pick: %w", &git.ConflictError{Files: []string{"a.go"}}), started: true, want: ExitGit},
+	}
+
+	defer func(s bool) { started = s }(started)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started = tt.started
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
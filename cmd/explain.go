@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var explainUnstaged bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain your staged or unstaged changes in prose",
+	Long: `Summarizes your staged changes (or with --unstaged, the changes not staged
+yet) in prose, pointing out unrelated changes and leftovers, so you can
+decide how to commit them. Nothing is committed.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().BoolVarP(&explainUnstaged, "unstaged", "u", false, "Explain the changes not staged yet, including untracked files")
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	var diff string
+	if explainUnstaged {
+		if diff, err = repo.WorktreeDiff(true); err != nil {
+			return fmt.Errorf(i18n.T("failed to get unstaged changes: %w"), err)
+		}
+		if strings.TrimSpace(diff) == "" {
+			return withExitCode(ExitNoChanges, errors.New(i18n.T("no unstaged changes found")))
+		}
+	} else {
+		if diff, err = repo.GetStagedDiff(); err != nil {
+			return fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+		}
+		if strings.TrimSpace(diff) == "" {
+			return withExitCode(ExitNoChanges, errors.New(i18n.T(`no staged changes found
+
+To explain the changes you haven't staged yet, use:
+  vibe explain --unstaged`)))
+		}
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return withExitCode(ExitNoChanges, errors.New(i18n.T("all changes are excluded by path rules")))
+	}
+	showDiffStat(diff)
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Explaining your changes"))
+	explanation, err := llmClient.ExplainChanges(diff)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to explain changes: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", explanation))
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+// fixupPrefix marks a commit for git rebase --autosquash to fold into the
+// commit whose subject follows it
+const fixupPrefix = "fixup! "
+
+var fixupCount int
+
+var fixupCmd = &cobra.Command{
+	Use:   "fixup",
+	Short: "Create a fixup! commit for a recent commit from the staged changes",
+	Long: `Lets you pick one of the recent commits and commits the staged changes as a
+"fixup! <subject>" commit for it, ready to be folded in with:
+
+  git rebase -i --autosquash <commit>~
+
+No message is generated, so no API key is needed.`,
+	RunE: runFixup,
+}
+
+func init() {
+	fixupCmd.Flags().IntVarP(&fixupCount, "count", "n", 15, "Number of recent commits to choose from")
+	rootCmd.AddCommand(fixupCmd)
+}
+
+func runFixup(cmd *cobra.Command, args []string) error {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	hasStaged, err := repo.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+	}
+	if !hasStaged {
+		return withExitCode(ExitNoChanges, errors.New(i18n.T(`no staged changes found
+
+Stage the changes that belong to an earlier commit first:
+  git add <file>       # Stage specific file
+  git add -p           # Stage interactively`)))
+	}
+
+	commits, err := repo.RecentCommits(fixupCount)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to list recent commits: %w"), err)
+	}
+	if len(commits) == 0 {
+		return errors.New(i18n.T("there are no commits to fix up yet"))
+	}
+
+	labels := make([]string, len(commits))
+	for i, c := range commits {
+		labels[i] = c.Hash + " " + c.Message
+	}
+	choice, err := ui.SelectCommit(i18n.T("Which commit do the staged changes fix?"), labels)
+	if err != nil {
+		return err
+	}
+	target := commits[choice]
+
+	message := fixupMessage(target.Message)
+	hash, err := repo.Commit(message, git.CommitOptions{Signoff: cfg.Commit.Signoff})
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create commit: %w"), err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Committed: %s"), hash))
+	fmt.Printf(i18n.T("\n  %s\n\nFold it in with: git rebase -i --autosquash %s~\n"), message, target.Hash)
+	return nil
+}
+
+// fixupMessage returns the fixup! message for a commit subject. Fixing a
+// fixup targets the commit it fixes, so the prefix is never repeated.
+func fixupMessage(subject string) string {
+	for strings.HasPrefix(subject, fixupPrefix) {
+		subject = strings.TrimPrefix(subject, fixupPrefix)
+	}
+	return fixupPrefix + subject
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+// remoteForge is a forge connection for the repository behind a remote
+type remoteForge struct {
+	Forge github.Forge
+
+	// Repo is the repository behind the remote, where branches are pushed
+	Repo *github.RepoInfo
+
+	// Base is the repository pull requests are opened against: Repo itself,
+	// or the upstream repository when Repo is a fork
+	Base *github.RepoInfo
+
+	Token string
+}
+
+// openForge resolves the forge serving remoteURL from the host registry,
+// authenticates with the host's token and parses the repository
+func openForge(cfg *config.Config, remoteURL string) (*remoteForge, error) {
+	if err := registerForgeHosts(cfg); err != nil {
+		return nil, err
+	}
+
+	host, err := github.RemoteHost(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to parse remote: %w"), err)
+	}
+
+	token, err := githubToken(cfg, host)
+	if err != nil {
+		return nil, err
+	}
+
+	forge, err := github.NewForge(host, token)
+	if err != nil {
+		return nil, err
+	}
+
+	repoInfo, err := forge.ParseRemote(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to parse remote: %w"), err)
+	}
+
+	return &remoteForge{
+		Forge: forge,
+		Repo:  repoInfo,
+		Base:  repoInfo,
+		Token: token,
+	}, nil
+}
+
+// openBaseForge connects to the forge of the push remote, resolving the
+// upstream repository PRs are opened against
+func openBaseForge(repo *git.Repository, cfg *config.Config) (*remoteForge, error) {
+	remoteURL, err := repo.GetRemoteURL()
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to get remote URL: %w"), err)
+	}
+
+	remote, err := openForge(cfg, remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveUpstream(repo, remote, ""); err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to detect upstream repository: %w"), err)
+	}
+	return remote, nil
+}
+
+// resolveUpstream points remote.Base at the repository PRs should target.
+// In order, it uses the remote named by upstreamRemote, a remote called
+// "upstream", or the fork parent reported by the forge.
+func resolveUpstream(repo *git.Repository, remote *remoteForge, upstreamRemote string) error {
+	if upstreamRemote == "" && repo.HasRemote("upstream") {
+		upstreamRemote = "upstream"
+	}
+
+	if upstreamRemote != "" {
+		url, err := repo.GetRemoteURLFor(upstreamRemote)
+		if err != nil {
+			return err
+		}
+
+		base, err := remote.Forge.ParseRemote(url)
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to parse %s remote: %w"), upstreamRemote, err)
+		}
+		remote.Base = base
+		return nil
+	}
+
+	parent, err := remote.Forge.ForkParent(remote.Repo.Owner, remote.Repo.Name)
+	if err != nil {
+		return err
+	}
+	if parent != nil {
+		remote.Base = parent
+	}
+	return nil
+}
+
+// isFork reports whether PRs are opened from a fork into another repository
+func (r *remoteForge) isFork() bool {
+	return r.Base.Owner != r.Repo.Owner || r.Base.Name != r.Repo.Name
+}
+
+// showQuota prints the forge's remaining API quota in verbose mode
+func (r *remoteForge) showQuota() {
+	if !verbose {
+		return
+	}
+
+	limited, ok := r.Forge.(github.RateLimited)
+	if !ok {
+		return
+	}
+	if quota := limited.RateLimit(); quota != nil {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("API quota for %s: %s"), r.Repo.Host, quota))
+	}
+}
+
+// registerForgeHosts maps the hosts from the config to their forge backends
+func registerForgeHosts(cfg *config.Config) error {
+	for _, host := range cfg.HostNames() {
+		forge := cfg.Hosts[host].Forge
+		if forge == "" {
+			forge = github.ForgeGitHub
+		}
+		if err := github.RegisterHost(host, forge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+// installableHooks are the git hooks vibe can be installed as
+var installableHooks = []string{"prepare-commit-msg", "pre-push"}
+
+// conventionExempt are subject prefixes of commits git writes itself or
+// that are squashed away, which commit_pattern doesn't apply to
+var conventionExempt = []string{"Merge ", "Revert ", "fixup! ", "squash! ", "amend! "}
+
+var hookForce bool
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Run vibe from git hooks",
+	Long: `Installs vibe as a git hook, so plain 'git commit' gets AI-generated
+messages without changing anyone's workflow.
+
+  vibe hook install            # install the prepare-commit-msg and pre-push hooks
+  vibe hook install pre-push   # install only some of them
+  vibe hook uninstall          # remove them again
+
+The pre-push hook rejects pushes whose commits add likely secrets or whose
+subjects don't match hooks.commit_pattern in the config.
+
+The hooks honor core.hooksPath, so they work with husky and similar setups.`,
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:       "install [hook...]",
+	Short:     "Install vibe's git hooks in the current repository",
+	Args:      cobra.OnlyValidArgs,
+	ValidArgs: installableHooks,
+	RunE:      runHookInstall,
+}
+
+var hookUninstallCmd = &cobra.Command{
+	Use:       "uninstall [hook...]",
+	Short:     "Remove vibe's git hooks from the current repository",
+	Args:      cobra.OnlyValidArgs,
+	ValidArgs: installableHooks,
+	RunE:      runHookUninstall,
+}
+
+var hookPrepareCommitMsgCmd = &cobra.Command{
+	Use:   "prepare-commit-msg <file> [source] [commit]",
+	Short: "Write a generated message into git's commit message file",
+	Long: `Run by the prepare-commit-msg hook. Writes a message generated for the
+staged changes into <file>, where git opens it in your editor.
+
+Nothing is generated when the message comes from elsewhere (-m, merges,
+squashes, amends). Failures never block the commit, git's usual empty
+message is left in place instead.`,
+	Args:   cobra.RangeArgs(1, 3),
+	Hidden: true,
+	RunE:   runHookPrepareCommitMsg,
+}
+
+var hookPrePushCmd = &cobra.Command{
+	Use:   "pre-push <remote> [url]",
+	Short: "Check outgoing commits before they are pushed",
+	Long: `Run by the pre-push hook with git's list of pushed refs on stdin. Rejects
+the push when an outgoing commit adds a likely secret (unless
+hooks.block_secrets is false) or its subject doesn't match
+hooks.commit_pattern.`,
+	Args:         cobra.RangeArgs(1, 2),
+	Hidden:       true,
+	SilenceUsage: true,
+	RunE:         runHookPrePush,
+}
+
+func init() {
+	hookInstallCmd.Flags().BoolVar(&hookForce, "force", false, "Replace existing hooks that were not installed by vibe")
+
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookUninstallCmd)
+	hookCmd.AddCommand(hookPrepareCommitMsgCmd)
+	hookCmd.AddCommand(hookPrePushCmd)
+	rootCmd.AddCommand(hookCmd)
+}
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	for _, name := range hookNames(args) {
+		path, err := repo.InstallHook(name, git.HookScript(name), hookForce)
+		if err != nil {
+			return err
+		}
+		ui.ShowSuccess(fmt.Sprintf(i18n.T("Installed %s hook: %s"), name, path))
+	}
+	return nil
+}
+
+func runHookUninstall(cmd *cobra.Command, args []string) error {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	for _, name := range hookNames(args) {
+		removed, err := repo.UninstallHook(name)
+		if err != nil {
+			return err
+		}
+		if removed {
+			ui.ShowSuccess(fmt.Sprintf(i18n.T("Removed %s hook"), name))
+		}
+	}
+	return nil
+}
+
+// hookNames returns the hooks named on the command line, or all of them
+func hookNames(args []string) []string {
+	if len(args) == 0 {
+		return installableHooks
+	}
+	return args
+}
+
+func runHookPrePush(cmd *cobra.Command, args []string) error {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	var pattern *regexp.Regexp
+	if cfg.Hooks.CommitPattern != "" {
+		if pattern, err = regexp.Compile(cfg.Hooks.CommitPattern); err != nil {
+			return fmt.Errorf(i18n.T("invalid hooks.commit_pattern setting: %w"), err)
+		}
+	}
+	blockSecrets := cfg.Hooks.ShouldBlockSecrets()
+	if pattern == nil && !blockSecrets {
+		return nil
+	}
+
+	// Each line is "<local ref> <local sha> <remote ref> <remote sha>"
+	var problems []string
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+
+		commits, err := repo.OutgoingCommits(args[0], fields[1], fields[3])
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to list outgoing commits: %w"), err)
+		}
+
+		for _, c := range commits {
+			short := c.Hash[:7]
+			if pattern != nil && !conventionExemptSubject(c.Subject) && !pattern.MatchString(c.Subject) {
+				problems = append(problems, fmt.Sprintf(i18n.T("%s %q doesn't match hooks.commit_pattern"), short, c.Subject))
+			}
+			if blockSecrets {
+				for _, f := range git.FindSecrets(c.Patch) {
+					problems = append(problems, fmt.Sprintf(i18n.T("%s adds a likely %s in %s:%d"), short, f.Kind, f.Path, f.Line))
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf(i18n.T("failed to read pushed refs: %w"), err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf(i18n.T(`push rejected:
+  %s
+
+Fix the commits (e.g. with git rebase -i) and push again, or push anyway with:
+  git push --no-verify`), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// conventionExemptSubject reports whether commit_pattern doesn't apply to
+// a commit subject
+func conventionExemptSubject(subject string) bool {
+	for _, prefix := range conventionExempt {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func runHookPrepareCommitMsg(cmd *cobra.Command, args []string) error {
+	file := args[0]
+	source := ""
+	if len(args) > 1 {
+		source = args[1]
+	}
+
+	// Only fill in messages the user would otherwise write from scratch
+	if source != "" && source != "template" {
+		return nil
+	}
+
+	if err := prepareCommitMsg(file); err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("vibe: no message generated: %v\n"), err)
+	}
+	return nil
+}
+
+// prepareCommitMsg generates a message for the staged changes and writes
+// it into the commit message file. With a commit template in the file, the
+// message follows it. git's comment lines are kept below the message.
+func prepareCommitMsg(file string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	template, comments := splitCommitMsgFile(string(existing))
+	if template == "" {
+		template, _ = repo.CommitTemplate()
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		return err
+	}
+	diff, _ = git.FilterDiff(diff, cfg.Paths.Exclude)
+	if diff == "" {
+		return nil
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	message, _, err := generateCommitMessage(llmClient, diff, template)
+	if err != nil {
+		return err
+	}
+
+	content := message + "\n"
+	if comments != "" {
+		content += "\n" + comments
+	}
+	return os.WriteFile(file, []byte(content), 0o644)
+}
+
+// splitCommitMsgFile splits the commit message file git prepared into the
+// template it holds, if any, and its "#" comment lines
+func splitCommitMsgFile(content string) (template, comments string) {
+	var text, comment []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			comment = append(comment, line)
+		} else {
+			text = append(text, line)
+		}
+	}
+
+	if len(comment) > 0 {
+		comments = strings.Join(comment, "\n") + "\n"
+	}
+	if strings.TrimSpace(strings.Join(text, "\n")) == "" {
+		return "", comments
+	}
+	// Comment lines guide the model as part of the template too
+	return strings.TrimSpace(content), comments
+}
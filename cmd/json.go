@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+// jsonStdout is the real stdout while --json sends everything else that is
+// printed to stderr
+var jsonStdout *os.File
+
+// beginJSON redirects what commands print to stderr, keeping stdout for
+// the JSON result written by writeJSON
+func beginJSON() {
+	jsonStdout = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// writeJSON writes v to the real stdout as indented JSON
+func writeJSON(v any) error {
+	enc := json.NewEncoder(jsonStdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// jsonRun holds what the --json results of all commands share: the tokens
+// used and the notes on the run, collected from the client and report the
+// command ends up using
+type jsonRun struct {
+	Usage *llm.Usage `json:"usage,omitempty"`
+	Notes []string   `json:"notes,omitempty"`
+
+	client *llm.Client
+	report *ui.Report
+}
+
+// collect fills in the usage and notes once the command is done
+func (r *jsonRun) collect() {
+	if r.client != nil {
+		usage := r.client.Usage()
+		r.Usage = &usage
+	}
+	if r.report != nil {
+		r.Notes = r.report.Notes()
+	}
+}
+
+// commitJSON is the --json result of vibe commit
+type commitJSON struct {
+	Message string `json:"message"`
+	Hash    string `json:"hash,omitempty"`
+	Cached  bool   `json:"cached,omitempty"`
+	jsonRun
+}
+
+// prJSON is the --json result of vibe pr
+type prJSON struct {
+	Number      int      `json:"number,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Updated     bool     `json:"updated,omitempty"`
+	jsonRun
+}
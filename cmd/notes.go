@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var notesAnnouncement bool
+
+var notesCmd = &cobra.Command{
+	Use:   "notes <range>",
+	Short: "Summarize a range of commits into release notes",
+	Long: `Summarizes the commits of any range into polished release notes, or
+with --announcement, into a short announcement paragraph. Nothing is tagged
+or changed, the notes are printed.
+
+The range works like git log's: v1.2.0..v1.3.0 are the commits of v1.3.0
+since v1.2.0, and v1.2.0 alone the commits since v1.2.0 up to HEAD.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Example: `  vibe notes v1.2.0..v1.3.0
+  vibe notes v1.3.0 --announcement
+  vibe notes main..feature/search`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNotes,
+}
+
+func init() {
+	notesCmd.Flags().BoolVarP(&notesAnnouncement, "announcement", "a", false, "Write an announcement paragraph instead of release notes")
+	rootCmd.AddCommand(notesCmd)
+}
+
+func runNotes(cmd *cobra.Command, args []string) error {
+	rangeSpec := args[0]
+
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	commits, err := repo.CommitsInRange(rangeSpec)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf(i18n.T("there are no commits in %s"), rangeSpec)
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Summarizing %d commit(s) in %s..."), len(commits), rangeSpec))
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Writing release notes"))
+	notes, err := llmClient.GenerateReleaseNotes(rangeSpec, describeCommits(commits), notesAnnouncement)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate release notes: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", notes))
+	return nil
+}
@@ -2,12 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/user/vibe/internal/config"
 	"github.com/user/vibe/internal/git"
 	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
 	"github.com/user/vibe/internal/llm"
 	"github.com/user/vibe/internal/ui"
 )
@@ -18,167 +23,812 @@ var prCmd = &cobra.Command{
 	Long: `Creates a GitHub Pull Request with an AI-generated title and description.
 
 The command will:
-1. Detect your current branch and the base branch (main/master)
-2. Get the commits ahead of the base branch
-3. Generate a diff of all changes
-4. Use OpenAI to generate a PR title and description
-5. Show you the PR details for review
-6. Allow you to accept, edit, or cancel
-7. Push your branch if needed
-8. Create the PR on GitHub
+1. Detect your current branch and the base branch (main/master, or --base)
+2. Fetch the base branch so the comparison reflects the remote
+3. Get the commits ahead of the base branch
+4. Generate a diff of all changes
+5. Use OpenAI to generate a PR title and description
+6. Show you the PR details for review
+7. Allow you to accept, edit, or cancel
+8. Push your branch if needed
+9. Create the PR on GitHub (or, with --update, edit the branch's open PR)
+
+If the branch already has an open PR, vibe shows it before generating
+anything and offers to update it instead.
 
 Requirements:
 - Must be in a git repository with a GitHub remote
 - Must be on a feature branch (not main/master)
 - Must have commits ahead of the base branch
 - OPENAI_API_KEY environment variable must be set
-- GITHUB_TOKEN environment variable must be set`,
+- GITHUB_TOKEN environment variable must be set (or a gh CLI login, or a
+  per-host token in the config for GitHub Enterprise remotes)`,
+	Args: cobra.NoArgs,
 	RunE: runPR,
 }
 
+var (
+	prLabels        []string
+	prSuggestLabels bool
+	prReviewers     []string
+	prTeamReviewers []string
+	prAssignees     []string
+	prMilestone     string
+	prProject       string
+	prUpstream      string
+	prNoUpstream    bool
+	prBase          string
+	prUpdate        bool
+	prWeb           bool
+	prCopy          bool
+	prWatch         bool
+	prAutoMerge     string
+	prRemote        string
+	prForceLease    bool
+	prTags          bool
+	prTestPlan      bool
+	prRisk          bool
+	prDiagram       bool
+	prJSONOutput    bool
+
+	// prOut collects the --json result
+	prOut *prJSON
+
+	// prDraft is a PR reviewed in vibe tui, created without generating or
+	// confirming it again
+	prDraft *llm.PRContent
+)
+
+// diagramMinModules is the number of changed modules from which changes
+// are large enough to be worth a diagram
+const diagramMinModules = 3
+
 func init() {
+	prCmd.Flags().StringVarP(&prBase, "base", "B", "", "Branch to open the PR against (default: main or master)")
+	prCmd.Flags().BoolVarP(&prUpdate, "update", "u", false, "Regenerate the title and description of the branch's existing open PR")
+	prCmd.Flags().BoolVarP(&prWeb, "web", "w", false, "Open the PR in the browser when done (default from pr.web in config)")
+	prCmd.Flags().BoolVarP(&prCopy, "copy", "c", false, "Copy the PR URL to the clipboard when done (default from pr.copy in config)")
+	prCmd.Flags().BoolVar(&prWatch, "watch", false, "Wait for the PR's CI checks and fail if any of them fail")
+	prCmd.Flags().StringVar(&prAutoMerge, "auto-merge", "", "Enable auto-merge with the given method, written --auto-merge=merge or --auto-merge=rebase (default: squash)")
+	prCmd.Flags().Lookup("auto-merge").NoOptDefVal = "squash"
+	prCmd.Flags().StringSliceVarP(&prLabels, "label", "l", nil, "Add a label to the PR (repeatable or comma-separated)")
+	prCmd.Flags().BoolVar(&prSuggestLabels, "suggest-labels", false, "Let AI suggest labels from the repository's existing labels")
+	prCmd.Flags().StringSliceVarP(&prReviewers, "reviewer", "r", nil, "Request a review from a user (repeatable or comma-separated)")
+	prCmd.Flags().StringSliceVar(&prTeamReviewers, "team-reviewer", nil, "Request a review from a team, e.g. org/team (repeatable or comma-separated)")
+	prCmd.Flags().StringSliceVarP(&prAssignees, "assignee", "a", nil, "Assign a user to the PR, use @me for yourself (repeatable or comma-separated)")
+	prCmd.Flags().StringVarP(&prMilestone, "milestone", "m", "", "Set the PR's milestone (fuzzy matched against open milestones)")
+	prCmd.Flags().StringVarP(&prProject, "project", "p", "", "Add the PR to a project (fuzzy matched against the repository's projects)")
+	prCmd.Flags().StringVar(&prRemote, "remote", "", "Remote to push the branch to (default from pr.remote in config, or origin)")
+	prCmd.Flags().BoolVar(&prForceLease, "force-with-lease", false, "Overwrite the remote branch after a rebase, unless someone else pushed to it")
+	prCmd.Flags().BoolVar(&prTags, "tags", false, "Push all local tags along with the branch")
+	prCmd.Flags().BoolVar(&prTestPlan, "test-plan", false, "Add a \"How to test\" section with steps and commands to the description (default from pr.test_plan in config)")
+	prCmd.Flags().BoolVar(&prRisk, "risk", false, "Add a low/medium/high risk rating with its reasons to the description (default from pr.risk in config)")
+	prCmd.Flags().BoolVar(&prDiagram, "diagram", false, "Add a Mermaid diagram of the modules touched by larger changes to the description (default from pr.diagram in config)")
+	prCmd.Flags().BoolVar(&prJSONOutput, "json", false, "Create the PR without asking and write the result as JSON to stdout")
+	prCmd.Flags().StringVar(&prUpstream, "upstream", "", "Remote of the repository to open the PR against (default: 'upstream' remote or the fork parent)")
+	prCmd.Flags().BoolVar(&prNoUpstream, "no-upstream", false, "Open the PR against the push remote's repository even if it is a fork")
+	_ = prCmd.RegisterFlagCompletionFunc("base", completeBranches)
+	_ = prCmd.RegisterFlagCompletionFunc("remote", completeRemotes)
+	_ = prCmd.RegisterFlagCompletionFunc("upstream", completeRemotes)
 	rootCmd.AddCommand(prCmd)
 }
 
-func runPR(cmd *cobra.Command, args []string) error {
+func runPR(cmd *cobra.Command, args []string) (err error) {
+	if prJSONOutput {
+		beginJSON()
+		prOut = &prJSON{}
+		defer func() {
+			if err == nil {
+				prOut.collect()
+				err = writeJSON(prOut)
+			}
+		}()
+	}
+
+	// The PR is confirmed before it is created, fail before any request
+	// when that can't happen
+	if !prJSONOutput && !assumeYes && !ui.Interactive() {
+		return fmt.Errorf(i18n.T(`%w, so the PR can't be confirmed
+
+To create it without confirming, use:
+  vibe pr --yes
+  vibe pr --json       # also writes the result as JSON to stdout`), ui.ErrNoTerminal)
+	}
+
 	// Check for required environment variables
 	if err := checkOpenAIKey(); err != nil {
 		return err
 	}
-	if err := checkGitHubToken(); err != nil {
-		return err
-	}
 
 	// Open the git repository
 	repo, err := git.OpenCurrent()
 	if err != nil {
-		return fmt.Errorf("not a git repository: %w", err)
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	if prAutoMerge != "" && !github.ValidMergeMethod(prAutoMerge) {
+		return fmt.Errorf(i18n.T("invalid --auto-merge method %q (use %s)"), prAutoMerge, strings.Join(github.MergeMethods, ", "))
+	}
+
+	if !cmd.Flags().Changed("web") {
+		prWeb = cfg.PR.Web
+	}
+	if !cmd.Flags().Changed("copy") {
+		prCopy = cfg.PR.Copy
+	}
+	if !cmd.Flags().Changed("test-plan") {
+		prTestPlan = cfg.PR.TestPlan
+	}
+	if !cmd.Flags().Changed("risk") {
+		prRisk = cfg.PR.Risk
+	}
+	if !cmd.Flags().Changed("diagram") {
+		prDiagram = cfg.PR.Diagram
+	}
+	if prRemote == "" {
+		prRemote = cfg.PR.Remote
+	}
+	repo.UseRemote(prRemote)
+	if !repo.HasRemote(repo.Remote()) {
+		return fmt.Errorf(i18n.T(`remote '%s' not found
+
+Pick an existing remote with --remote, or add it:
+  git remote add %s <url>`), repo.Remote(), repo.Remote())
 	}
 
 	// Get current branch
 	currentBranch, err := repo.GetCurrentBranch()
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return fmt.Errorf(i18n.T("failed to get current branch: %w"), err)
 	}
 
-	// Get default branch (main or master)
-	baseBranch, err := repo.GetDefaultBranch()
-	if err != nil {
-		return fmt.Errorf("failed to detect base branch: %w", err)
+	// Use the requested base branch, or detect the default (main or master)
+	baseBranch := prBase
+	if baseBranch == "" {
+		baseBranch, err = repo.GetDefaultBranch()
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to detect base branch: %w"), err)
+		}
 	}
 
 	// Check we're not on the base branch
 	if currentBranch == baseBranch {
-		return fmt.Errorf(`cannot create PR from %s branch
+		return fmt.Errorf(i18n.T(`cannot create PR from %s branch
 
 Create a feature branch first:
-  git checkout -b feature/my-feature`, baseBranch)
+  git checkout -b feature/my-feature`), baseBranch)
 	}
 
-	ui.ShowInfo(fmt.Sprintf("Analyzing branch '%s' against '%s'...", currentBranch, baseBranch))
+	report := ui.NewReport()
+	defer report.Show()
+	if prOut != nil {
+		prOut.report = report
+	}
 
-	// Get commits ahead of base
-	commits, err := repo.GetCommitsAhead(baseBranch)
+	// Get remote URL and parse owner/repo
+	remoteURL, err := repo.GetRemoteURL()
 	if err != nil {
-		return fmt.Errorf("failed to get commits: %w", err)
+		return fmt.Errorf(i18n.T("failed to get remote URL: %w"), err)
 	}
 
-	if len(commits) == 0 {
-		return fmt.Errorf(`no commits ahead of %s
+	remote, err := openForge(cfg, remoteURL)
+	if err != nil {
+		return err
+	}
+	defer remote.showQuota()
 
-Make some commits first, then run vibe pr again.`, baseBranch)
+	if !prNoUpstream {
+		if err := resolveUpstream(repo, remote, prUpstream); err != nil {
+			return fmt.Errorf(i18n.T("failed to detect upstream repository: %w"), err)
+		}
+	}
+	if remote.isFork() {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Opening PR against upstream %s/%s"), remote.Base.Owner, remote.Base.Name))
 	}
 
-	ui.ShowInfo(fmt.Sprintf("Found %d commit(s) ahead of %s", len(commits), baseBranch))
+	head := github.QualifiedHead(remote.Repo, remote.Base, currentBranch)
 
-	// Format commits for the prompt
-	var commitLines []string
-	for _, c := range commits {
-		commitLines = append(commitLines, fmt.Sprintf("%s %s", c.Hash, c.Message))
+	// Compare against the remote's current base, a stale local copy gives
+	// wrong commit lists and bloated diffs
+	if err := repo.FetchBranch(remote.Token, baseBranch); err != nil {
+		report.Add("could not fetch %s from %s, comparing against the local copy: %v", baseBranch, repo.Remote(), err)
+	}
+
+	// Shallow clones may lack the commit the branch forked from, if they
+	// can't be deepened the forge compares the branches instead
+	compareOnForge := false
+	if err := repo.DeepenForBase(baseBranch); err != nil {
+		report.Add("%v; comparing on %s instead", err, remote.Base.Host)
+		compareOnForge = true
 	}
-	commitsText := strings.Join(commitLines, "\n")
 
-	// Get the diff from base branch
-	diff, err := repo.GetDiffFromBase(baseBranch)
+	if !compareOnForge {
+		proceed, err := syncWithBase(repo, baseBranch)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			ui.ShowInfo(i18n.T("PR creation cancelled."))
+			return errCancelled
+		}
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Analyzing branch '%s' against '%s'..."), currentBranch, baseBranch))
+
+	commits, diff, err := branchChanges(repo, remote, baseBranch, head, compareOnForge)
 	if err != nil {
-		return fmt.Errorf("failed to get diff: %w", err)
+		return err
+	}
+
+	if len(commits) == 0 {
+		return withExitCode(ExitNoChanges, fmt.Errorf(i18n.T(`no commits ahead of %s
+
+Make some commits first, then run vibe pr again.`), baseBranch))
 	}
 
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Found %d commit(s) ahead of %s"), len(commits), baseBranch))
+
 	if diff == "" {
-		return fmt.Errorf("no changes found compared to %s", baseBranch)
+		return withExitCode(ExitNoChanges, fmt.Errorf(i18n.T("no changes found compared to %s"), baseBranch))
 	}
 
-	// Get remote URL and parse owner/repo
-	remoteURL, err := repo.GetRemoteURL()
-	if err != nil {
-		return fmt.Errorf("failed to get remote URL: %w", err)
+	filtered, _ := git.FilterDiff(diff, cfg.Paths.Exclude)
+	_, deps := git.SummarizeDependencies(filtered)
+
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return fmt.Errorf(i18n.T("all changes compared to %s are excluded by path rules"), baseBranch)
 	}
+	showDiffStat(diff)
 
-	repoInfo, err := github.ParseRemoteURL(remoteURL)
+	// Look up an open PR for the branch before spending an API call on
+	// generation, creating a second one would fail anyway
+	existing, err := remote.Forge.FindOpenPR(remote.Base.Owner, remote.Base.Name, head)
 	if err != nil {
-		return fmt.Errorf("failed to parse GitHub remote: %w", err)
+		return fmt.Errorf(i18n.T("failed to find existing PR: %w"), err)
+	}
+
+	switch {
+	case prUpdate && existing == nil:
+		return fmt.Errorf(i18n.T(`no open PR found for branch '%s'
+
+Run vibe pr without --update to create one.`), currentBranch)
+
+	case !prUpdate && existing != nil && prOut != nil:
+		// Without --update, scripts get the existing PR as is
+		prOut.Number, prOut.URL, prOut.Title = existing.Number, existing.URL, existing.Title
+		return finishPR(remote, existing.Number, existing.URL, report)
+
+	case !prUpdate && existing != nil:
+		update, err := ui.ConfirmUpdateExisting(existing.Number, existing.Title, existing.URL)
+		if err != nil {
+			return fmt.Errorf(i18n.T("prompt failed: %w"), err)
+		}
+		if !update {
+			return finishPR(remote, existing.Number, existing.URL, report)
+		}
+	}
+
+	if existing != nil {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Updating PR #%d: %s"), existing.Number, existing.Title))
 	}
 
 	// Create OpenAI client and generate PR content
-	llmClient, err := llm.NewClient()
+	llmClient, err := llm.NewClient(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create AI client: %w", err)
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+	if prOut != nil {
+		prOut.client = llmClient
 	}
 
-	prContent, err := llmClient.GeneratePRContent(commitsText, diff)
-	if err != nil {
-		return fmt.Errorf("failed to generate PR content: %w", err)
+	// A PR reviewed in vibe tui is used as is
+	prContent := prDraft
+	if prContent == nil {
+		prContent, err = generatePRContent(repo, cfg, llmClient, currentBranch, commits, diff, deps, "", "", report)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Show the PR and get user confirmation
-	result, err := ui.ConfirmPR(prContent.Title, prContent.Description)
-	if err != nil {
-		return fmt.Errorf("prompt failed: %w", err)
+	labels := prLabels
+	if prSuggestLabels {
+		suggested, err := suggestLabels(remote, llmClient, commitsText(commits), diff)
+		if err != nil {
+			report.Add("label suggestion skipped: %v", err)
+		}
+		labels = mergeLabels(labels, suggested)
+	}
+
+	// Show the PR and get user confirmation, scripts using --json can't
+	// answer prompts
+	result := &ui.PRResult{Action: ui.ActionAccept, Title: prContent.Title, Description: prContent.Description, Labels: labels}
+	for prOut == nil && prDraft == nil {
+		if result, err = ui.ConfirmPR(prContent.Title, prContent.Description, labels); err != nil {
+			return fmt.Errorf(i18n.T("prompt failed: %w"), err)
+		}
+		if result.Action != ui.ActionRegenerate {
+			break
+		}
+
+		previous := prContent.Title + "\n\n" + prContent.Description
+		if prContent, err = generatePRContent(repo, cfg, llmClient, currentBranch, commits, diff, deps, previous, result.Hint, report); err != nil {
+			return err
+		}
 	}
 
 	switch result.Action {
 	case ui.ActionCancel:
-		ui.ShowInfo("PR creation cancelled.")
-		return nil
+		ui.ShowInfo(i18n.T("PR creation cancelled."))
+		return errCancelled
 
 	case ui.ActionAccept, ui.ActionEdit:
-		// Check if we need to push
-		needsPush, err := repo.NeedsPush()
-		if err != nil {
-			return fmt.Errorf("failed to check push status: %w", err)
+		if err := pushBranch(repo, remote); err != nil {
+			return err
+		}
+
+		if existing != nil {
+			return updatePR(remote, existing, result, report)
 		}
+		return createPR(remote, baseBranch, head, result, report)
 
-		if needsPush {
-			ui.ShowInfo("Pushing branch to origin...")
-			if err := repo.Push(); err != nil {
-				return fmt.Errorf("failed to push branch: %w", err)
+	default:
+		return fmt.Errorf("unexpected action")
+	}
+}
+
+// generatePRContent generates the title and description of a PR for the
+// commits and diff of branch, and adds the diagram, dependency, screenshot
+// and closing issue sections. previous and hint regenerate a rejected PR.
+func generatePRContent(repo *git.Repository, cfg *config.Config, llmClient *llm.Client, branch string, commits []git.CommitInfo, diff string, deps *git.DependencySummary, previous, hint string, report *ui.Report) (*llm.PRContent, error) {
+	prOpts := llm.PROptions{Previous: previous, Hint: hint}
+	if len(cfg.PR.Sections) > 0 {
+		ui.ShowInfo(i18n.T("Using the sections from pr.sections in config"))
+		prOpts.Sections = cfg.PR.Sections
+	} else if template, ok := repo.PRTemplate(); ok {
+		ui.ShowInfo(i18n.T("Using the repository's pull request template"))
+		prOpts.Template = template
+	}
+	if prTestPlan {
+		prOpts.TestPlan = true
+		for _, stat := range git.DiffStat(diff) {
+			if git.IsTestFile(stat.Path) {
+				prOpts.TestFiles = append(prOpts.TestFiles, stat.Path)
 			}
 		}
+	}
+	if prRisk {
+		prOpts.Risk = true
+		prOpts.HotFiles = git.MatchingFiles(diff, cfg.PR.HotPaths)
+	}
+
+	commitLines := commitsText(commits)
+	stop := ui.ShowSpinner(i18n.T("Generating the PR title and description"))
+	prContent, err := llmClient.GeneratePRContent(commitLines, diff, prOpts)
+	stop()
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to generate PR content: %w"), err)
+	}
+	if prDiagram {
+		prContent.Description = appendDiagram(prContent.Description, llmClient, commitLines, diff, report)
+	}
+	prContent.Description = appendDependencies(prContent.Description, deps)
+	uiFiles := git.MatchingFiles(diff, cfg.PR.UIPatterns())
+	if len(uiFiles) > 0 {
+		prContent.Description = addScreenshotPlaceholders(prContent.Description, uiFiles)
+		report.Add("UI files changed, attach screenshots to the PR before requesting review")
+	}
+
+	if cfg.PR.ShouldLinkIssues() {
+		messages := make([]string, 0, len(commits))
+		for _, c := range commits {
+			messages = append(messages, c.Message)
+		}
+		issues := github.DetectIssues(branch, messages)
+		// The issue the branch was started for with vibe start
+		if linked := repo.LinkedIssue(branch); linked > 0 && !slices.Contains(issues, linked) {
+			issues = append(issues, linked)
+		}
+		prContent.Description = github.AppendClosingRefs(prContent.Description, cfg.PR.ClosingKeyword, issues)
+	}
+	return prContent, nil
+}
+
+// commitsText formats commits for the prompts, one "hash subject" per line
+func commitsText(commits []git.CommitInfo) string {
+	lines := make([]string, 0, len(commits))
+	for _, c := range commits {
+		lines = append(lines, fmt.Sprintf("%s %s", c.Hash, c.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// syncWithBase offers to rebase onto or merge the base branch when it has
+// commits the current branch doesn't have. It returns false if the user
+// cancelled.
+func syncWithBase(repo *git.Repository, baseBranch string) (bool, error) {
+	div, err := repo.CheckDivergence(baseBranch)
+	if err != nil {
+		return false, fmt.Errorf(i18n.T("failed to compare with %s: %w"), baseBranch, err)
+	}
+	if !div.Diverged() {
+		return true, nil
+	}
+	if prOut != nil {
+		// Rebasing or merging is left to the script
+		prOut.report.Add("%s has %d commit(s) this branch doesn't have", div.BaseRef, div.Behind)
+		return true, nil
+	}
+
+	action, err := ui.ConfirmSync(div.BaseRef, div.Behind, div.Conflicts)
+	if err != nil {
+		return false, err
+	}
+
+	switch action {
+	case ui.SyncCancel:
+		return false, nil
+
+	case ui.SyncRebase:
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Rebasing onto %s..."), div.BaseRef))
+		if err := repo.Rebase(div.BaseRef); err != nil {
+			return false, fmt.Errorf(i18n.T("%w\n\nResolve the conflicts with git rebase %s, then run vibe pr again"), err, div.BaseRef)
+		}
+		// The pushed branch, if any, no longer matches the rebased one
+		prForceLease = true
+
+	case ui.SyncMerge:
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Merging %s..."), div.BaseRef))
+		if err := repo.Merge(div.BaseRef); err != nil {
+			return false, fmt.Errorf(i18n.T("%w\n\nResolve the conflicts with git merge %s, then run vibe pr again"), err, div.BaseRef)
+		}
+	}
+
+	return true, nil
+}
+
+// pushBranch pushes the current branch to the push remote if it has
+// unpushed commits, and the local tags with --tags
+func pushBranch(repo *git.Repository, remote *remoteForge) error {
+	needsPush, err := repo.NeedsPush()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to check push status: %w"), err)
+	}
+
+	opts := git.PushOptions{
+		ForceWithLease: prForceLease,
+		AllTags:        prTags,
+	}
+
+	switch {
+	case needsPush:
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Pushing branch to %s..."), repo.Remote()))
+		if err := repo.Push(remote.Token, opts); err != nil {
+			return fmt.Errorf(i18n.T("failed to push branch: %w"), err)
+		}
+
+	case prTags:
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Pushing tags to %s..."), repo.Remote()))
+		if err := repo.PushTags(remote.Token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createPR opens a new PR for head with the confirmed content and applies
+// labels, reviewers, assignees, milestone and project
+func createPR(remote *remoteForge, baseBranch, head string, result *ui.PRResult, report *ui.Report) error {
+	stop := ui.ShowSpinner(i18n.T("Creating pull request"))
+	prResult, err := remote.Forge.CreatePR(
+		remote.Base.Owner,
+		remote.Base.Name,
+		baseBranch,
+		head,
+		result.Title,
+		result.Description,
+	)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create PR: %w"), err)
+	}
+
+	applyPRMetadata(remote, prResult.Number, result, report)
+	setPROut(prResult.Number, prResult.URL, result, false)
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("PR created: %s"), prResult.URL))
+	return finishPR(remote, prResult.Number, prResult.URL, report)
+}
+
+// updatePR replaces the title and description of an existing PR with the
+// confirmed content
+func updatePR(remote *remoteForge, existing *github.PullRequest, result *ui.PRResult, report *ui.Report) error {
+	stop := ui.ShowSpinner(fmt.Sprintf(i18n.T("Updating pull request #%d"), existing.Number))
+	prResult, err := remote.Forge.UpdatePR(
+		remote.Base.Owner,
+		remote.Base.Name,
+		existing.Number,
+		result.Title,
+		result.Description,
+	)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to update PR: %w"), err)
+	}
+
+	applyPRMetadata(remote, prResult.Number, result, report)
+	setPROut(prResult.Number, prResult.URL, result, true)
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("PR updated: %s"), prResult.URL))
+	return finishPR(remote, prResult.Number, prResult.URL, report)
+}
 
-		// Create the PR
-		ui.ShowInfo("Creating pull request...")
+// setPROut records a created or updated PR in the --json result, if any
+func setPROut(number int, url string, result *ui.PRResult, updated bool) {
+	if prOut == nil {
+		return
+	}
+	prOut.Number, prOut.URL, prOut.Updated = number, url, updated
+	prOut.Title, prOut.Description, prOut.Labels = result.Title, result.Description, result.Labels
+}
 
-		ghClient, err := github.NewClient()
+// branchChanges returns the commits and the diff of the current branch
+// since it forked from base. With onForge they come from the forge, for
+// shallow clones whose local history can't tell.
+func branchChanges(repo *git.Repository, remote *remoteForge, base, head string, onForge bool) ([]git.CommitInfo, string, error) {
+	if !onForge {
+		commits, err := repo.GetCommitsAhead(base)
+		if err != nil {
+			return nil, "", fmt.Errorf(i18n.T("failed to get commits: %w"), err)
+		}
+		diff, err := repo.GetDiffFromBase(base)
 		if err != nil {
-			return fmt.Errorf("failed to create GitHub client: %w", err)
+			return nil, "", fmt.Errorf(i18n.T("failed to get diff: %w"), err)
 		}
+		return commits, diff, nil
+	}
+
+	comparison, err := remote.Forge.Compare(remote.Base.Owner, remote.Base.Name, base, head)
+	if err != nil {
+		return nil, "", fmt.Errorf(i18n.T(`failed to compare with %s on %s: %w
 
-		prResult, err := ghClient.CreatePR(
-			repoInfo.Owner,
-			repoInfo.Name,
-			baseBranch,
-			currentBranch,
-			result.Title,
-			result.Description,
-		)
+Push the branch first so it can be compared, or fetch the full history:
+  git fetch --unshallow`), base, remote.Base.Host, err)
+	}
+
+	commits := make([]git.CommitInfo, 0, len(comparison.Commits))
+	for _, c := range comparison.Commits {
+		commits = append(commits, git.CommitInfo{
+			Hash:    c.SHA[:min(7, len(c.SHA))],
+			Message: strings.Split(c.Message, "\n")[0], // First line only
+		})
+	}
+	return commits, comparison.Diff, nil
+}
+
+// finishPR runs the follow-up actions for a created or updated PR
+func finishPR(remote *remoteForge, number int, url string, report *ui.Report) error {
+	if prCopy {
+		if err := ui.CopyToClipboard(url); err != nil {
+			report.Add("PR URL could not be copied: %v", err)
+		} else {
+			ui.ShowInfo(i18n.T("PR URL copied to clipboard"))
+		}
+	}
+
+	if prWeb {
+		if err := ui.OpenBrowser(url); err != nil {
+			report.Add("PR could not be opened in the browser: %v", err)
+		}
+	}
+
+	if prWatch {
+		return watchChecks(remote, number)
+	}
+	return nil
+}
+
+// applyPRMetadata applies labels, reviewers, assignees, milestone and
+// project to a PR and enables auto-merge. Failures don't undo the PR, they are noted in the report.
+func applyPRMetadata(remote *remoteForge, number int, result *ui.PRResult, report *ui.Report) {
+	if len(result.Labels) > 0 {
+		err := remote.Forge.AddLabels(remote.Base.Owner, remote.Base.Name, number, result.Labels)
 		if err != nil {
-			return fmt.Errorf("failed to create PR: %w", err)
+			report.Add("labels could not be applied: %v", err)
 		}
+	}
 
-		ui.ShowSuccess(fmt.Sprintf("PR created: %s", prResult.URL))
-		return nil
+	applyPRPeople(remote, number, report)
+	applyPRPlanning(remote, number, report)
 
-	default:
-		return fmt.Errorf("unexpected action")
+	if prAutoMerge != "" {
+		if err := remote.Forge.EnableAutoMerge(remote.Base.Owner, remote.Base.Name, number, prAutoMerge); err != nil {
+			report.Add("auto-merge could not be enabled: %v", err)
+		} else {
+			ui.ShowInfo(fmt.Sprintf(i18n.T("Auto-merge enabled (%s)"), prAutoMerge))
+		}
+	}
+}
+
+// applyPRPeople requests the reviewers and sets the assignees given on the
+// command line. Failures don't undo the PR, they are noted in the report.
+func applyPRPeople(remote *remoteForge, number int, report *ui.Report) {
+	owner, name := remote.Base.Owner, remote.Base.Name
+
+	if len(prReviewers) > 0 || len(prTeamReviewers) > 0 {
+		if err := remote.Forge.RequestReviewers(owner, name, number, prReviewers, prTeamReviewers); err != nil {
+			report.Add("reviewers could not be requested: %v", err)
+		}
+	}
+
+	if len(prAssignees) > 0 {
+		if err := remote.Forge.AddAssignees(owner, name, number, prAssignees); err != nil {
+			report.Add("assignees could not be set: %v", err)
+		}
+	}
+}
+
+// applyPRPlanning sets the milestone and project given on the command line
+func applyPRPlanning(remote *remoteForge, number int, report *ui.Report) {
+	owner, name := remote.Base.Owner, remote.Base.Name
+
+	if prMilestone != "" {
+		title, err := remote.Forge.SetMilestone(owner, name, number, prMilestone)
+		if err != nil {
+			report.Add("milestone could not be set: %v", err)
+		} else {
+			ui.ShowInfo(fmt.Sprintf(i18n.T("Milestone: %s"), title))
+		}
+	}
+
+	if prProject != "" {
+		title, err := remote.Forge.AddToProject(owner, name, number, prProject)
+		if err != nil {
+			report.Add("PR could not be added to a project: %v", err)
+		} else {
+			ui.ShowInfo(fmt.Sprintf(i18n.T("Project: %s"), title))
+		}
+	}
+}
+
+// suggestLabels asks the model to choose fitting labels from the labels
+// that already exist in the repository
+func suggestLabels(remote *remoteForge, llmClient *llm.Client, commits, diff string) ([]string, error) {
+	available, err := remote.Forge.ListLabels(remote.Base.Owner, remote.Base.Name)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("failed to list labels: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Suggesting labels"))
+	defer stop()
+	return llmClient.SuggestLabels(available, commits, diff)
+}
+
+// appendDiagram adds a Mermaid diagram of the modules the changes touch to
+// a PR description, when they touch enough of them to need one
+func appendDiagram(body string, llmClient *llm.Client, commits, diff string, report *ui.Report) string {
+	modules := changedModules(diff)
+	if len(modules) < diagramMinModules {
+		report.Add("diagram skipped: the changes touch %d module(s), diagrams are drawn from %d", len(modules), diagramMinModules)
+		return body
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Drawing a diagram of the changed modules"))
+	diagram, err := llmClient.GenerateDiagram(modules, commits, diff)
+	stop()
+	if err != nil {
+		report.Add("diagram skipped: %v", err)
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n## Structure\n\n```mermaid\n" + diagram + "\n```"
+}
+
+// changedModules returns the directories of the files changed by diff,
+// leaving out tests, each with its number of changed files
+func changedModules(diff string) []string {
+	var dirs []string
+	files := make(map[string]int)
+	for _, stat := range git.DiffStat(diff) {
+		if git.IsTestFile(stat.Path) {
+			continue
+		}
+		dir := path.Dir(stat.Path)
+		if files[dir] == 0 {
+			dirs = append(dirs, dir)
+		}
+		files[dir]++
+	}
+
+	modules := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		modules = append(modules, fmt.Sprintf("%s (%d file(s))", dir, files[dir]))
+	}
+	return modules
+}
+
+var (
+	// screenshotsHeading matches the heading of a Screenshots section
+	screenshotsHeading = regexp.MustCompile(`(?im)^#{1,3}[ \t]*screenshots?\b.*$`)
+
+	// sectionHeading matches the heading of the next section
+	sectionHeading = regexp.MustCompile(`(?m)^#{1,3}[ \t]`)
+
+	// htmlComment matches the guidance comments of PR templates
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// addScreenshotPlaceholders adds placeholders for before and after
+// screenshots of the changed UI files to a PR description, in its
+// Screenshots section if it has an empty one, or in a new one
+func addScreenshotPlaceholders(body string, uiFiles []string) string {
+	listed := uiFiles
+	if len(listed) > 5 {
+		listed = append(listed[:5:5], fmt.Sprintf("and %d more", len(uiFiles)-5))
+	}
+	placeholder := fmt.Sprintf(`<!-- UI files changed: %s. Drop before/after screenshots below. -->
+
+| Before | After |
+| --- | --- |
+| _screenshot_ | _screenshot_ |`, strings.Join(listed, ", "))
+
+	loc := screenshotsHeading.FindStringIndex(body)
+	if loc == nil {
+		return strings.TrimRight(body, "\n") + "\n\n## Screenshots\n\n" + placeholder
+	}
+
+	// Only fill in a section the model left empty
+	rest := body[loc[1]:]
+	end := len(rest)
+	if next := sectionHeading.FindStringIndex(rest); next != nil {
+		end = next[0]
+	}
+	content := strings.TrimSpace(htmlComment.ReplaceAllString(rest[:end], ""))
+	if content != "" && !strings.EqualFold(content, "N/A") {
+		return body
+	}
+	return strings.TrimRight(body[:loc[1]]+"\n\n"+placeholder+"\n\n"+strings.TrimLeft(rest[end:], "\n"), "\n")
+}
+
+// appendDependencies adds a section listing the dependency changes to a PR
+// description, in place of the lockfile diffs reviewers would skim
+func appendDependencies(body string, deps *git.DependencySummary) string {
+	if len(deps.Changes) == 0 {
+		return body
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(body, "\n"))
+	b.WriteString("\n\n## Dependencies\n\n")
+	for _, c := range deps.Changes {
+		switch {
+		case c.From == "":
+			fmt.Fprintf(&b, "- Added `%s` %s\n", c.Name, c.To)
+		case c.To == "":
+			fmt.Fprintf(&b, "- Removed `%s`\n", c.Name)
+		default:
+			fmt.Fprintf(&b, "- Bumped `%s` %s → %s\n", c.Name, c.From, c.To)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// mergeLabels appends the labels from extra that are not already in labels
+func mergeLabels(labels, extra []string) []string {
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		seen[label] = true
+	}
+
+	merged := append([]string(nil), labels...)
+	for _, label := range extra {
+		if !seen[label] {
+			seen[label] = true
+			merged = append(merged, label)
+		}
 	}
+	return merged
 }
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+const (
+	// pulseBranches and pulseContributors are how many of the most active
+	// branches and contributors are listed
+	pulseBranches     = 8
+	pulseContributors = 5
+
+	// pulseBarWidth is the length of the bar of the busiest week
+	pulseBarWidth = 30
+)
+
+var (
+	pulseSince     string
+	pulseNoSummary bool
+)
+
+var pulseCmd = &cobra.Command{
+	Use:   "pulse",
+	Short: "Show a dashboard of the repository's recent activity",
+	Long: `Shows the pulse of the repository over the last weeks: commits per week,
+the most recently active branches, the number of open pull requests and the
+top contributors, followed by an AI-written "state of the repo" paragraph.
+
+Commits on all local and remote-tracking branches are counted, so fetch
+first for an up-to-date picture. Without a GitHub token or remote, open
+pull requests are left out.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set, unless --no-summary is used`,
+	Args: cobra.NoArgs,
+	RunE: runPulse,
+}
+
+func init() {
+	pulseCmd.Flags().StringVarP(&pulseSince, "since", "s", "4w", "Period to show, e.g. 2w, 4w or 2024-05-01")
+	pulseCmd.Flags().BoolVar(&pulseNoSummary, "no-summary", false, "Skip the AI-written summary")
+	rootCmd.AddCommand(pulseCmd)
+}
+
+func runPulse(cmd *cobra.Command, args []string) error {
+	if !pulseNoSummary {
+		if err := checkOpenAIKey(); err != nil {
+			return err
+		}
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := git.ParseSince(pulseSince, now)
+	if err != nil {
+		return err
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	commits, err := repo.CommitsSince(since, "", true)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to collect commits: %w"), err)
+	}
+	branches, err := repo.ActiveBranches(since)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to list branches: %w"), err)
+	}
+
+	name := filepath.Base(repo.Root())
+	openPRs := -1
+	if remote, err := openBaseForge(repo, cfg); err != nil {
+		report.Add("open pull requests left out: %v", err)
+	} else {
+		name = remote.Base.Owner + "/" + remote.Base.Name
+		if openPRs, err = remote.Forge.CountOpenPRs(remote.Base.Owner, remote.Base.Name); err != nil {
+			report.Add("open pull requests left out: %v", err)
+		}
+		remote.showQuota()
+	}
+
+	sections := []string{
+		fmt.Sprintf(i18n.T("Pulse of %s, %s to %s"), name, since.Format("Mon Jan 2"), now.Format("Mon Jan 2, 2006")),
+		pulseVelocity(commits, since, now),
+		pulseActiveBranches(branches),
+	}
+	if openPRs >= 0 {
+		sections = append(sections, fmt.Sprintf(i18n.T("Open pull requests: %d"), openPRs))
+	}
+	sections = append(sections, pulseTopContributors(commits))
+
+	dashboard := strings.Join(sections, "\n\n")
+	fmt.Printf("\n%s\n", dashboard)
+
+	if pulseNoSummary || len(commits) == 0 {
+		return nil
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	subjects := make([]string, 0, len(commits))
+	for _, c := range commits {
+		subjects = append(subjects, fmt.Sprintf("[%s] %s (%s)", c.Branch, c.Message, c.Author))
+	}
+	stop := ui.ShowSpinner(i18n.T("Summarizing the activity"))
+	summary, err := llmClient.SummarizeRepo(dashboard, subjects)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to summarize repository: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf(i18n.T("\nState of the repo:\n%s\n"), summary))
+	return nil
+}
+
+// pulseVelocity charts the commits per week since the given time
+func pulseVelocity(commits []git.AuthoredCommit, since, now time.Time) string {
+	week := 7 * 24 * time.Hour
+	weeks := max(int((now.Sub(since)+week-1)/week), 1)
+	counts := git.CommitsPerWeek(commits, now, weeks)
+
+	busiest := 1
+	for _, n := range counts {
+		busiest = max(busiest, n)
+	}
+
+	lines := []string{fmt.Sprintf(i18n.T("Commits per week (%d in total)"), len(commits))}
+	for i, n := range counts {
+		start := now.Add(-time.Duration(weeks-i) * week)
+		bar := strings.Repeat("█", (n*pulseBarWidth+busiest-1)/busiest)
+		lines = append(lines, fmt.Sprintf("  %-6s  %s %d", start.Format("Jan 2"), bar, n))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pulseActiveBranches lists the most recently active branches
+func pulseActiveBranches(branches []git.BranchActivity) string {
+	if len(branches) == 0 {
+		return i18n.T("Active branches: none")
+	}
+
+	shown := branches[:min(len(branches), pulseBranches)]
+	width := 0
+	for _, b := range shown {
+		width = max(width, len(b.Name))
+	}
+
+	lines := []string{fmt.Sprintf(i18n.T("Active branches (%d)"), len(branches))}
+	for _, b := range shown {
+		lines = append(lines, fmt.Sprintf("  %-*s  %s  %s: %s", width, b.Name, b.When.Format("Jan 2 15:04"), b.Author, b.Subject))
+	}
+	if rest := len(branches) - len(shown); rest > 0 {
+		lines = append(lines, fmt.Sprintf(i18n.T("  ... and %d more"), rest))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pulseTopContributors lists the authors with the most commits
+func pulseTopContributors(commits []git.AuthoredCommit) string {
+	authors := git.TopAuthors(commits)
+	if len(authors) == 0 {
+		return i18n.T("Top contributors: none")
+	}
+
+	shown := authors[:min(len(authors), pulseContributors)]
+	width := 0
+	for _, a := range shown {
+		width = max(width, len(a.Name))
+	}
+
+	lines := []string{fmt.Sprintf(i18n.T("Top contributors (%d in total)"), len(authors))}
+	for _, a := range shown {
+		lines = append(lines, fmt.Sprintf(i18n.T("  %-*s  %d commit(s)"), width, a.Name, a.Commits))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	reportSince  string
+	reportAuthor string
+	reportCopy   bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write an activity report of recent commits and merged PRs",
+	Long: `Aggregates the commits on all local and remote-tracking branches and the
+pull requests merged on GitHub over a period, and writes a formatted summary
+to paste into Slack or a status document.
+
+  vibe report                      # the last week of the whole team
+  vibe report --since 2w           # the last two weeks
+  vibe report --author alice       # only one person's work
+
+--since takes a window like 24h, 3d, 1w or a date like 2024-05-01.
+--author matches commit author emails and names and PR author logins,
+ignoring case. Fetch first to include your teammates' latest work.
+
+Without a GitHub token or remote, the report is based on commits only.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportSince, "since", "s", "1w", "Period to report on, e.g. 3d, 1w, 2w or 2024-05-01")
+	reportCmd.Flags().StringVarP(&reportAuthor, "author", "a", "", "Only include work by this author (commit email or name, or GitHub login)")
+	reportCmd.Flags().BoolVarP(&reportCopy, "copy", "c", false, "Copy the report to the clipboard")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := git.ParseSince(reportSince, now)
+	if err != nil {
+		return err
+	}
+	period := fmt.Sprintf(i18n.T("%s to %s"), since.Format("Mon Jan 2"), now.Format("Mon Jan 2, 2006"))
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	commits, err := repo.CommitsSince(since, reportAuthor, true)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to collect commits: %w"), err)
+	}
+
+	prs, err := mergedPRs(repo, cfg, since)
+	if err != nil {
+		report.Add("merged pull requests left out: %v", err)
+	}
+
+	if len(commits) == 0 && len(prs) == 0 {
+		who := ""
+		if reportAuthor != "" {
+			who = " by " + reportAuthor
+		}
+		return fmt.Errorf(i18n.T(`no commits or merged pull requests%s from %s
+
+Look further back, or fetch your teammates' work first:
+  vibe report --since 2w
+  git fetch --all`), who, period)
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Writing a report from %d commit(s) and %d merged PR(s), %s..."), len(commits), len(prs), period))
+
+	commitLines := make([]string, 0, len(commits))
+	for _, c := range commits {
+		commitLines = append(commitLines, fmt.Sprintf("[%s] %s %s (%s)", c.Branch, c.Hash, c.Message, c.Author))
+	}
+	prLines := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		prLines = append(prLines, fmt.Sprintf("#%d %s (@%s, merged %s)", pr.Number, pr.Title, pr.Author, pr.MergedAt.Local().Format("Mon Jan 2")))
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Writing the report"))
+	summary, err := llmClient.GenerateReport(period, commitLines, prLines)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate report: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", summary))
+
+	if reportCopy {
+		if err := ui.CopyToClipboard(summary); err != nil {
+			report.Add("report could not be copied: %v", err)
+		} else {
+			ui.ShowInfo(i18n.T("Report copied to clipboard"))
+		}
+	}
+	return nil
+}
+
+// mergedPRs returns the pull requests merged since the given time in the
+// repository PRs are opened against, filtered by --author
+func mergedPRs(repo *git.Repository, cfg *config.Config, since time.Time) ([]github.MergedPR, error) {
+	remote, err := openBaseForge(repo, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer remote.showQuota()
+
+	prs, err := remote.Forge.MergedPRs(remote.Base.Owner, remote.Base.Name, since)
+	if err != nil {
+		return nil, err
+	}
+
+	if reportAuthor == "" {
+		return prs, nil
+	}
+	var filtered []github.MergedPR
+	for _, pr := range prs {
+		if strings.Contains(strings.ToLower(pr.Author), strings.ToLower(reportAuthor)) {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered, nil
+}
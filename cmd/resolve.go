@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+// resolveContextLines is how many lines around a conflict are sent to the
+// model with it
+const resolveContextLines = 10
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve [files...]",
+	Short: "Resolve merge conflicts with AI-proposed resolutions",
+	Long: `Finds the files left conflicted by a merge, rebase, cherry-pick or stash
+apply, and proposes a resolution for each conflicted region, combining the
+intent of both sides. For each region you can accept the proposal, edit it,
+keep one side or leave the markers in place.
+
+  vibe resolve                  # all conflicted files
+  vibe resolve cmd/root.go      # only some of them
+
+Files whose conflicts are all resolved are staged, like git add. Conflicts
+with the diff3 or zdiff3 style (git config merge.conflictStyle diff3) also
+give the model the common ancestor, which makes for better proposals.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	RunE: runResolve,
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+}
+
+func runResolve(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	files, err := repo.ConflictedFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New(i18n.T("no conflicted files found, there is no merge, rebase or cherry-pick to resolve"))
+	}
+
+	if len(args) > 0 {
+		conflicted := make(map[string]bool, len(files))
+		for _, f := range files {
+			conflicted[f] = true
+		}
+		files = files[:0]
+		for _, arg := range args {
+			path, err := repo.RepoPath(arg)
+			if err != nil {
+				return err
+			}
+			if !conflicted[path] {
+				return fmt.Errorf(i18n.T("%s has no conflicts"), arg)
+			}
+			files = append(files, path)
+		}
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	var resolved []string
+	for _, path := range files {
+		done, ok, err := resolveFile(repo, llmClient, path, report)
+		if err != nil {
+			return err
+		}
+		if ok {
+			resolved = append(resolved, path)
+		}
+		if done {
+			break
+		}
+	}
+
+	if err := repo.MarkResolved(resolved); err != nil {
+		return fmt.Errorf(i18n.T("failed to stage resolved files: %w"), err)
+	}
+	if len(resolved) > 0 {
+		ui.ShowSuccess(fmt.Sprintf(i18n.T("Resolved and staged %d file(s)"), len(resolved)))
+	}
+
+	left, err := repo.ConflictedFiles()
+	if err != nil {
+		return err
+	}
+	if len(left) > 0 {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("\n%d file(s) still have conflicts: %s"), len(left), strings.Join(left, ", ")))
+		return nil
+	}
+	ui.ShowInfo(i18n.T(`
+All conflicts are resolved. Review the result, then continue with e.g.:
+  git merge --continue
+  git rebase --continue`))
+	return nil
+}
+
+// resolveFile walks the conflicts of a file with the user and writes the
+// resolved ones back. It reports whether the user is done with all files
+// and whether every conflict of this file was resolved.
+func resolveFile(repo *git.Repository, llmClient *llm.Client, path string, report *ui.Report) (done, resolved bool, err error) {
+	fullPath := filepath.Join(repo.Root(), filepath.FromSlash(path))
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		// e.g. deleted on one side, which has no markers to resolve
+		report.Add("skipped %s: %v, resolve it with git add or git rm", path, err)
+		return false, false, nil
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		report.Add("skipped %s: binary files can't be resolved here, pick a side with git checkout --ours or --theirs", path)
+		return false, false, nil
+	}
+
+	content := string(data)
+	hunks, err := git.ParseConflicts(content)
+	if err != nil {
+		report.Add("skipped %s: %v", path, err)
+		return false, false, nil
+	}
+	if len(hunks) == 0 {
+		report.Add("%s has no conflict markers left, stage it with git add once it looks right", path)
+		return false, false, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	resolutions := make(map[int]string, len(hunks))
+	for i, hunk := range hunks {
+		before, after := git.ConflictContext(content, hunk, resolveContextLines)
+		stop := ui.ShowSpinner(fmt.Sprintf(i18n.T("Proposing a resolution for conflict %d/%d in %s"), i+1, len(hunks), path))
+		proposal, err := llmClient.ResolveConflict(llm.Conflict{
+			Path:        path,
+			Before:      before,
+			After:       after,
+			OursLabel:   hunk.OursLabel,
+			TheirsLabel: hunk.TheirsLabel,
+			Ours:        hunk.Ours,
+			Base:        hunk.Base,
+			Theirs:      hunk.Theirs,
+		})
+		stop()
+		if err != nil {
+			return false, false, fmt.Errorf(i18n.T("failed to resolve conflict in %s: %w"), path, err)
+		}
+
+		region := strings.Join(lines[hunk.Start:hunk.End+1], "\n")
+		result, err := ui.ConfirmResolution(path, i+1, len(hunks), region, proposal.Code, proposal.Reason)
+		if err != nil {
+			return false, false, err
+		}
+
+		switch result.Action {
+		case ui.ResolveAccept:
+			resolutions[i] = result.Code
+		case ui.ResolveOurs:
+			resolutions[i] = hunk.Ours
+		case ui.ResolveTheirs:
+			resolutions[i] = hunk.Theirs
+		case ui.ResolveDone:
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	if len(resolutions) > 0 {
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return false, false, err
+		}
+		if err := os.WriteFile(fullPath, []byte(git.ResolveConflicts(content, hunks, resolutions)), info.Mode().Perm()); err != nil {
+			return false, false, fmt.Errorf(i18n.T("failed to write %s: %w"), path, err)
+		}
+	}
+	if len(resolutions) < len(hunks) {
+		report.Add("%s still has %d unresolved conflict(s), it was not staged", path, len(hunks)-len(resolutions))
+		return done, false, nil
+	}
+	return done, true, nil
+}
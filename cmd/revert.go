@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var revertReason string
+
+var revertCmd = &cobra.Command{
+	Use:   "revert <commit>",
+	Short: "Revert a commit with a message explaining what and why",
+	Long: `Reverts a commit like git revert, asks why (unless given with --reason), and
+generates a message explaining what is being undone and why, instead of the
+bare "Revert ..." default. The message ends with the same "This reverts
+commit ..." line as git revert.
+
+  vibe revert a1b2c3d
+  vibe revert a1b2c3d --reason "doubled upload times in production"
+
+If the revert conflicts, the conflicts are left to resolve, e.g. with
+vibe resolve, and you commit the result yourself.
+
+Requirements:
+- Must be in a git repository, without staged changes
+- The git binary must be installed
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRevert,
+}
+
+func init() {
+	revertCmd.Flags().StringVarP(&revertReason, "reason", "r", "", "Why the commit is reverted (asked for when not given)")
+	rootCmd.AddCommand(revertCmd)
+}
+
+func runRevert(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	hasStaged, err := repo.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+	}
+	if hasStaged {
+		return errors.New(i18n.T(`staged changes would be mixed into the revert commit
+
+Commit or unstage them first.`))
+	}
+
+	reverted, err := repo.Revert(args[0])
+	var conflict *git.ConflictError
+	if errors.As(err, &conflict) {
+		return fmt.Errorf(i18n.T(`reverting %s %s stopped on %w
+
+Resolve them, then commit the result:
+  vibe resolve
+  vibe commit
+
+Or drop the revert:
+  git reset --merge`), reverted.Hash, reverted.Message, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	hasStaged, err = repo.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+	}
+	if !hasStaged {
+		return fmt.Errorf(i18n.T("the changes of %s are already undone, nothing to revert"), reverted.Hash)
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Reverting %s %s (%s, %s)"), reverted.Hash, reverted.Message, reverted.Author, reverted.When.Format("Jan 2, 2006")))
+
+	reason := strings.TrimSpace(revertReason)
+	if reason == "" {
+		if reason, err = ui.AskReason(i18n.T("Why is this commit reverted?")); err != nil {
+			return err
+		}
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	diff := prepareDiff(reverted.Diff, cfg, report)
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Generating the revert message"))
+	message, err := llmClient.GenerateRevertMessage(strings.TrimSpace(reverted.Message+"\n\n"+reverted.Body), reason, diff)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T(`failed to generate message: %w
+
+The revert is still staged. Commit it with 'vibe commit', or drop it with 'git reset --merge'.`), err)
+	}
+
+	result, err := ui.ConfirmCommit(revertMessage(message, reverted.FullHash), false)
+	if err != nil {
+		return err
+	}
+	if result.Action == ui.ActionCancel {
+		ui.ShowInfo(i18n.T(`Commit cancelled. The revert is still staged.
+
+Commit it with 'vibe commit', or drop it with 'git reset --merge'.`))
+		return errCancelled
+	}
+
+	hash, err := repo.Commit(result.Message, git.CommitOptions{Signoff: cfg.Commit.Signoff})
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create commit: %w"), err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Committed: %s"), hash))
+	return nil
+}
+
+// revertMessage adds the "This reverts commit ..." line of git revert to
+// message, unless it is already there
+func revertMessage(message, hash string) string {
+	line := fmt.Sprintf("This reverts commit %s.", hash)
+	if strings.Contains(message, line) {
+		return message
+	}
+	return message + "\n\n" + line
+}
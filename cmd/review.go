@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	reviewBase string
+	reviewPost bool
+)
+
+// findingTitle returns the heading findings of kind are listed under
+func findingTitle(kind string) string {
+	switch kind {
+	case llm.FindingBug:
+		return i18n.T("Potential bugs")
+	case llm.FindingTest:
+		return i18n.T("Missing tests")
+	default:
+		return i18n.T("Risky changes")
+	}
+}
+
+// findingLabels label single findings in posted reviews, by kind
+var findingLabels = map[string]string{
+	llm.FindingBug:  "Potential bug",
+	llm.FindingTest: "Missing test",
+	llm.FindingRisk: "Risky change",
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review the branch's changes for bugs, missing tests and risks",
+	Long: `Runs the diff of the current branch against its base branch through a
+review-oriented prompt and reports potential bugs, missing tests and risky
+changes. Style nits are left out.
+
+With --post, the findings are posted as a review on the branch's open PR:
+findings on lines of the diff become line comments, the others go into
+the review summary. The review only comments, it neither approves nor
+requests changes.
+
+Requirements:
+- Must be in a git repository, on a branch other than the base branch
+- OPENAI_API_KEY environment variable must be set
+- For --post, a GitHub token and an open PR for the branch`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+func init() {
+	reviewCmd.Flags().StringVarP(&reviewBase, "base", "B", "", "Branch to compare against (default: main or master)")
+	reviewCmd.Flags().BoolVar(&reviewPost, "post", false, "Post the findings as a review on the branch's open PR")
+	_ = reviewCmd.RegisterFlagCompletionFunc("base", completeBranches)
+	rootCmd.AddCommand(reviewCmd)
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+	repo.UseRemote(cfg.PR.Remote)
+
+	currentBranch, err := repo.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get current branch: %w"), err)
+	}
+
+	baseBranch := reviewBase
+	if baseBranch == "" {
+		if baseBranch, err = repo.GetDefaultBranch(); err != nil {
+			return fmt.Errorf(i18n.T("failed to detect base branch: %w"), err)
+		}
+	}
+	if currentBranch == baseBranch {
+		return fmt.Errorf(i18n.T(`nothing to review on the %s branch
+
+Switch to a feature branch, or compare against another base:
+  vibe review --base <branch>`), baseBranch)
+	}
+
+	// Connect before spending an API call when the review is to be posted
+	var remote *remoteForge
+	if reviewPost {
+		if remote, err = openBaseForge(repo, cfg); err != nil {
+			return err
+		}
+		defer remote.showQuota()
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Reviewing branch '%s' against '%s'..."), currentBranch, baseBranch))
+
+	diff, err := repo.GetDiffFromBase(baseBranch)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get diff: %w"), err)
+	}
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return fmt.Errorf(i18n.T("no changes found compared to %s"), baseBranch)
+	}
+	showDiffStat(diff)
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Reviewing the changes"))
+	findings, err := llmClient.ReviewDiff(git.NumberLines(diff))
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to review changes: %w"), err)
+	}
+
+	if len(findings) == 0 {
+		ui.ShowSuccess(i18n.T("No problems found"))
+		return nil
+	}
+	showFindings(findings)
+
+	if !reviewPost {
+		return nil
+	}
+	return postReview(remote, currentBranch, diff, findings)
+}
+
+// showFindings prints the findings grouped by kind
+func showFindings(findings []llm.ReviewFinding) {
+	var b strings.Builder
+	for _, kind := range []string{llm.FindingBug, llm.FindingTest, llm.FindingRisk} {
+		var lines []string
+		for _, f := range findings {
+			if f.Kind == kind {
+				lines = append(lines, fmt.Sprintf("  %s  %s", findingLocation(f), f.Message))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n%s:\n%s\n", findingTitle(kind), strings.Join(lines, "\n"))
+		}
+	}
+	ui.Page(b.String())
+}
+
+// findingLocation returns "path:line", or the path for file-wide findings
+func findingLocation(f llm.ReviewFinding) string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.Path, f.Line)
+	}
+	return f.Path
+}
+
+// postReview posts the findings as a review on the branch's open PR.
+// Findings on lines the diff shows become line comments; GitHub rejects
+// comments on other lines, so those go into the summary.
+func postReview(remote *remoteForge, branch, diff string, findings []llm.ReviewFinding) error {
+	head := github.QualifiedHead(remote.Repo, remote.Base, branch)
+	pr, err := remote.Forge.FindOpenPR(remote.Base.Owner, remote.Base.Name, head)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to find the branch's PR: %w"), err)
+	}
+	if pr == nil {
+		return fmt.Errorf(i18n.T(`no open PR found for branch '%s'
+
+Open one first with:
+  vibe pr`), branch)
+	}
+
+	lines := git.DiffLines(diff)
+	var comments []github.ReviewComment
+	var summary []string
+	for _, f := range findings {
+		label := findingLabels[f.Kind]
+		if f.Line > 0 && lines[f.Path][f.Line] {
+			comments = append(comments, github.ReviewComment{
+				Path: f.Path,
+				Line: f.Line,
+				Body: fmt.Sprintf("**%s:** %s", label, f.Message),
+			})
+			continue
+		}
+		summary = append(summary, fmt.Sprintf("- **%s** `%s`: %s", label, findingLocation(f), f.Message))
+	}
+
+	body := fmt.Sprintf("vibe review found %d potential problem(s).", len(findings))
+	if len(summary) > 0 {
+		body += "\n\n" + strings.Join(summary, "\n")
+	}
+
+	url, err := remote.Forge.CreateReview(remote.Base.Owner, remote.Base.Name, pr.Number, body, comments)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to post review: %w"), err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Posted review on PR #%d with %d line comment(s)"), pr.Number, len(comments)))
+	fmt.Printf("\n  %s\n", url)
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var rewordCount int
+
+var rewordCmd = &cobra.Command{
+	Use:   "reword",
+	Short: "Regenerate the messages of unpushed commits",
+	Long: `Walks the commits of the current branch that are not pushed yet, generates a
+better message for each from its diff, and shows the current and proposed
+messages side by side. The messages you accept are rewritten like an
+interactive rebase would, the content of the commits is not changed.
+
+Pushed commits are left alone, as rewording them would need a force push.
+The walk also stops at merge commits.
+
+Requirements:
+- Must be in a git repository, on a branch
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runReword,
+}
+
+func init() {
+	rewordCmd.Flags().IntVarP(&rewordCount, "count", "n", 10, "Maximum number of recent commits to reword")
+	rootCmd.AddCommand(rewordCmd)
+}
+
+func runReword(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	if _, err := repo.GetCurrentBranch(); err != nil {
+		return fmt.Errorf(i18n.T("failed to get current branch: %w"), err)
+	}
+
+	commits, err := repo.UnpushedCommits(rewordCount)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to find unpushed commits: %w"), err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf(i18n.T(`no unpushed commits to reword
+
+Commits already on %s are left alone, rewording them would need a force push.`), repo.Remote())
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	if len(commits) == rewordCount {
+		report.Add("only the last %d commits were considered, use --count for more", rewordCount)
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	template, _ := repo.CommitTemplate()
+	opts := llm.CommitOptions{Template: template}
+
+	var hashes, before, after, messages []string
+	for _, c := range commits {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Rewording %s %s..."), c.Hash, c.Message))
+
+		current := strings.TrimSpace(c.Message + "\n\n" + c.Body)
+		diff, _ := git.FilterDiff(c.Diff, cfg.Paths.Exclude)
+		stop := ui.ShowSpinner(fmt.Sprintf(i18n.T("Generating a message for %s"), c.Hash))
+		message, err := llmClient.GenerateRewordMessage(current, diff, opts)
+		stop()
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to generate message for %s: %w"), c.Hash, err)
+		}
+		if message == "" || message == current {
+			continue
+		}
+
+		subject, _, _ := strings.Cut(message, "\n")
+		hashes = append(hashes, c.Hash)
+		before = append(before, c.Message)
+		after = append(after, subject)
+		messages = append(messages, message)
+	}
+	if len(hashes) == 0 {
+		ui.ShowInfo(i18n.T("\nNo better messages were found, nothing to reword."))
+		return nil
+	}
+
+	selected, err := ui.ConfirmReword(hashes, before, after)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		ui.ShowInfo(i18n.T("Reword cancelled."))
+		return errCancelled
+	}
+
+	reworded := make(map[string]string, len(selected))
+	for _, i := range selected {
+		reworded[hashes[i]] = messages[i]
+	}
+	head, err := repo.Reword(reworded)
+	if err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Reworded %d commit(s), HEAD is now %s"), len(reworded), head))
+	fmt.Printf(i18n.T("\nUndo with: git reset --soft %s\n"), commits[0].Hash)
+	return nil
+}
@@ -1,16 +1,26 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 
-	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/auth"
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/logging"
+	"github.com/user/vibe/internal/ui"
 )
 
 func init() {
-	// Load .env file if it exists (silently ignore if not found)
-	_ = godotenv.Load()
+	// Load .env files if they exist (silently ignore if not found)
+	loadEnvFiles()
 }
 
 var rootCmd = &cobra.Command{
@@ -22,50 +32,232 @@ It streamlines your git workflow by analyzing your changes and suggesting
 appropriate commit messages or PR descriptions using OpenAI.
 
 Commands:
-  vibe commit  - Generate an AI commit message for staged changes
-  vibe pr      - Create a GitHub PR with AI-generated title and description
+  vibe commit       - Generate an AI commit message for staged changes
+  vibe pr           - Create a GitHub PR with AI-generated title and description
+  vibe watch        - Keep a commit message ready while you stage changes
+  vibe tui          - Stage, commit and open PRs in a full-screen interface
+  vibe fixup        - Create a fixup! commit for a recent commit
+  vibe branch       - Create a branch with an AI-suggested name
+  vibe start        - Start a branch for a GitHub issue
+  vibe cherry-pick  - Cherry-pick a commit with a message adapted to the branch
+  vibe revert       - Revert a commit with a message explaining what and why
+  vibe squash       - Squash commits into one with an AI-generated message
+  vibe reword       - Regenerate the messages of unpushed commits
+  vibe stash        - Stash changes under an AI-generated description
+  vibe tag          - Create an annotated tag with AI-written release notes
+  vibe changelog    - Generate a CHANGELOG section since the last tag
+  vibe bump         - Recommend the next semantic version
+  vibe notes        - Summarize a range of commits into release notes
+  vibe describe     - Explain what a commit changed and why it might matter
+  vibe why          - Explain why a line is the way it is
+  vibe explain      - Explain your staged or unstaged changes in prose
+  vibe resolve      - Resolve merge conflicts with AI-proposed resolutions
+  vibe review       - Review the branch for bugs, missing tests and risks
+  vibe summarize    - Summarize the current branch in one paragraph
+  vibe standup      - Summarize your recent commits for a daily standup
+  vibe report       - Write an activity report of recent commits and merged PRs
+  vibe pulse        - Show a dashboard of the repository's recent activity
+  vibe churn        - Find the most changed files and refactor candidates
+  vibe contributors - Show commit statistics per author
+  vibe hook         - Get AI messages from plain 'git commit' via git hooks
+  vibe auth         - Store API keys in the OS keychain
+  vibe config       - Change settings and share configuration bundles
+  vibe completion   - Print a shell completion script
 
-Environment Variables:
+Environment Variables (take precedence over keys stored with 'vibe auth'):
   OPENAI_API_KEY  - Your OpenAI API key (required)
-  GITHUB_TOKEN    - Your GitHub personal access token (required for PR command)`,
+  GITHUB_TOKEN    - Your GitHub personal access token (required for PR command,
+                    falls back to the gh CLI login when unset)`,
 }
 
 // Execute runs the root command
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if closeLog != nil {
+		_ = closeLog()
+	}
+	if err != nil && !cancelled(err) {
+		// Flag parsing may have failed before setupLogging
+		ui.SetNoColor(noColor || os.Getenv("NO_COLOR") != "")
+		fmt.Fprintln(os.Stderr, ui.ErrorLine(err))
+	}
+	return err
 }
 
+var (
+	// verbose enables extra diagnostic output
+	verbose bool
+
+	// quiet hides progress output, leaving errors, results and prompts
+	quiet bool
+
+	// logFile receives all diagnostic logs, whatever the verbosity
+	logFile string
+
+	// debug writes the prompts and responses of API requests to debugFile
+	debug bool
+
+	// assumeYes accepts generated messages without prompting
+	assumeYes bool
+
+	// noColor disables colors, like a non-empty NO_COLOR
+	noColor bool
+
+	// noPager prints long output directly instead of through $PAGER
+	noPager bool
+
+	// model overrides the model setting
+	model string
+
+	// closeLog closes the log files once the command is done
+	closeLog func() error
+)
+
 func init() {
-	// Disable the default completion command
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	// Execute prints errors, except cancellations
+	rootCmd.SilenceErrors = true
+
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show extra diagnostic output, such as git commands, API requests and the remaining GitHub API quota")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only show errors, results and prompts")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append debug logs to this file, e.g. to attach to a bug report")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Accept generated messages without prompting, e.g. in CI")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors in output and prompts, like setting NO_COLOR")
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Print long output directly instead of through $PAGER")
+	rootCmd.PersistentFlags().StringVar(&model, "model", "", "OpenAI model to use, overriding the model setting")
+	_ = rootCmd.RegisterFlagCompletionFunc("model", completeModels)
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Append the prompts sent to the model and its raw responses, with secrets redacted, to a local file")
+	rootCmd.PersistentPreRunE = setupLogging
+
+	// Ctrl-C while waiting on a request cancels the command
+	ui.SetInterruptHandler(func() {
+		fmt.Fprintln(os.Stderr, i18n.T("Cancelled."))
+		if closeLog != nil {
+			_ = closeLog()
+		}
+		os.Exit(ExitCancelled)
+	})
 }
 
-// loadEnv is called by init() at package load time
-// It's defined separately to allow the godotenv.Load() to run first
+// setupLogging applies the verbosity, prompt and color flags to the
+// logger and the UI
+func setupLogging(cmd *cobra.Command, args []string) error {
+	if verbose && quiet {
+		return errors.New(i18n.T("--verbose and --quiet can't be used together"))
+	}
+
+	// Cobra validates these after this hook, errors past it aren't usage
+	// errors
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		return err
+	}
+	if err := cmd.ValidateFlagGroups(); err != nil {
+		return err
+	}
+	started = true
+	cmd.SilenceUsage = true
+
+	level := slog.LevelWarn
+	switch {
+	case verbose:
+		level = slog.LevelDebug
+	case quiet:
+		level = slog.LevelError
+	}
+	ui.SetQuiet(quiet)
+	ui.SetAssumeYes(assumeYes)
+	ui.SetNoColor(noColor || os.Getenv("NO_COLOR") != "")
+	ui.SetNoPager(noPager)
+
+	closeLogFile, err := logging.Setup(level, logFile)
+	if err != nil {
+		return err
+	}
+	closeLog = closeLogFile
+	if !debug {
+		return nil
+	}
 
-// checkOpenAIKey validates that OPENAI_API_KEY is set
+	path, err := debugFile()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to open debug log: %w"), err)
+	}
+	llm.SetDebugLog(f, git.RedactSecrets)
+	fmt.Fprintf(os.Stderr, i18n.T("Writing prompts and responses to %s\n"), path)
+
+	closeLog = func() error {
+		f.Close()
+		return closeLogFile()
+	}
+	return nil
+}
+
+// debugFile returns the path of the --debug log, in vibe's cache directory
+func debugFile() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to locate cache directory: %w"), err)
+	}
+	dir := filepath.Join(base, "vibe")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf(i18n.T("failed to create cache directory: %w"), err)
+	}
+	return filepath.Join(dir, "debug.log"), nil
+}
+
+// checkOpenAIKey validates that an OpenAI API key is available, either from
+// OPENAI_API_KEY or from the OS keyring
 func checkOpenAIKey() error {
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		return fmt.Errorf(`OPENAI_API_KEY environment variable is not set.
+	if auth.OpenAIKey() == "" {
+		return withExitCode(ExitAuth, errors.New(i18n.T(`OPENAI_API_KEY environment variable is not set.
 
 To fix this:
   export OPENAI_API_KEY="your-api-key"
 
-Get your API key at: https://platform.openai.com/api-keys`)
+Or store it in your OS keychain:
+  vibe auth set-key openai
+
+Get your API key at: https://platform.openai.com/api-keys`)))
 	}
 	return nil
 }
 
-// checkGitHubToken validates that GITHUB_TOKEN is set
-func checkGitHubToken() error {
-	if os.Getenv("GITHUB_TOKEN") == "" {
-		return fmt.Errorf(`GITHUB_TOKEN environment variable is not set.
+// githubToken returns the token for a GitHub host, looking at the
+// environment, the per-host config, the OS keychain and the gh CLI login
+func githubToken(cfg *config.Config, host string) (string, error) {
+	if token := auth.GitHubTokenForHost(host, cfg.HostToken(host)); token != "" {
+		return token, nil
+	}
+
+	if host != auth.DefaultGitHubHost {
+		return "", withExitCode(ExitAuth, fmt.Errorf(i18n.T(`no GitHub token found for %s.
+
+To fix this, add the token to your config (~/.config/vibe/config.yaml):
+  hosts:
+    %s:
+      token: your-token
+
+Or set it in the environment:
+  export GH_ENTERPRISE_TOKEN="your-token"
+
+Or log in with the GitHub CLI:
+  gh auth login --hostname %s`), host, host, host))
+	}
+
+	return "", withExitCode(ExitAuth, errors.New(i18n.T(`GITHUB_TOKEN environment variable is not set.
 
 To fix this:
   export GITHUB_TOKEN="your-token"
 
+Or store it in your OS keychain:
+  vibe auth set-key github
+
+Or log in with the GitHub CLI:
+  gh auth login
+
 Create a token at: https://github.com/settings/tokens
-Required scope: repo`)
-	}
-	return nil
+Required scope: repo`)))
 }
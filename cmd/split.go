@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+// splitCommit asks the model to group the staged changes into logical
+// commits and creates them one at a time once confirmed. It reports
+// whether the changes were handled; false means a single commit should be
+// made as usual.
+func splitCommit(repo *git.Repository, llmClient *llm.Client, diff string) (bool, error) {
+	files, err := repo.StagedPaths()
+	if err != nil {
+		return false, err
+	}
+	if len(files) < 2 {
+		return false, nil
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Grouping the staged changes"))
+	groups, err := llmClient.SuggestCommitSplit(files, diff)
+	stop()
+	if err != nil {
+		return false, fmt.Errorf(i18n.T("failed to suggest a split: %w"), err)
+	}
+	if len(groups) < 2 {
+		ui.ShowInfo(i18n.T("The staged changes belong together, making a single commit"))
+		return false, nil
+	}
+
+	messages := make([]string, len(groups))
+	groupFiles := make([][]string, len(groups))
+	for i, g := range groups {
+		messages[i], groupFiles[i] = g.Message, g.Files
+	}
+
+	action, err := ui.ConfirmSplit(messages, groupFiles)
+	if err != nil {
+		return false, err
+	}
+	switch action {
+	case ui.SplitSingle:
+		return false, nil
+	case ui.SplitCancel:
+		ui.ShowInfo(i18n.T("Commit cancelled."))
+		return true, errCancelled
+	}
+
+	for i, g := range groups {
+		hash, err := repo.CommitFiles(g.Files, g.Message, commitOptions())
+		if err != nil {
+			return true, fmt.Errorf(i18n.T("failed to create commit %d of %d: %w"), i+1, len(groups), err)
+		}
+		ui.ShowSuccess(fmt.Sprintf(i18n.T("Committed: %s"), hash))
+		fmt.Fprintf(os.Stdout, "\n  %s\n", g.Message)
+	}
+	return true, nil
+}
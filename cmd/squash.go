@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var squashPrint bool
+
+var squashCmd = &cobra.Command{
+	Use:   "squash <n|base>",
+	Short: "Squash commits into one with an AI-generated message",
+	Long: `Generates a single commit message for the last n commits, or for the commits
+since a base branch, from their messages and combined diff. Once you accept
+the message, the commits are replaced by one commit with the same content,
+like git reset --soft followed by git commit.
+
+  vibe squash 3              # the last 3 commits
+  vibe squash main           # everything since the branch forked from main
+  vibe squash main --print   # only print the message, e.g. for a squash merge
+
+Requirements:
+- Must be in a git repository, without staged changes
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSquash,
+}
+
+func init() {
+	squashCmd.Flags().BoolVarP(&squashPrint, "print", "p", false, "Only print the message, don't rewrite the history")
+	rootCmd.AddCommand(squashCmd)
+}
+
+func runSquash(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	base := args[0]
+	if n, err := strconv.Atoi(base); err == nil {
+		if n < 2 {
+			return fmt.Errorf(i18n.T("squashing needs at least 2 commits, got %d"), n)
+		}
+		base = fmt.Sprintf("HEAD~%d", n)
+	}
+
+	if !squashPrint {
+		// They would end up in the squashed commit
+		hasStaged, err := repo.HasStagedChanges()
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to check staged changes: %w"), err)
+		}
+		if hasStaged {
+			return fmt.Errorf(i18n.T(`staged changes would be folded into the squashed commit
+
+Commit or unstage them first, or only generate the message:
+  vibe squash %s --print`), args[0])
+		}
+	}
+
+	plan, err := repo.PlanSquash(base)
+	if err != nil && base != args[0] {
+		return fmt.Errorf(i18n.T(`can't squash the last %s commits: %w
+
+Squashing the first commit of the history is not supported.`), args[0], err)
+	}
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to find the commits to squash: %w"), err)
+	}
+	if len(plan.Commits) < 2 {
+		return fmt.Errorf(i18n.T("nothing to squash, there are %d commit(s) since %s"), len(plan.Commits), args[0])
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Squashing %d commits onto %s:"), len(plan.Commits), plan.Base[:7]))
+	for _, c := range plan.Commits {
+		ui.ShowInfo(fmt.Sprintf("  %s %s", c.Hash, c.Message))
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	diff := prepareDiff(plan.Diff, cfg, report)
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	template, _ := repo.CommitTemplate()
+	stop := ui.ShowSpinner(i18n.T("Generating the squashed commit message"))
+	message, err := llmClient.GenerateSquashMessage(describeCommits(plan.Commits), diff, llm.CommitOptions{Template: template})
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate message: %w"), err)
+	}
+
+	if squashPrint {
+		fmt.Printf("\n%s\n", message)
+		return nil
+	}
+
+	if plan.Pushed {
+		report.Add("some of these commits are already pushed, publish the squashed commit with git push --force-with-lease")
+	}
+
+	result, err := ui.ConfirmSquash(len(plan.Commits), message)
+	if err != nil {
+		return err
+	}
+	if result.Action == ui.ActionCancel {
+		ui.ShowInfo(i18n.T("Squash cancelled."))
+		return errCancelled
+	}
+
+	hash, err := repo.Squash(plan.Base, result.Message, git.CommitOptions{Signoff: cfg.Commit.Signoff})
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to squash commits: %w"), err)
+	}
+
+	subject, _, _ := strings.Cut(result.Message, "\n")
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Squashed %d commits into %s"), len(plan.Commits), hash))
+	fmt.Printf(i18n.T("\n  %s\n\nUndo with: git reset --soft %s\n"), subject, plan.Head[:7])
+	return nil
+}
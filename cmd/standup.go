@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	standupSince  string
+	standupAuthor string
+	standupCopy   bool
+)
+
+var standupCmd = &cobra.Command{
+	Use:   "standup",
+	Short: "Summarize your recent commits for a daily standup",
+	Long: `Collects your commits across all local branches since yesterday and writes
+a short "What I did / What's next" update for your daily standup.
+
+"Yesterday" starts at the beginning of the previous working day, so on
+Mondays Friday's work is included. Use --since for another window, e.g.
+24h, 3d, 1w or a date like 2024-05-01. Commits are matched by your git
+user.email unless --author gives another email or name.
+
+The defaults can be set in the config:
+  standup:
+    since: 2d
+    author: me@example.com
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runStandup,
+}
+
+func init() {
+	standupCmd.Flags().StringVarP(&standupSince, "since", "s", "", "Time window to collect commits from, e.g. 24h, 3d or 1w (default: yesterday)")
+	standupCmd.Flags().StringVarP(&standupAuthor, "author", "a", "", "Email or name of the author whose commits are collected (default: your git user.email)")
+	standupCmd.Flags().BoolVarP(&standupCopy, "copy", "c", false, "Copy the update to the clipboard")
+	rootCmd.AddCommand(standupCmd)
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	window := standupSince
+	if window == "" {
+		window = cfg.Standup.Since
+	}
+	if window == "" {
+		window = "yesterday"
+	}
+	since, err := git.ParseSince(window, time.Now())
+	if err != nil {
+		return err
+	}
+
+	author := standupAuthor
+	if author == "" {
+		author = cfg.Standup.Author
+	}
+	if author == "" {
+		_, author = repo.User()
+	}
+
+	commits, err := repo.CommitsSince(since, author, false)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to collect commits: %w"), err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf(i18n.T(`no commits by %s since %s
+
+Look further back, or at another author:
+  vibe standup --since 1w
+  vibe standup --author <email or name>`), author, since.Format("Mon Jan 2 15:04"))
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	currentBranch, _ := repo.GetCurrentBranch()
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Writing a standup update from %d commit(s) since %s..."), len(commits), since.Format("Mon Jan 2 15:04")))
+
+	lines := make([]string, 0, len(commits))
+	for _, c := range commits {
+		lines = append(lines, fmt.Sprintf("[%s] %s", c.Branch, describeCommits([]git.CommitInfo{c.CommitInfo})[0]))
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Writing your standup update"))
+	update, err := llmClient.GenerateStandup(lines, currentBranch)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to generate standup update: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", update))
+
+	if standupCopy {
+		if err := ui.CopyToClipboard(update); err != nil {
+			report.Add("update could not be copied: %v", err)
+		} else {
+			ui.ShowInfo(i18n.T("Update copied to clipboard"))
+		}
+	}
+	return nil
+}
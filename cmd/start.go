@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/ui"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start <issue>",
+	Short: "Start a branch for a GitHub issue",
+	Long: `Fetches the issue, creates a branch named after it (e.g.
+123-fix-login-redirect) and switches to it. Staged and unstaged changes
+are carried over.
+
+The issue is linked to the branch, so the PR that vibe pr opens from it
+closes the issue. Issues of the upstream repository are used when the
+remote is a fork.
+
+No message is generated, so no OpenAI API key is needed.`,
+	Example: `  vibe start 123
+  vibe start #123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStart,
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd)
+}
+
+func runStart(cmd *cobra.Command, args []string) error {
+	number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || number <= 0 {
+		return fmt.Errorf(i18n.T("invalid issue number %q"), args[0])
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+	repo.UseRemote(cfg.PR.Remote)
+
+	remoteURL, err := repo.GetRemoteURL()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get remote URL: %w"), err)
+	}
+
+	remote, err := openForge(cfg, remoteURL)
+	if err != nil {
+		return err
+	}
+	defer remote.showQuota()
+
+	// Issues of a fork usually live upstream, where its PRs are opened
+	if err := resolveUpstream(repo, remote, ""); err != nil {
+		return fmt.Errorf(i18n.T("failed to detect upstream repository: %w"), err)
+	}
+
+	issue, err := remote.Forge.GetIssue(remote.Base.Owner, remote.Base.Name, number)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to fetch issue #%d: %w"), number, err)
+	}
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Issue #%d: %s"), issue.Number, issue.Title))
+	if issue.State == "closed" {
+		ui.ShowInfo(i18n.T("Note: this issue is already closed"))
+	}
+
+	result, err := ui.ConfirmBranch(github.IssueBranchName(issue.Number, issue.Title))
+	if err != nil {
+		return err
+	}
+	if result.Action == ui.ActionCancel {
+		ui.ShowInfo(i18n.T("Branch not created."))
+		return errCancelled
+	}
+
+	if err := repo.CreateBranch(result.Name); err != nil {
+		return err
+	}
+	if err := repo.LinkIssue(result.Name, issue.Number); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Switched to new branch %s for #%d"), result.Name, issue.Number))
+	fmt.Printf("\n  %s\n", issue.URL)
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	stashUntracked bool
+	stashMessage   string
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Stash your changes under an AI-generated description",
+	Long: `Stashes the staged and unstaged changes like git stash, with a message
+describing the work in progress, e.g. "WIP: migrating auth middleware to
+context", instead of git's "WIP on main: <last commit>".
+
+  vibe stash            # stash tracked changes
+  vibe stash -u         # include untracked files
+  vibe stash list       # show the stashes with their messages
+
+Restore a stash with git stash pop or git stash apply. Stashing always
+uses the git binary, whatever the configured backend.
+
+Requirements:
+- Must be in a git repository, with git installed
+- OPENAI_API_KEY environment variable must be set, unless --message is used`,
+	Args: cobra.NoArgs,
+	RunE: runStash,
+}
+
+var stashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the stashes with their messages",
+	Args:  cobra.NoArgs,
+	RunE:  runStashList,
+}
+
+func init() {
+	stashCmd.Flags().BoolVarP(&stashUntracked, "include-untracked", "u", false, "Stash untracked files too")
+	stashCmd.Flags().StringVarP(&stashMessage, "message", "m", "", "Use this stash message instead of generating one")
+	stashCmd.AddCommand(stashListCmd)
+	rootCmd.AddCommand(stashCmd)
+}
+
+func runStash(cmd *cobra.Command, args []string) error {
+	if stashMessage == "" {
+		if err := checkOpenAIKey(); err != nil {
+			return err
+		}
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	// git stash saves both the staged and the unstaged changes
+	staged, err := repo.GetStagedDiff()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+	}
+	unstaged, err := repo.WorktreeDiff(stashUntracked)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get unstaged changes: %w"), err)
+	}
+
+	diff := staged + unstaged
+	if strings.TrimSpace(diff) == "" {
+		hint := ""
+		if !stashUntracked {
+			hint = i18n.T("\n\nTo stash untracked files too, use:\n  vibe stash --include-untracked")
+		}
+		return withExitCode(ExitNoChanges, fmt.Errorf(i18n.T("no local changes to stash%s"), hint))
+	}
+
+	message := stashMessage
+	if message == "" {
+		diff, _ = git.FilterDiff(diff, cfg.Paths.Exclude)
+		if strings.TrimSpace(diff) == "" {
+			return errors.New(i18n.T(`all changes are excluded by path rules
+
+Name the stash yourself:
+  vibe stash -m "WIP: ..."`))
+		}
+
+		llmClient, err := llm.NewClient(cfg)
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+		}
+
+		stop := ui.ShowSpinner(i18n.T("Describing your changes"))
+		message, err = llmClient.GenerateStashMessage(diff)
+		stop()
+		if err != nil {
+			return fmt.Errorf(i18n.T("failed to generate stash message: %w"), err)
+		}
+	}
+
+	if err := repo.Stash(message, stashUntracked); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Stashed: %s"), message))
+	fmt.Println(i18n.T("\n  Restore it with: git stash pop"))
+	return nil
+}
+
+func runStashList(cmd *cobra.Command, args []string) error {
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	entries, err := repo.StashList()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		ui.ShowInfo(i18n.T("No stashes"))
+		return nil
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s  %-16s %s\n", e.Ref, e.Age, e.Message)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	summarizeBase string
+	summarizeCopy bool
+)
+
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: "Summarize the current branch in one paragraph",
+	Long: `Writes a one-paragraph summary of everything on the current branch since
+it forked from its base branch, from its commits and diff. Handy for
+filling in tickets and status updates.
+
+Requirements:
+- Must be in a git repository, on a branch other than the base branch
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runSummarize,
+}
+
+func init() {
+	summarizeCmd.Flags().StringVarP(&summarizeBase, "base", "B", "", "Branch to compare against (default: main or master)")
+	summarizeCmd.Flags().BoolVarP(&summarizeCopy, "copy", "c", false, "Copy the summary to the clipboard")
+	_ = summarizeCmd.RegisterFlagCompletionFunc("base", completeBranches)
+	rootCmd.AddCommand(summarizeCmd)
+}
+
+func runSummarize(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	currentBranch, err := repo.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get current branch: %w"), err)
+	}
+
+	baseBranch := summarizeBase
+	if baseBranch == "" {
+		if baseBranch, err = repo.GetDefaultBranch(); err != nil {
+			return fmt.Errorf(i18n.T("failed to detect base branch: %w"), err)
+		}
+	}
+	if currentBranch == baseBranch {
+		return fmt.Errorf(i18n.T(`nothing to summarize on the %s branch
+
+Switch to a feature branch, or compare against another base:
+  vibe summarize --base <branch>`), baseBranch)
+	}
+
+	commits, err := repo.GetCommitsAhead(baseBranch)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get commits: %w"), err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf(i18n.T("no commits ahead of %s"), baseBranch)
+	}
+
+	diff, err := repo.GetDiffFromBase(baseBranch)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get diff: %w"), err)
+	}
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	diff = prepareDiff(diff, cfg, report)
+
+	ui.ShowInfo(fmt.Sprintf(i18n.T("Summarizing %d commit(s) on '%s' since '%s'..."), len(commits), currentBranch, baseBranch))
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Summarizing the branch"))
+	summary, err := llmClient.SummarizeBranch(strings.Join(describeCommits(commits), "\n"), diff)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to summarize branch: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", summary))
+
+	if summarizeCopy {
+		if err := ui.CopyToClipboard(summary); err != nil {
+			report.Add("summary could not be copied: %v", err)
+		} else {
+			ui.ShowInfo(i18n.T("Summary copied to clipboard"))
+		}
+	}
+	return nil
+}
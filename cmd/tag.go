@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/github"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	tagPush    bool
+	tagMessage string
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <name>",
+	Short: "Create an annotated tag with AI-written release notes",
+	Long: `Summarizes the commits since the previous tag into the message of an
+annotated tag, shows it for review, and creates the tag at HEAD.
+
+With --push, the tag is pushed to the remote afterwards (pr.remote in the
+config, or origin).
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set, unless --message is used`,
+	Example: `  vibe tag v1.4.0
+  vibe tag v1.4.0 --push`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTag,
+}
+
+func init() {
+	tagCmd.Flags().BoolVarP(&tagPush, "push", "p", false, "Push the tag to the remote once created")
+	tagCmd.Flags().StringVarP(&tagMessage, "message", "m", "", "Use this tag message instead of generating one")
+	rootCmd.AddCommand(tagCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if tagMessage == "" {
+		if err := checkOpenAIKey(); err != nil {
+			return err
+		}
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+	repo.UseRemote(cfg.PR.Remote)
+
+	// Fail before spending an API call on a name that can't be used
+	if repo.TagExists(name) {
+		return fmt.Errorf(i18n.T(`tag %s already exists
+
+Pick another name, or delete the existing tag first:
+  git tag -d %s`), name, name)
+	}
+
+	message := tagMessage
+	if message == "" {
+		message, err = generateTagMessage(repo, cfg, name)
+		if err != nil || message == "" {
+			return err
+		}
+	}
+
+	if err := repo.CreateTag(name, message); err != nil {
+		return err
+	}
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Created tag %s"), name))
+
+	if !tagPush {
+		fmt.Printf(i18n.T("\n  Push it with: git push %s %s\n"), repo.Remote(), name)
+		return nil
+	}
+
+	remoteURL, err := repo.GetRemoteURL()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to get remote URL: %w"), err)
+	}
+	// SSH and local remotes need no token
+	var token string
+	if host, err := github.RemoteHost(remoteURL); err == nil {
+		token, _ = githubToken(cfg, host)
+	}
+
+	if err := repo.PushTags(token, name); err != nil {
+		return err
+	}
+	ui.ShowSuccess(fmt.Sprintf(i18n.T("Pushed tag %s to %s"), name, repo.Remote()))
+	return nil
+}
+
+// generateTagMessage summarizes the commits since the previous tag and
+// lets the user review the message. It returns errCancelled if the user
+// cancels.
+func generateTagMessage(repo *git.Repository, cfg *config.Config, name string) (string, error) {
+	previous, err := repo.PreviousTag()
+	if err != nil {
+		return "", err
+	}
+
+	commits, err := repo.CommitsSinceTag(previous)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf(i18n.T(`no commits since %s
+
+HEAD is already tagged as %s. Name the tag yourself to tag it again:
+  vibe tag %s -m "..."`), previous, previous, name)
+	}
+
+	if previous != "" {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Summarizing %d commit(s) since %s..."), len(commits), previous))
+	} else {
+		ui.ShowInfo(fmt.Sprintf(i18n.T("Summarizing %d commit(s), there is no previous tag..."), len(commits)))
+	}
+
+	lines := make([]string, len(commits))
+	for i, c := range commits {
+		lines[i] = c.Hash + " " + c.Message
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+	stop := ui.ShowSpinner(fmt.Sprintf(i18n.T("Writing release notes for %s"), name))
+	message, err := llmClient.GenerateTagMessage(name, previous, lines)
+	stop()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to generate tag message: %w"), err)
+	}
+
+	result, err := ui.ConfirmTag(name, message)
+	if err != nil {
+		return "", err
+	}
+	if result.Action == ui.ActionCancel {
+		ui.ShowInfo(i18n.T("Tag not created."))
+		return "", errCancelled
+	}
+	return result.Message, nil
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/config"
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Stage, commit and open PRs in a full-screen interface",
+	Long: `Opens a full-screen interface with panes for the changed files, the diff of
+the selected file and the generated commit message or pull request, as an
+alternative to the step-by-step prompts of vibe commit and vibe pr.
+
+Keys:
+  up/down, j/k  select a file, or scroll the focused pane
+  space         stage or unstage the selected file
+  tab           switch pane
+  g             generate the commit message or PR again
+  e             edit it, esc to finish
+  p             switch between the commit message and the PR
+  c, enter      commit, or leave to push and open the PR
+  q             quit
+
+Opening the PR continues like vibe pr, with the title and description
+from the interface: the branch is pushed and the PR created or updated.
+
+Requirements:
+- Must be in a git repository, in a terminal
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+	commitSignoff = cfg.Commit.Signoff
+	prTestPlan, prRisk, prDiagram = cfg.PR.TestPlan, cfg.PR.Risk, cfg.PR.Diagram
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	// Notes on the last generation are shown once the TUI is closed
+	report := ui.NewReport()
+	defer func() { report.Show() }()
+
+	result, err := ui.RunTUI(ui.TUIActions{
+		Files: func() ([]ui.FileChoice, error) {
+			files, err := repo.ChangedFiles()
+			if err != nil {
+				return nil, err
+			}
+			choices := make([]ui.FileChoice, 0, len(files))
+			for _, f := range files {
+				choices = append(choices, ui.FileChoice{Path: f.Path, Status: f.Status, Selected: f.Staged()})
+			}
+			return choices, nil
+		},
+		Diff: func(path string) (string, error) {
+			staged, err := repo.GetStagedDiff()
+			if err != nil {
+				return "", fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+			}
+			unstaged, err := repo.WorktreeDiff(true)
+			if err != nil {
+				return "", fmt.Errorf(i18n.T("failed to get unstaged changes: %w"), err)
+			}
+			return git.FileDiff(staged, path) + git.FileDiff(unstaged, path), nil
+		},
+		Stage: func(path string, stage bool) error {
+			if stage {
+				return repo.StageFiles([]string{path})
+			}
+			return repo.UnstageFiles([]string{path})
+		},
+		GenerateCommit: func() (string, error) {
+			report = ui.NewReport()
+			return generateTUICommit(repo, cfg, llmClient, report)
+		},
+		Commit: func(message string) (string, error) {
+			hash, err := repo.Commit(message, commitOptions())
+			if err != nil {
+				return "", commitError(err)
+			}
+			return fmt.Sprintf(i18n.T("Committed: %s"), hash), nil
+		},
+		GeneratePR: func() (string, string, error) {
+			report = ui.NewReport()
+			return generateTUIPR(repo, cfg, llmClient, report)
+		},
+	})
+	if err != nil || result == nil || !result.CreatePR {
+		return err
+	}
+
+	prDraft = &llm.PRContent{Title: result.Title, Description: result.Description}
+	return runPR(prCmd, nil)
+}
+
+// generateTUICommit generates a commit message for the staged changes
+func generateTUICommit(repo *git.Repository, cfg *config.Config, llmClient *llm.Client, report *ui.Report) (string, error) {
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to get staged diff: %w"), err)
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "", errors.New(i18n.T("no staged changes found, press space to stage the selected file"))
+	}
+
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return "", errors.New(i18n.T("all staged changes are excluded by path rules"))
+	}
+
+	template, _ := repo.CommitTemplate()
+	message, _, err := generateCommitMessage(llmClient, diff, template)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to generate commit message: %w"), err)
+	}
+	return message, nil
+}
+
+// generateTUIPR generates a PR title and description for the current
+// branch from the local history. vibe pr fetches the base branch and
+// compares again before the PR is created.
+func generateTUIPR(repo *git.Repository, cfg *config.Config, llmClient *llm.Client, report *ui.Report) (string, string, error) {
+	branch, err := repo.GetCurrentBranch()
+	if err != nil {
+		return "", "", fmt.Errorf(i18n.T("failed to get current branch: %w"), err)
+	}
+	base, err := repo.GetDefaultBranch()
+	if err != nil {
+		return "", "", fmt.Errorf(i18n.T("failed to detect base branch: %w"), err)
+	}
+	if branch == base {
+		return "", "", fmt.Errorf(i18n.T("cannot create PR from %s branch, create a feature branch first"), base)
+	}
+
+	commits, diff, err := branchChanges(repo, nil, base, branch, false)
+	if err != nil {
+		return "", "", err
+	}
+	if len(commits) == 0 {
+		return "", "", fmt.Errorf(i18n.T("no commits ahead of %s, commit first"), base)
+	}
+
+	filtered, _ := git.FilterDiff(diff, cfg.Paths.Exclude)
+	_, deps := git.SummarizeDependencies(filtered)
+	diff = prepareDiff(diff, cfg, report)
+	if diff == "" {
+		return "", "", fmt.Errorf(i18n.T("all changes compared to %s are excluded by path rules"), base)
+	}
+
+	content, err := generatePRContent(repo, cfg, llmClient, branch, commits, diff, deps, "", "", report)
+	if err != nil {
+		return "", "", err
+	}
+	return content.Title, content.Description, nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+var (
+	watchInterval time.Duration
+	watchDebounce time.Duration
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep a commit message ready for the currently staged changes",
+	Long: `Watches the git index and regenerates a commit message whenever the staged
+changes settle.
+
+Generated messages are cached, so when you run 'vibe commit' afterwards the
+suggestion for the same staged changes appears instantly without another
+API call.
+
+Press Ctrl-C to stop watching.`,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 500*time.Millisecond, "How often to check the index for changes")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 2*time.Second, "How long the index must be unchanged before generating")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	ui.ShowInfo(i18n.T("Watching staged changes (Ctrl-C to stop)..."))
+
+	var (
+		lastSeen    time.Time // index mtime observed on the previous tick
+		lastChange  time.Time // when the index mtime last changed
+		lastHandled time.Time // index mtime we last generated a message for
+		lastDiff    string
+	)
+
+	for {
+		select {
+		case <-interrupt:
+			ui.ShowInfo(i18n.T("\nStopped watching."))
+			return nil
+
+		case now := <-ticker.C:
+			modTime, err := repo.IndexModTime()
+			if err != nil {
+				return fmt.Errorf(i18n.T("failed to watch index: %w"), err)
+			}
+
+			if !modTime.Equal(lastSeen) {
+				lastSeen = modTime
+				lastChange = now
+				continue
+			}
+
+			// Wait until the index has been stable for the debounce period
+			if modTime.Equal(lastHandled) || now.Sub(lastChange) < watchDebounce {
+				continue
+			}
+			lastHandled = modTime
+
+			diff, err := repo.GetStagedDiff()
+			if err != nil {
+				ui.ShowError(fmt.Errorf(i18n.T("failed to get staged diff: %w"), err))
+				continue
+			}
+
+			diff, _ = git.FilterDiff(diff, cfg.Paths.Exclude)
+			if diff == "" || diff == lastDiff {
+				continue
+			}
+			lastDiff = diff
+
+			ui.ShowInfo(i18n.T("Staged changes updated, generating commit message..."))
+
+			template, _ := repo.CommitTemplate()
+			message, _, err := generateCommitMessage(llmClient, diff, template)
+			if err != nil {
+				ui.ShowError(fmt.Errorf(i18n.T("failed to generate commit message: %w"), err))
+				continue
+			}
+
+			ui.ShowSuccess(fmt.Sprintf(i18n.T("Ready (%s):\n  %s"), now.Format("15:04:05"), message))
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/vibe/internal/git"
+	"github.com/user/vibe/internal/i18n"
+	"github.com/user/vibe/internal/llm"
+	"github.com/user/vibe/internal/ui"
+)
+
+const (
+	// whyMaxLines caps the lines that can be asked about at once
+	whyMaxLines = 200
+
+	// whyMaxCommits is how many of the commits behind the lines are sent to
+	// the model, those behind the most lines first
+	whyMaxCommits = 3
+)
+
+var whyCmd = &cobra.Command{
+	Use:   "why <file>:<line>[-<end>]",
+	Short: "Explain why a line is the way it is",
+	Long: `Finds the commits that last changed a line, or a range of lines, with git
+blame and explains the intent behind them from their messages and diffs.
+
+  vibe why internal/git/git.go:42
+  vibe why internal/git/git.go:40-60
+
+Lines are numbered as committed in HEAD, uncommitted changes are not taken
+into account.
+
+Requirements:
+- Must be in a git repository
+- OPENAI_API_KEY environment variable must be set`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhy,
+}
+
+func init() {
+	rootCmd.AddCommand(whyCmd)
+}
+
+func runWhy(cmd *cobra.Command, args []string) error {
+	if err := checkOpenAIKey(); err != nil {
+		return err
+	}
+
+	file, start, end, err := parseFileLines(args[0])
+	if err != nil {
+		return err
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf(i18n.T("not a git repository: %w"), err)
+	}
+
+	cfg, err := loadConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	path, err := repo.RepoPath(file)
+	if err != nil {
+		return err
+	}
+
+	lines, err := repo.Blame(path, start, end)
+	if err != nil {
+		return err
+	}
+
+	// Commits behind the most lines first, in line order otherwise
+	counts := make(map[string]int)
+	var hashes []string
+	for _, line := range lines {
+		if counts[line.Hash] == 0 {
+			hashes = append(hashes, line.Hash)
+		}
+		counts[line.Hash]++
+	}
+	sort.SliceStable(hashes, func(i, j int) bool {
+		return counts[hashes[i]] > counts[hashes[j]]
+	})
+
+	report := ui.NewReport()
+	defer report.Show()
+
+	if len(hashes) > whyMaxCommits {
+		report.Add("only the %d commits behind the most lines were explained, %d more were left out", whyMaxCommits, len(hashes)-whyMaxCommits)
+		hashes = hashes[:whyMaxCommits]
+	}
+
+	code := make([]string, 0, len(lines))
+	for _, line := range lines {
+		code = append(code, fmt.Sprintf("%s %4d  %s", line.Hash[:7], line.Number, line.Text))
+	}
+	fmt.Printf("\n%s\n\n", strings.Join(code, "\n"))
+
+	commits := make([]llm.LineCommit, 0, len(hashes))
+	for _, hash := range hashes {
+		details, err := repo.ShowCommit(hash)
+		if err != nil {
+			return err
+		}
+		// The commits share the model's limit, it cuts their diffs down
+		diff, _ := git.FilterDiff(details.Diff, cfg.Paths.Exclude)
+		commits = append(commits, llm.LineCommit{
+			Hash:    details.Hash,
+			Author:  details.Author,
+			Date:    details.When.Format("2006-01-02"),
+			Message: strings.TrimSpace(details.Message + "\n\n" + details.Body),
+			Diff:    diff,
+		})
+		ui.ShowInfo(fmt.Sprintf("%s %s (%s, %s)", details.Hash, details.Message, details.Author, details.When.Format("Jan 2, 2006")))
+	}
+
+	llmClient, err := llm.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to create AI client: %w"), err)
+	}
+
+	stop := ui.ShowSpinner(i18n.T("Explaining the lines"))
+	explanation, err := llmClient.ExplainLines(path, strings.Join(code, "\n"), commits)
+	stop()
+	if err != nil {
+		return fmt.Errorf(i18n.T("failed to explain lines: %w"), err)
+	}
+
+	ui.Page(fmt.Sprintf("\n%s\n", explanation))
+	return nil
+}
+
+// parseFileLines splits "<file>:<line>" or "<file>:<start>-<end>" into the
+// file and its line range
+func parseFileLines(arg string) (string, int, int, error) {
+	usage := fmt.Errorf(i18n.T(`expected <file>:<line> or <file>:<start>-<end>, got %q
+
+For example:
+  vibe why cmd/root.go:42
+  vibe why cmd/root.go:40-60`), arg)
+
+	file, spec, ok := cutLast(arg, ":")
+	if !ok || file == "" {
+		return "", 0, 0, usage
+	}
+	first, last, isRange := strings.Cut(spec, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 1 {
+		return "", 0, 0, usage
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return "", 0, 0, usage
+		}
+	}
+	if end-start+1 > whyMaxLines {
+		return "", 0, 0, fmt.Errorf(i18n.T("at most %d lines can be explained at once"), whyMaxLines)
+	}
+	return file, start, end, nil
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultGitHubHost is the host used when no explicit host is requested
+const DefaultGitHubHost = "github.com"
+
+// OpenAIKey returns the OpenAI API key from OPENAI_API_KEY or, failing
+// that, the OS keyring. An empty string is returned if no key could be found.
+func OpenAIKey() string {
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		return key
+	}
+	return keyringKey(KeyOpenAI)
+}
+
+// GitHubToken returns a token for github.com, see GitHubTokenForHost
+func GitHubToken() string {
+	return GitHubTokenForHost(DefaultGitHubHost, "")
+}
+
+// GitHubTokenForHost returns a token for the given GitHub host from the
+// following sources:
+// 1. GITHUB_TOKEN (github.com) or GH_ENTERPRISE_TOKEN (other hosts)
+// 2. configToken, the token configured for the host in vibe's config
+// 3. The OS keyring (github.com only, set with `vibe auth set-key github`)
+// 4. The gh CLI (`gh auth token --hostname <host>`)
+// 5. The gh CLI config file (~/.config/gh/hosts.yml)
+// An empty string is returned if no token could be found.
+func GitHubTokenForHost(host, configToken string) string {
+	if host == "" {
+		host = DefaultGitHubHost
+	}
+
+	if token := os.Getenv(tokenEnvVar(host)); token != "" {
+		return token
+	}
+
+	if configToken != "" {
+		return configToken
+	}
+
+	if host == DefaultGitHubHost {
+		if token := keyringKey(KeyGitHub); token != "" {
+			return token
+		}
+	}
+
+	if token := ghAuthToken(host); token != "" {
+		return token
+	}
+
+	return ghConfigToken(host)
+}
+
+// tokenEnvVar returns the environment variable holding the token for host
+func tokenEnvVar(host string) string {
+	if host == DefaultGitHubHost {
+		return "GITHUB_TOKEN"
+	}
+	return "GH_ENTERPRISE_TOKEN"
+}
+
+// ghAuthToken asks the gh CLI for its stored token for the given host
+func ghAuthToken(host string) string {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+
+	out, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// ghConfigToken reads the oauth_token for a host from the gh CLI hosts.yml.
+// Newer gh versions keep the token in the OS keyring, in which case the
+// file has no token and ghAuthToken is the only way to retrieve it.
+func ghConfigToken(host string) string {
+	data, err := os.ReadFile(ghHostsPath())
+	if err != nil {
+		return ""
+	}
+	return parseGHHosts(string(data), host)
+}
+
+// ghHostsPath returns the location of the gh CLI hosts.yml file
+func ghHostsPath() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh", "hosts.yml")
+}
+
+// parseGHHosts extracts the oauth_token for host from hosts.yml content.
+// The file is a simple two-level YAML map, so a line-based parse is enough:
+//
+//	github.com:
+//	    user: octocat
+//	    oauth_token: gho_xxx
+func parseGHHosts(content, host string) string {
+	inHost := false
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Top-level keys are host names
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inHost = strings.TrimSuffix(trimmed, ":") == host
+			continue
+		}
+
+		if inHost && strings.HasPrefix(trimmed, "oauth_token:") {
+			token := strings.TrimSpace(strings.TrimPrefix(trimmed, "oauth_token:"))
+			return strings.Trim(token, "\"'")
+		}
+	}
+
+	return ""
+}
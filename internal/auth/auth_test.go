@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"testing"
+)
+
+func TestParseGHHosts(t *testing.T) {
+	content := `github.com:
+    user: octocat
+    oauth_token: gho_public
+    git_protocol: https
+ghe.company.com:
+    oauth_token: "gho_enterprise"
+`
+
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{
+			name: "Default host",
+			host: "github.com",
+			want: "gho_public",
+		},
+		{
+			name: "Quoted token on enterprise host",
+			host: "ghe.company.com",
+			want: "gho_enterprise",
+		},
+		{
+			name: "Unknown host",
+			host: "gitlab.com",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseGHHosts(content, tt.host)
+			if got != tt.want {
+				t.Errorf("parseGHHosts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGHHostsWithoutToken(t *testing.T) {
+	// gh >= 2.40 stores tokens in the keyring and omits oauth_token
+	content := `github.com:
+    user: octocat
+    git_protocol: ssh
+`
+	if got := parseGHHosts(content, "github.com"); got != "" {
+		t.Errorf("parseGHHosts() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name vibe's secrets are stored under
+const keyringService = "vibe"
+
+// Names of the keys that can be stored in the OS keyring
+const (
+	KeyOpenAI = "openai"
+	KeyGitHub = "github"
+)
+
+// keyEnvVars maps keyring key names to the environment variables they replace
+var keyEnvVars = map[string]string{
+	KeyOpenAI: "OPENAI_API_KEY",
+	KeyGitHub: "GITHUB_TOKEN",
+}
+
+// KeyNames returns the names of all keys that can be stored in the keyring
+func KeyNames() []string {
+	names := make([]string, 0, len(keyEnvVars))
+	for name := range keyEnvVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeyEnvVar returns the environment variable that takes precedence over the
+// keyring entry for name
+func KeyEnvVar(name string) string {
+	return keyEnvVars[name]
+}
+
+// SetKey stores a secret in the OS keyring (macOS Keychain, Windows
+// Credential Manager or the Secret Service on Linux)
+func SetKey(name, value string) error {
+	if err := validateKeyName(name); err != nil {
+		return err
+	}
+	if err := keyring.Set(keyringService, name, value); err != nil {
+		return fmt.Errorf("failed to store key in OS keyring: %w", err)
+	}
+	return nil
+}
+
+// DeleteKey removes a secret from the OS keyring
+func DeleteKey(name string) error {
+	if err := validateKeyName(name); err != nil {
+		return err
+	}
+	err := keyring.Delete(keyringService, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("no %s key is stored in the OS keyring", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete key from OS keyring: %w", err)
+	}
+	return nil
+}
+
+// keyringKey returns a secret from the OS keyring, or an empty string if it
+// is not stored or the keyring is unavailable
+func keyringKey(name string) string {
+	value, err := keyring.Get(keyringService, name)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// validateKeyName checks that name is a known key
+func validateKeyName(name string) error {
+	if _, ok := keyEnvVars[name]; !ok {
+		return fmt.Errorf("unknown key %q (expected one of: %s)", name, strings.Join(KeyNames(), ", "))
+	}
+	return nil
+}
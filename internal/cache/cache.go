@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxAge is how long an entry is kept before New prunes it
+const maxAge = 30 * 24 * time.Hour
+
+// Cache stores generated content on disk keyed by the input that produced it
+type Cache struct {
+	dir string
+}
+
+// New creates a cache in the user cache directory (e.g. ~/.cache/vibe)
+func New() (*Cache, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return NewAt(filepath.Join(base, "vibe"))
+}
+
+// NewAt creates a cache rooted at the given directory, pruning entries
+// older than maxAge
+func NewAt(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	c := &Cache{dir: dir}
+	c.prune(time.Now().Add(-maxAge))
+	return c, nil
+}
+
+// Key builds a cache key from the given parts (e.g. model name and diff)
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached value for key, if present
+func (c *Cache) Get(key string) (string, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Put stores value under key
+func (c *Cache) Put(key, value string) error {
+	if err := os.WriteFile(c.path(key), []byte(value), 0o600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// prune removes the entries last written before cutoff. Other files in the
+// directory, such as the debug log, are left alone.
+func (c *Cache) prune(cutoff time.Time) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isKey(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		_ = os.Remove(filepath.Join(c.dir, e.Name()))
+	}
+}
+
+// isKey reports whether name looks like a key built by Key
+func isKey(name string) bool {
+	if len(name) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+// path returns the file path for a cache key
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
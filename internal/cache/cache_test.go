@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	c, err := NewAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewAt() error = %v", err)
+	}
+
+	key := Key("gpt-4o", "diff --git a/main.go b/main.go")
+
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() on empty cache returned a value")
+	}
+
+	if err := c.Put(key, "Add main entrypoint"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, ok := c.Get(key)
+	if !ok || got != "Add main entrypoint" {
+		t.Errorf("Get() = %q, %v, want %q, true", got, ok, "Add main entrypoint")
+	}
+}
+
+func TestKeyDependsOnAllParts(t *testing.T) {
+	if Key("a", "bc") == Key("ab", "c") {
+		t.Error("Key() should not collide when parts are re-split")
+	}
+	if Key("model", "diff") != Key("model", "diff") {
+		t.Error("Key() should be deterministic")
+	}
+}
+
+func TestNewPrunesOldEntries(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewAt(dir)
+	if err != nil {
+		t.Fatalf("NewAt() error = %v", err)
+	}
+
+	fresh, stale := Key("fresh"), Key("stale")
+	for _, key := range []string{fresh, stale} {
+		if err := c.Put(key, key); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "debug.log"), []byte("log"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-maxAge - time.Hour)
+	for _, name := range []string{stale, "debug.log"} {
+		if err := os.Chtimes(filepath.Join(dir, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err = NewAt(dir)
+	if err != nil {
+		t.Fatalf("NewAt() error = %v", err)
+	}
+	if _, ok := c.Get(fresh); !ok {
+		t.Error("NewAt() pruned an entry younger than maxAge")
+	}
+	if _, ok := c.Get(stale); ok {
+		t.Error("NewAt() kept an entry older than maxAge")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "debug.log")); err != nil {
+		t.Errorf("NewAt() removed a file that is not a cache entry: %v", err)
+	}
+}
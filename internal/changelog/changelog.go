@@ -0,0 +1,144 @@
+// Package changelog renders changelog sections and maintains CHANGELOG.md
+// files
+package changelog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FileName is the conventional name of the changelog file
+const FileName = "CHANGELOG.md"
+
+// Unreleased is the version heading for changes not released yet
+const Unreleased = "Unreleased"
+
+const (
+	// FormatKeepAChangelog follows https://keepachangelog.com
+	FormatKeepAChangelog = "keepachangelog"
+
+	// FormatConventional follows the conventional-changelog output used by
+	// tools such as standard-version and release-please
+	FormatConventional = "conventional"
+)
+
+// Formats lists the valid changelog formats
+var Formats = []string{FormatKeepAChangelog, FormatConventional}
+
+// Kind is the kind of change an entry describes
+type Kind int
+
+const (
+	Breaking Kind = iota
+	Feature
+	Fix
+	Other
+)
+
+// Entry is a single line of a changelog section
+type Entry struct {
+	Kind Kind
+	Text string
+}
+
+// headings are the subsection headings of each kind, per format
+var headings = map[string][]string{
+	FormatKeepAChangelog: {Breaking: "Breaking Changes", Feature: "Added", Fix: "Fixed", Other: "Changed"},
+	FormatConventional:   {Breaking: "⚠ BREAKING CHANGES", Feature: "Features", Fix: "Bug Fixes", Other: "Other Changes"},
+}
+
+// Render renders the changelog section for version released on date, with
+// the entries grouped by kind, breaking changes first
+func Render(format, version string, date time.Time, entries []Entry) (string, error) {
+	titles, ok := headings[format]
+	if !ok {
+		return "", fmt.Errorf("unknown changelog format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+
+	var b strings.Builder
+	b.WriteString(heading(format, version, date))
+	b.WriteString("\n")
+
+	bullet := "-"
+	if format == FormatConventional {
+		bullet = "*"
+	}
+	for kind := Breaking; kind <= Other; kind++ {
+		var lines []string
+		for _, e := range entries {
+			if e.Kind == kind {
+				lines = append(lines, fmt.Sprintf("%s %s", bullet, e.Text))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", titles[kind], strings.Join(lines, "\n"))
+	}
+	return b.String(), nil
+}
+
+// heading returns the version heading of a section. Unreleased sections
+// have no date.
+func heading(format, version string, date time.Time) string {
+	day := date.Format("2006-01-02")
+	switch {
+	case format == FormatKeepAChangelog && version == Unreleased:
+		return "## [Unreleased]"
+	case format == FormatKeepAChangelog:
+		return fmt.Sprintf("## [%s] - %s", strings.TrimPrefix(version, "v"), day)
+	case version == Unreleased:
+		return "## Unreleased"
+	default:
+		return fmt.Sprintf("## %s (%s)", strings.TrimPrefix(version, "v"), day)
+	}
+}
+
+// Prepend adds section to the changelog content above the latest release,
+// below any title and introduction. A section with the same heading, such as
+// an earlier Unreleased section, is replaced. Empty content starts a new
+// changelog.
+func Prepend(content, section string) string {
+	section = strings.TrimRight(section, "\n") + "\n"
+	if strings.TrimSpace(content) == "" {
+		return "# Changelog\n\n" + section
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	title := strings.TrimSpace(strings.SplitN(section, "\n", 2)[0])
+
+	// The first version heading is where the new section goes
+	start := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			start = i
+			break
+		}
+	}
+
+	// An existing section with the same heading ends at the next one
+	end := start
+	if start < len(lines) && strings.TrimSpace(lines[start]) == title {
+		end = start + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+			end++
+		}
+	}
+
+	var b strings.Builder
+	before := strings.Join(lines[:start], "")
+	b.WriteString(before)
+	if before != "" && !strings.HasSuffix(before, "\n\n") {
+		if !strings.HasSuffix(before, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(section)
+	if end < len(lines) {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(lines[end:], ""))
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	date := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Kind: Fix, Text: "Fix tag pushes over SSH"},
+		{Kind: Feature, Text: "Add vibe stash"},
+		{Kind: Breaking, Text: "Drop the --draft flag"},
+		{Kind: Feature, Text: "Add vibe tag"},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		version string
+		entries []Entry
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Keep a Changelog release",
+			format:  FormatKeepAChangelog,
+			version: "v1.4.0",
+			entries: entries,
+			want: "## [1.4.0] - 2026-10-16\n\n### Breaking Changes\n\n- Drop the --draft flag\n\n" +
+				"### Added\n\n- Add vibe stash\n- Add vibe tag\n\n### Fixed\n\n- Fix tag pushes over SSH\n",
+		},
+		{
+			name:    "Conventional release",
+			format:  FormatConventional,
+			version: "1.4.0",
+			entries: entries[:2],
+			want:    "## 1.4.0 (2026-10-16)\n\n### Features\n\n* Add vibe stash\n\n### Bug Fixes\n\n* Fix tag pushes over SSH\n",
+		},
+		{
+			name:    "Unreleased",
+			format:  FormatKeepAChangelog,
+			version: Unreleased,
+			entries: []Entry{{Kind: Other, Text: "Speed up diffs"}},
+			want:    "## [Unreleased]\n\n### Changed\n\n- Speed up diffs\n",
+		},
+		{
+			name:    "Unknown format",
+			format:  "markdown",
+			version: Unreleased,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, tt.version, date, tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	section := "## [1.1.0] - 2026-10-16\n\n### Added\n\n- New\n"
+
+	tests := []struct {
+		name    string
+		content string
+		section string
+		want    string
+	}{
+		{
+			name:    "New changelog",
+			content: "",
+			section: section,
+			want:    "# Changelog\n\n" + section,
+		},
+		{
+			name:    "Above the latest release, below the intro",
+			content: "# Changelog\n\nAll notable changes.\n\n## [1.0.0] - 2026-01-01\n\n- Old\n",
+			section: section,
+			want:    "# Changelog\n\nAll notable changes.\n\n" + section + "\n## [1.0.0] - 2026-01-01\n\n- Old\n",
+		},
+		{
+			name:    "No releases yet",
+			content: "# Changelog\n",
+			section: section,
+			want:    "# Changelog\n\n" + section,
+		},
+		{
+			name:    "Same heading is replaced",
+			content: "# Changelog\n\n## [Unreleased]\n\n- Stale\n\n## [1.0.0] - 2026-01-01\n\n- Old\n",
+			section: "## [Unreleased]\n\n- Fresh\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n- Fresh\n\n## [1.0.0] - 2026-01-01\n\n- Old\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prepend(tt.content, tt.section); got != tt.want {
+				t.Errorf("Prepend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
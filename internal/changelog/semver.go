@@ -0,0 +1,55 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Semver bump levels
+const (
+	Major = "major"
+	Minor = "minor"
+	Patch = "patch"
+)
+
+// versionPattern matches semver tags with an optional "v" prefix and an
+// optional pre-release or build suffix
+var versionPattern = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)(-[^+]*)?(\+.*)?$`)
+
+// NextVersion bumps version by level, keeping its "v" prefix. Before 1.0.0
+// breaking changes bump the minor version, as most projects do, since a
+// major bump would declare the API stable. A pre-release is released as is
+// when that is already the requested bump, e.g. a patch of v2.0.0-rc.1 is
+// v2.0.0.
+func NextVersion(version, level string) (string, error) {
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf("%s is not a semantic version such as v1.2.3", version)
+	}
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	patch, _ := strconv.Atoi(m[4])
+
+	if m[5] != "" {
+		released := fmt.Sprintf("%s%d.%d.%d", m[1], major, minor, patch)
+		switch {
+		case level == Patch,
+			level == Minor && patch == 0,
+			level == Major && minor == 0 && patch == 0:
+			return released, nil
+		}
+	}
+
+	switch {
+	case level == Major && major > 0:
+		major, minor, patch = major+1, 0, 0
+	case level == Major, level == Minor:
+		minor, patch = minor+1, 0
+	case level == Patch:
+		patch++
+	default:
+		return "", fmt.Errorf("unknown bump level %q (use %s, %s or %s)", level, Major, Minor, Patch)
+	}
+	return fmt.Sprintf("%s%d.%d.%d", m[1], major, minor, patch), nil
+}
@@ -0,0 +1,34 @@
+package changelog
+
+import "testing"
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		level   string
+		want    string
+		wantErr bool
+	}{
+		{version: "v1.4.2", level: Major, want: "v2.0.0"},
+		{version: "v1.4.2", level: Minor, want: "v1.5.0"},
+		{version: "1.4.2", level: Patch, want: "1.4.3"},
+		{version: "v0.3.1", level: Major, want: "v0.4.0"},
+		{version: "v2.0.0-rc.1", level: Patch, want: "v2.0.0"},
+		{version: "v2.0.0-rc.1", level: Major, want: "v2.0.0"},
+		{version: "v2.1.3-beta+build.5", level: Minor, want: "v2.2.0"},
+		{version: "release-7", level: Patch, wantErr: true},
+		{version: "v1.0.0", level: "huge", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.level, func(t *testing.T) {
+			got, err := NextVersion(tt.version, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NextVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion is the current configuration bundle format version
+const BundleVersion = 1
+
+// bundleHeader is written at the top of exported bundles
+const bundleHeader = "# vibe configuration bundle\n# Import with: vibe config import <file>\n"
+
+// Bundle is a shareable snapshot of a vibe configuration, including prompts
+// and path rules. It never contains secrets.
+type Bundle struct {
+	Version int    `yaml:"version"`
+	Config  Config `yaml:"config"`
+}
+
+// Export writes cfg as a configuration bundle to w, without its secrets
+func Export(w io.Writer, cfg *Config) error {
+	data, err := marshal(&Bundle{
+		Version: BundleVersion,
+		Config:  *cfg.Shareable(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, bundleHeader); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// bundleFile is a bundle as read by Import. Its config is kept as YAML so
+// only the settings the bundle has are applied.
+type bundleFile struct {
+	Version int       `yaml:"version"`
+	Config  yaml.Node `yaml:"config"`
+}
+
+// Import reads a configuration bundle from r and merges it into cfg, like
+// .vibe.yaml over the global config: the bundle's settings replace those of
+// cfg, even false or empty ones, and the settings it leaves out are kept.
+// Host tokens are never imported, those of cfg are kept.
+func Import(r io.Reader, cfg *Config) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var bundle bundleFile
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	if bundle.Version == 0 {
+		return fmt.Errorf("invalid bundle: missing version")
+	}
+	if bundle.Version > BundleVersion {
+		return fmt.Errorf("bundle version %d is newer than supported version %d - please upgrade vibe", bundle.Version, BundleVersion)
+	}
+
+	if bundle.Config.IsZero() {
+		return nil
+	}
+
+	tokens := make(map[string]string, len(cfg.Hosts))
+	for name, host := range cfg.Hosts {
+		tokens[name] = host.Token
+	}
+	if err := bundle.Config.Decode(cfg); err != nil {
+		return fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	// Never import secrets, even from a hand-edited bundle, and keep the
+	// tokens cfg already has
+	for name, host := range cfg.Hosts {
+		host.Token = tokens[name]
+		cfg.Hosts[name] = host
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoFileName is the name of the per-repository config file, placed in the
+// repository root and meant to be committed so the whole team shares it
+const RepoFileName = ".vibe.yaml"
+
+// Config holds vibe's user and repository settings.
+// The only secrets it may hold are per-host tokens, which are stripped by
+// Shareable before a config is exported.
+type Config struct {
+	// Model is the OpenAI model used for generation
+	Model string `yaml:"model,omitempty"`
+
+	// Locale is the language of vibe's own messages, e.g. "de" (default:
+	// from LC_ALL, LC_MESSAGES or LANG). Generated content isn't affected.
+	Locale string `yaml:"locale,omitempty"`
+
+	// Prompts overrides the built-in system prompts
+	Prompts Prompts `yaml:"prompts,omitempty"`
+
+	// Paths controls which files are sent to the model
+	Paths Paths `yaml:"paths,omitempty"`
+
+	// PR holds settings for the pr command
+	PR PR `yaml:"pr,omitempty"`
+
+	// Commit holds settings for the commit command
+	Commit Commit `yaml:"commit,omitempty"`
+
+	// Branch holds settings for the branch command
+	Branch Branch `yaml:"branch,omitempty"`
+
+	// Standup holds settings for the standup command
+	Standup Standup `yaml:"standup,omitempty"`
+
+	// Git holds settings for how git operations are run
+	Git Git `yaml:"git,omitempty"`
+
+	// Hooks holds settings for the git hooks vibe installs
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Keys remaps the keys of prompts and vibe tui
+	Keys Keys `yaml:"keys,omitempty"`
+
+	// Hosts holds per-host settings keyed by hostname, e.g. "github.com"
+	// and "ghe.company.com" for a GitHub Enterprise instance
+	Hosts map[string]Host `yaml:"hosts,omitempty"`
+}
+
+// PR holds settings for pull request creation
+type PR struct {
+	// LinkIssues controls whether issues referenced by the branch name or
+	// commits are linked with closing keywords (default: true)
+	LinkIssues *bool `yaml:"link_issues,omitempty"`
+
+	// ClosingKeyword is the keyword used to link issues, e.g. "Fixes" or
+	// "Resolves" (default: "Closes")
+	ClosingKeyword string `yaml:"closing_keyword,omitempty"`
+
+	// Web opens the PR in the browser after it is created or updated
+	Web bool `yaml:"web,omitempty"`
+
+	// Copy copies the PR URL to the clipboard after it is created or updated
+	Copy bool `yaml:"copy,omitempty"`
+
+	// Remote is the git remote branches are pushed to (default: "origin")
+	Remote string `yaml:"remote,omitempty"`
+
+	// Sections are the headings generated descriptions are made of, in
+	// order, e.g. Summary, Changes, Testing. They take precedence over the
+	// repository's pull request template.
+	Sections []string `yaml:"sections,omitempty"`
+
+	// TestPlan adds a "How to test" section to generated descriptions
+	TestPlan bool `yaml:"test_plan,omitempty"`
+
+	// Risk adds a low/medium/high risk rating with its reasons to generated
+	// descriptions
+	Risk bool `yaml:"risk,omitempty"`
+
+	// HotPaths are glob patterns of critical or performance-sensitive code,
+	// e.g. "internal/billing/". Changes to them weigh on the risk rating.
+	HotPaths []string `yaml:"hot_paths,omitempty"`
+
+	// Diagram adds a Mermaid diagram of the modules touched to generated
+	// descriptions of larger changes
+	Diagram bool `yaml:"diagram,omitempty"`
+
+	// UIPaths are glob patterns of frontend files, whose changes get a
+	// Screenshots section (default: DefaultUIPaths). A single empty
+	// pattern ("") disables it.
+	UIPaths []string `yaml:"ui_paths,omitempty"`
+}
+
+// DefaultUIPaths are the patterns of frontend files used when none are
+// configured
+var DefaultUIPaths = []string{
+	"*.css", "*.scss", "*.sass", "*.less", "*.html",
+	"*.jsx", "*.tsx", "*.vue", "*.svelte",
+	"frontend/", "web/", "ui/",
+}
+
+// UIPatterns returns the configured frontend file patterns or the defaults
+func (p PR) UIPatterns() []string {
+	if len(p.UIPaths) == 0 {
+		return DefaultUIPaths
+	}
+	var patterns []string
+	for _, pattern := range p.UIPaths {
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// Commit holds settings for creating commits
+type Commit struct {
+	// Signoff adds a Signed-off-by trailer to every commit (--signoff), as
+	// projects enforcing the DCO require
+	Signoff bool `yaml:"signoff,omitempty"`
+}
+
+// DefaultBranchPrefixes are the branch name prefixes used when none are
+// configured
+var DefaultBranchPrefixes = []string{"feat/", "fix/", "chore/", "docs/", "refactor/"}
+
+// Branch holds settings for naming new branches
+type Branch struct {
+	// Prefixes lists the prefixes a generated branch name may start with,
+	// e.g. "feat/" and "fix/" (default: DefaultBranchPrefixes). A single
+	// empty prefix ("") disables prefixes.
+	Prefixes []string `yaml:"prefixes,omitempty"`
+}
+
+// BranchPrefixes returns the configured branch prefixes or the defaults
+func (b Branch) BranchPrefixes() []string {
+	if len(b.Prefixes) == 0 {
+		return DefaultBranchPrefixes
+	}
+	var prefixes []string
+	for _, p := range b.Prefixes {
+		if p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+// Standup holds settings for standup summaries
+type Standup struct {
+	// Since is the time window commits are collected from, e.g. "24h" or
+	// "2d" (default: "yesterday", the start of the previous working day)
+	Since string `yaml:"since,omitempty"`
+
+	// Author is the email or name whose commits are collected (default:
+	// the git user.email)
+	Author string `yaml:"author,omitempty"`
+}
+
+// Git holds settings for how git operations are run
+type Git struct {
+	// Backend runs status, diff, commit, push and fetch with "go-git"
+	// (the default, no git install needed) or "git", the installed git
+	// binary, which honors hooks and credential helpers
+	Backend string `yaml:"backend,omitempty"`
+}
+
+// Hooks holds settings for the git hooks vibe installs
+type Hooks struct {
+	// CommitPattern is a regular expression every pushed commit subject
+	// must match, e.g. for Conventional Commits. Empty disables the check.
+	CommitPattern string `yaml:"commit_pattern,omitempty"`
+
+	// BlockSecrets rejects pushes whose commits add likely credentials
+	// (default: true)
+	BlockSecrets *bool `yaml:"block_secrets,omitempty"`
+}
+
+// ShouldBlockSecrets reports whether pushes adding secrets are rejected
+func (h Hooks) ShouldBlockSecrets() bool {
+	return h.BlockSecrets == nil || *h.BlockSecrets
+}
+
+// ShouldLinkIssues reports whether detected issues should be linked
+func (p PR) ShouldLinkIssues() bool {
+	return p.LinkIssues == nil || *p.LinkIssues
+}
+
+// Keys remaps the keys of prompts and vibe tui. Each action lists the keys
+// that trigger it, e.g. ["y"] or ["ctrl+y", "enter"]; empty lists keep the
+// defaults.
+type Keys struct {
+	// Accept, Edit, Regenerate and Cancel pick that choice in prompts, and
+	// commit, edit and regenerate in vibe tui
+	Accept     []string `yaml:"accept,omitempty"`
+	Edit       []string `yaml:"edit,omitempty"`
+	Regenerate []string `yaml:"regenerate,omitempty"`
+	Cancel     []string `yaml:"cancel,omitempty"`
+
+	// Up and Down move through choices and files
+	Up   []string `yaml:"up,omitempty"`
+	Down []string `yaml:"down,omitempty"`
+
+	// Stage and Quit stage a file and leave vibe tui
+	Stage []string `yaml:"stage,omitempty"`
+	Quit  []string `yaml:"quit,omitempty"`
+}
+
+// Host holds the settings for a single forge host
+type Host struct {
+	// Forge is the backend serving this host, "github" (the default)
+	// for github.com and GitHub Enterprise instances
+	Forge string `yaml:"forge,omitempty"`
+
+	// Token is the API token used for this host
+	Token string `yaml:"token,omitempty"`
+}
+
+// Prompts holds custom system prompts, empty values use the built-in prompts
+type Prompts struct {
+	Commit string `yaml:"commit,omitempty"`
+	PR     string `yaml:"pr,omitempty"`
+}
+
+// Paths holds path rules applied to diffs before they are sent to the model
+type Paths struct {
+	// Exclude lists glob patterns (e.g. "*.lock", "vendor/*") of files
+	// whose changes are left out of the prompt
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// HostToken returns the configured token for host, if any
+func (c *Config) HostToken(host string) string {
+	return c.Hosts[host].Token
+}
+
+// HostNames returns the names of all configured hosts
+func (c *Config) HostNames() []string {
+	names := make([]string, 0, len(c.Hosts))
+	for name := range c.Hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shareable returns a copy of the config with all secrets removed.
+// Host entries are kept so the list of known hosts is still shared.
+func (c *Config) Shareable() *Config {
+	shared := *c
+	if c.Hosts != nil {
+		shared.Hosts = make(map[string]Host, len(c.Hosts))
+		for name, host := range c.Hosts {
+			host.Token = ""
+			shared.Hosts[name] = host
+		}
+	}
+	return &shared
+}
+
+// GlobalPath returns the location of the user's config file
+// (e.g. ~/.config/vibe/config.yaml)
+func GlobalPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "vibe", "config.yaml"), nil
+}
+
+// RepoPath returns the location of the config file for a repository root
+func RepoPath(root string) string {
+	return filepath.Join(root, RepoFileName)
+}
+
+// Load reads the global config and, if repoRoot is not empty, layers the
+// repository's .vibe.yaml on top of it. Missing files are not an error.
+func Load(repoRoot string) (*Config, error) {
+	cfg := &Config{}
+
+	globalPath, err := GlobalPath()
+	if err == nil {
+		if err := loadInto(cfg, globalPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if repoRoot != "" {
+		path := RepoPath(repoRoot)
+		if err := checkNoTokens(path); err != nil {
+			return nil, err
+		}
+		if err := loadInto(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// checkNoTokens rejects host tokens in the repository's config file, which
+// is meant to be committed
+func checkNoTokens(path string) error {
+	repoCfg, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, name := range repoCfg.HostNames() {
+		if repoCfg.HostToken(name) != "" {
+			return fmt.Errorf(`%s has a token for %s, tokens would be committed with it
+
+Move the token to the global config (~/.config/vibe/config.yaml), or set
+GITHUB_TOKEN or GH_ENTERPRISE_TOKEN instead`, path, name)
+		}
+	}
+	return nil
+}
+
+// LoadFile reads a single config file
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+	if err := loadInto(cfg, path); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadInto decodes the file at path over cfg, so that only the settings
+// present in the file replace what cfg already holds
+func loadInto(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return nil
+}
+
+// Save writes cfg to path, creating parent directories as needed
+func Save(path string, cfg *Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	// The config may contain tokens, keep it private
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+	return nil
+}
+
+// marshal encodes v as YAML with two-space indentation
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
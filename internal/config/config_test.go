@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadLayersRepoConfigOverGlobal(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", configHome)
+
+	globalPath, err := GlobalPath()
+	if err != nil {
+		t.Fatalf("GlobalPath() error = %v", err)
+	}
+	writeFile(t, globalPath, `model: gpt-4o-mini
+prompts:
+  commit: Global commit prompt
+  pr: Global PR prompt
+`)
+
+	repoRoot := t.TempDir()
+	writeFile(t, RepoPath(repoRoot), `prompts:
+  commit: Team commit prompt
+paths:
+  exclude:
+    - "*.lock"
+`)
+
+	cfg, err := Load(repoRoot)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Model != "gpt-4o-mini" {
+		t.Errorf("Model = %q, want global value", cfg.Model)
+	}
+	if cfg.Prompts.Commit != "Team commit prompt" {
+		t.Errorf("Prompts.Commit = %q, want repo value", cfg.Prompts.Commit)
+	}
+	if cfg.Prompts.PR != "Global PR prompt" {
+		t.Errorf("Prompts.PR = %q, want global value", cfg.Prompts.PR)
+	}
+	if len(cfg.Paths.Exclude) != 1 || cfg.Paths.Exclude[0] != "*.lock" {
+		t.Errorf("Paths.Exclude = %v, want [*.lock]", cfg.Paths.Exclude)
+	}
+}
+
+func TestLoadRejectsTokensInRepoConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	repoRoot := t.TempDir()
+	writeFile(t, RepoPath(repoRoot), `hosts:
+  ghe.company.com:
+    token: ghe_secret
+`)
+	if _, err := Load(repoRoot); err == nil {
+		t.Error("Load() with a token in .vibe.yaml should fail")
+	}
+
+	// Hosts without tokens are fine to share
+	writeFile(t, RepoPath(repoRoot), `hosts:
+  ghe.company.com: {}
+`)
+	if _, err := Load(repoRoot); err != nil {
+		t.Errorf("Load() error = %v", err)
+	}
+}
+
+func TestLoadMissingFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Model != "" {
+		t.Errorf("Model = %q, want empty", cfg.Model)
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	cfg := &Config{
+		Model:   "gpt-4o",
+		Prompts: Prompts{Commit: "Use conventional commits.\nKeep it short."},
+		Paths:   Paths{Exclude: []string{"vendor/*", "*.pb.go"}},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "# vibe configuration bundle") {
+		t.Errorf("Export() output missing header:\n%s", buf.String())
+	}
+
+	got := &Config{}
+	if err := Import(&buf, got); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if got.Model != cfg.Model || got.Prompts.Commit != cfg.Prompts.Commit {
+		t.Errorf("Import() = %+v, want %+v", got, cfg)
+	}
+	if len(got.Paths.Exclude) != 2 || got.Paths.Exclude[1] != "*.pb.go" {
+		t.Errorf("Import() Paths.Exclude = %v, want %v", got.Paths.Exclude, cfg.Paths.Exclude)
+	}
+}
+
+func TestImportRejectsInvalidBundles(t *testing.T) {
+	tests := []struct {
+		name   string
+		bundle string
+	}{
+		{
+			name:   "Missing version",
+			bundle: "config:\n  model: gpt-4o\n",
+		},
+		{
+			name:   "Newer version",
+			bundle: "version: 99\nconfig:\n  model: gpt-4o\n",
+		},
+		{
+			name:   "Not YAML",
+			bundle: "version: [",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Import(strings.NewReader(tt.bundle), &Config{}); err == nil {
+				t.Error("Import() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestImportMerges(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		bundle string
+		check  func(*Config) bool
+	}{
+		{
+			name:   "Bundle setting replaces the local one",
+			config: Config{Model: "gpt-4o-mini"},
+			bundle: "  model: gpt-4o\n",
+			check:  func(c *Config) bool { return c.Model == "gpt-4o" },
+		},
+		{
+			name:   "Settings the bundle leaves out are kept",
+			config: Config{Model: "gpt-4o-mini", Prompts: Prompts{Commit: "Be brief."}},
+			bundle: "  prompts:\n    pr: Add a test plan.\n",
+			check: func(c *Config) bool {
+				return c.Model == "gpt-4o-mini" && c.Prompts.Commit == "Be brief." && c.Prompts.PR == "Add a test plan."
+			},
+		},
+		{
+			name:   "Empty list clears the local one",
+			config: Config{Paths: Paths{Exclude: []string{"vendor/*"}}},
+			bundle: "  paths:\n    exclude: []\n",
+			check:  func(c *Config) bool { return len(c.Paths.Exclude) == 0 },
+		},
+		{
+			name:   "False turns a local setting off",
+			config: Config{Commit: Commit{Signoff: true}},
+			bundle: "  commit:\n    signoff: false\n",
+			check:  func(c *Config) bool { return !c.Commit.Signoff },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			bundle := "version: 1\nconfig:\n" + tt.bundle
+			if err := Import(strings.NewReader(bundle), &cfg); err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if !tt.check(&cfg) {
+				t.Errorf("Import() gave %+v", cfg)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportStripsHostTokens(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]Host{
+			"github.com":      {Token: "ghp_secret"},
+			"ghe.company.com": {Token: "ghe_secret"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("Export() leaked a token:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "ghe.company.com") {
+		t.Errorf("Export() should keep host entries:\n%s", buf.String())
+	}
+
+	// The original config must be left untouched
+	if cfg.HostToken("github.com") != "ghp_secret" {
+		t.Errorf("Export() modified the original config")
+	}
+}
+
+func TestImportKeepsHostTokens(t *testing.T) {
+	cfg := &Config{Hosts: map[string]Host{"github.com": {Token: "ghp_mine"}}}
+
+	bundle := `version: 1
+config:
+  hosts:
+    github.com:
+      token: ghp_theirs
+    ghe.company.com:
+      token: ghe_theirs
+`
+	if err := Import(strings.NewReader(bundle), cfg); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if cfg.HostToken("github.com") != "ghp_mine" {
+		t.Errorf("token = %q, want the existing token kept", cfg.HostToken("github.com"))
+	}
+	if cfg.HostToken("ghe.company.com") != "" {
+		t.Errorf("imported a token from the bundle: %q", cfg.HostToken("ghe.company.com"))
+	}
+	if _, ok := cfg.Hosts["ghe.company.com"]; !ok {
+		t.Error("Import() should keep the bundle's host entries")
+	}
+}
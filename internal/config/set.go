@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Setting types, see Setting
+const (
+	TypeString = "string"
+	TypeBool   = "bool"
+	TypeList   = "list"
+)
+
+// Setting is a single setting that can be changed with Set, named by its
+// YAML keys joined with dots, e.g. "pr.remote"
+type Setting struct {
+	Key  string
+	Type string
+}
+
+// Settings returns the settings that can be changed with Set, in the order
+// of the config file. Hosts are left out, as they are keyed by host name.
+func Settings() []Setting {
+	var settings []Setting
+	walkSettings(reflect.ValueOf(&Config{}).Elem(), "", func(key string, field reflect.Value) {
+		settings = append(settings, Setting{Key: key, Type: settingType(field)})
+	})
+	return settings
+}
+
+// Set changes the setting key to value. Booleans are written like "true"
+// or "false", lists separated by commas, and an empty value clears the
+// setting.
+func (c *Config) Set(key, value string) error {
+	var target reflect.Value
+	walkSettings(reflect.ValueOf(c).Elem(), "", func(k string, field reflect.Value) {
+		if k == key {
+			target = field
+		}
+	})
+	if !target.IsValid() {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	switch settingType(target) {
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s, want true or false", value, key)
+		}
+		if target.Kind() == reflect.Pointer {
+			target.Set(reflect.ValueOf(&b))
+		} else {
+			target.SetBool(b)
+		}
+	case TypeList:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		target.Set(reflect.ValueOf(items))
+	default:
+		target.SetString(value)
+	}
+	return nil
+}
+
+// walkSettings calls fn with the key and value of each setting in v, a
+// struct with YAML tags
+func walkSettings(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			walkSettings(field, key+".", fn)
+		case reflect.String, reflect.Bool, reflect.Slice:
+			fn(key, field)
+		case reflect.Pointer:
+			if field.Type().Elem().Kind() == reflect.Bool {
+				fn(key, field)
+			}
+		}
+	}
+}
+
+// settingType returns the type of a setting's field
+func settingType(field reflect.Value) string {
+	switch field.Kind() {
+	case reflect.Bool, reflect.Pointer:
+		return TypeBool
+	case reflect.Slice:
+		return TypeList
+	}
+	return TypeString
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	types := make(map[string]string)
+	for _, s := range Settings() {
+		types[s.Key] = s.Type
+	}
+
+	want := map[string]string{
+		"model":           TypeString,
+		"pr.remote":       TypeString,
+		"pr.link_issues":  TypeBool,
+		"pr.web":          TypeBool,
+		"pr.sections":     TypeList,
+		"prompts.commit":  TypeString,
+		"keys.accept":     TypeList,
+		"commit.signoff":  TypeBool,
+		"standup.since":   TypeString,
+		"branch.prefixes": TypeList,
+	}
+	for key, typ := range want {
+		if types[key] != typ {
+			t.Errorf("Settings() type of %s = %q, want %q", key, types[key], typ)
+		}
+	}
+	for key := range types {
+		if strings.HasPrefix(key, "hosts") {
+			t.Errorf("Settings() includes %s, hosts can't be set", key)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+		check   func(*Config) bool
+	}{
+		{key: "model", value: "gpt-4o-mini", check: func(c *Config) bool { return c.Model == "gpt-4o-mini" }},
+		{key: "pr.remote", value: " upstream ", check: func(c *Config) bool { return c.PR.Remote == "upstream" }},
+		{key: "pr.web", value: "true", check: func(c *Config) bool { return c.PR.Web }},
+		{key: "pr.link_issues", value: "false", check: func(c *Config) bool { return c.PR.LinkIssues != nil && !*c.PR.LinkIssues }},
+		{key: "pr.sections", value: "Summary, Testing,", check: func(c *Config) bool {
+			return strings.Join(c.PR.Sections, "|") == "Summary|Testing"
+		}},
+		{key: "standup.since", value: "", check: func(c *Config) bool { return c.Standup.Since == "" }},
+		{key: "pr.web", value: "sometimes", wantErr: true},
+		{key: "pr", value: "x", wantErr: true},
+		{key: "hosts.github.com.token", value: "x", wantErr: true},
+		{key: "unknown", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := &Config{Standup: Standup{Since: "2d"}}
+			err := cfg.Set(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil && !tt.check(cfg) {
+				t.Errorf("Set(%q, %q) gave %+v", tt.key, tt.value, cfg)
+			}
+		})
+	}
+}
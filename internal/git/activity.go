@@ -0,0 +1,270 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// AuthoredCommit is a commit with its author and the branch it was found
+// on
+type AuthoredCommit struct {
+	CommitInfo
+
+	Branch string
+	Author string
+	Email  string
+	When   time.Time
+}
+
+// ParseSince parses a time window such as "yesterday", "today", "24h",
+// "3d", "1w", "1y" or "2024-05-01" into the time it starts at. "yesterday" is
+// the start of the previous working day, so on Mondays it covers Friday.
+// "all" covers the whole history and gives the zero time.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case "all":
+		return time.Time{}, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		day := midnight.AddDate(0, 0, -1)
+		for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			day = day.AddDate(0, 0, -1)
+		}
+		return day, nil
+	}
+
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return day, nil
+	}
+
+	if len(value) > 1 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n > 0 {
+			switch value[len(value)-1] {
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, -n), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n), nil
+			case 'y':
+				return now.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time window %q (use e.g. yesterday, 24h, 3d, 1w, 1y, 2024-05-01 or all)", value)
+}
+
+// User returns the name and email commits are authored with
+func (r *Repository) User() (name, email string) {
+	return getAuthorInfo(r)
+}
+
+// CommitsSince returns the commits authored since the given time on any
+// local branch, and with remotes on any remote-tracking branch too, newest
+// first. Each commit is listed once, preferably on the current branch,
+// then on a local one. A non-empty author keeps only commits whose author
+// email equals it or whose author name contains it, ignoring case. Merge
+// commits are left out.
+func (r *Repository) CommitsSince(since time.Time, author string, remotes bool) ([]AuthoredCommit, error) {
+	branches, err := r.branchRefs(remotes)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	var commits []AuthoredCommit
+	for _, branch := range branches {
+		iter, err := r.repo.Log(&git.LogOptions{From: branch.Hash(), Order: git.LogOrderCommitterTime})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get log of %s: %w", branch.Name().Short(), err)
+		}
+
+		for {
+			c, err := iter.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				iter.Close()
+				return nil, fmt.Errorf("failed to walk history: %w", err)
+			}
+			// Commits come newest first by commit time, which is never
+			// before the author time
+			if c.Committer.When.Before(since) {
+				break
+			}
+			if seen[c.Hash] {
+				continue
+			}
+			seen[c.Hash] = true
+
+			if c.NumParents() > 1 || c.Author.When.Before(since) || !authoredBy(c.Author, author) {
+				continue
+			}
+			commits = append(commits, AuthoredCommit{
+				CommitInfo: newCommitInfo(c),
+				Branch:     branch.Name().Short(),
+				Author:     c.Author.Name,
+				Email:      c.Author.Email,
+				When:       c.Author.When,
+			})
+		}
+		iter.Close()
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].When.After(commits[j].When)
+	})
+	return commits, nil
+}
+
+// branchRefs returns the local branches, the current one first, followed
+// by the remote-tracking branches with remotes
+func (r *Repository) branchRefs(remotes bool) ([]*plumbing.Reference, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	current, _ := r.GetCurrentBranch()
+	var local, remote []*plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		// Symbolic refs such as origin/HEAD point at a branch listed anyway
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		switch {
+		case ref.Name().IsBranch() && ref.Name().Short() == current:
+			local = append([]*plumbing.Reference{ref}, local...)
+		case ref.Name().IsBranch():
+			local = append(local, ref)
+		case ref.Name().IsRemote() && remotes:
+			remote = append(remote, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	return append(local, remote...), nil
+}
+
+// authoredBy reports whether sig matches author, see CommitsSince
+func authoredBy(sig object.Signature, author string) bool {
+	if author == "" {
+		return true
+	}
+	return strings.EqualFold(sig.Email, author) ||
+		strings.Contains(strings.ToLower(sig.Name), strings.ToLower(author))
+}
+
+// BranchActivity is a branch with its latest commit
+type BranchActivity struct {
+	Name    string
+	Author  string
+	Subject string
+	When    time.Time
+}
+
+// ActiveBranches returns the local and remote-tracking branches with a
+// commit since the given time, most recently active first. Remote-tracking
+// branches at the same commit as their local branch are left out.
+func (r *Repository) ActiveBranches(since time.Time) ([]BranchActivity, error) {
+	refs, err := r.branchRefs(true)
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(map[string]plumbing.Hash)
+	var branches []BranchActivity
+	for _, ref := range refs {
+		name := ref.Name().Short()
+		if ref.Name().IsRemote() {
+			// Remote-tracking branches come last, after all local ones
+			_, branch, _ := strings.Cut(name, "/")
+			if hash, ok := local[branch]; ok && hash == ref.Hash() {
+				continue
+			}
+		} else {
+			local[name] = ref.Hash()
+		}
+
+		c, err := r.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the latest commit of %s: %w", name, err)
+		}
+		if c.Committer.When.Before(since) {
+			continue
+		}
+		branches = append(branches, BranchActivity{
+			Name:    name,
+			Author:  c.Author.Name,
+			Subject: newCommitInfo(c).Message,
+			When:    c.Committer.When,
+		})
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].When.After(branches[j].When)
+	})
+	return branches, nil
+}
+
+// AuthorCount is an author with their number of commits
+type AuthorCount struct {
+	Name    string
+	Email   string
+	Commits int
+}
+
+// TopAuthors counts the commits of each author, most commits first. Authors
+// are told apart by email, ignoring case, and named as in their latest
+// commit.
+func TopAuthors(commits []AuthoredCommit) []AuthorCount {
+	index := make(map[string]int)
+	var authors []AuthorCount
+	for _, c := range commits {
+		key := strings.ToLower(c.Email)
+		i, ok := index[key]
+		if !ok {
+			i = len(authors)
+			index[key] = i
+			authors = append(authors, AuthorCount{Name: c.Author, Email: c.Email})
+		}
+		authors[i].Commits++
+	}
+
+	sort.SliceStable(authors, func(i, j int) bool {
+		return authors[i].Commits > authors[j].Commits
+	})
+	return authors
+}
+
+// CommitsPerWeek counts the commits in each of the given number of weeks
+// before now, oldest week first
+func CommitsPerWeek(commits []AuthoredCommit, now time.Time, weeks int) []int {
+	counts := make([]int, weeks)
+	for _, c := range commits {
+		if c.When.After(now) {
+			continue
+		}
+		week := int(now.Sub(c.When) / (7 * 24 * time.Hour))
+		if week < weeks {
+			counts[weeks-1-week]++
+		}
+	}
+	return counts
+}
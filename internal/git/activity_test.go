@@ -0,0 +1,243 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestParseSince(t *testing.T) {
+	// A Monday
+	now := time.Date(2024, 5, 6, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"today", time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), false},
+		{" Yesterday ", time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), false},
+		{"24h", time.Date(2024, 5, 5, 15, 30, 0, 0, time.UTC), false},
+		{"3d", time.Date(2024, 5, 3, 15, 30, 0, 0, time.UTC), false},
+		{"1w", time.Date(2024, 4, 29, 15, 30, 0, 0, time.UTC), false},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"1y", time.Date(2023, 5, 6, 15, 30, 0, 0, time.UTC), false},
+		{"all", time.Time{}, false},
+		{"", time.Time{}, true},
+		{"0d", time.Time{}, true},
+		{"3m", time.Time{}, true},
+		{"last week", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	// On a Tuesday yesterday is Monday
+	tuesday := now.AddDate(0, 0, 1)
+	if got, _ := ParseSince("yesterday", tuesday); !got.Equal(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseSince(yesterday) on a Tuesday = %v, want Monday", got)
+	}
+}
+
+func TestCommitsSince(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+	since := now.Add(-time.Hour)
+
+	commitFile(t, repo, "old.txt", "old\n", now.Add(-2*time.Hour))
+	commitFile(t, repo, "a.txt", "a\n", now.Add(-30*time.Minute))
+	commitAs(t, repo, "other.txt", "Other Person", "other@example.com", now.Add(-20*time.Minute))
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature", Create: true}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	commitFile(t, repo, "b.txt", "b\n", now.Add(-10*time.Minute))
+
+	subjects := func(commits []AuthoredCommit) string {
+		var s []string
+		for _, c := range commits {
+			s = append(s, c.Branch+":"+c.Message)
+		}
+		return strings.Join(s, ",")
+	}
+
+	commits, err := repo.CommitsSince(since, "test@example.com", false)
+	if err != nil {
+		t.Fatalf("CommitsSince() error = %v", err)
+	}
+	// Commits on several branches are listed on the current one
+	if want := "feature:Update b.txt,feature:Update a.txt"; subjects(commits) != want {
+		t.Errorf("CommitsSince() = %s, want %s", subjects(commits), want)
+	}
+
+	commits, err = repo.CommitsSince(since, "other", false)
+	if err != nil {
+		t.Fatalf("CommitsSince() error = %v", err)
+	}
+	if want := "feature:Add other.txt"; subjects(commits) != want {
+		t.Errorf("CommitsSince(other) = %s, want %s", subjects(commits), want)
+	}
+
+	commits, err = repo.CommitsSince(since, "", false)
+	if err != nil {
+		t.Fatalf("CommitsSince() error = %v", err)
+	}
+	if len(commits) != 3 {
+		t.Errorf("CommitsSince() for all authors = %s, want 3 commits", subjects(commits))
+	}
+
+	// Commits only on remote-tracking branches are listed with remotes
+	local := headHash(t, repo)
+	commitAs(t, repo, "remote.txt", "Other Person", "other@example.com", now.Add(-5*time.Minute))
+	remoteRef := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "feature"), headHash(t, repo))
+	if err := repo.repo.Storer.SetReference(remoteRef); err != nil {
+		t.Fatalf("SetReference() error = %v", err)
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: local, Mode: git.HardReset}); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	if commits, err = repo.CommitsSince(since, "other", false); err != nil || len(commits) != 1 {
+		t.Errorf("CommitsSince(other) without remotes = %s, %v, want 1 commit", subjects(commits), err)
+	}
+	commits, err = repo.CommitsSince(since, "other", true)
+	if err != nil {
+		t.Fatalf("CommitsSince() error = %v", err)
+	}
+	if want := "origin/feature:Add remote.txt,feature:Add other.txt"; subjects(commits) != want {
+		t.Errorf("CommitsSince(other) with remotes = %s, want %s", subjects(commits), want)
+	}
+}
+
+func TestActiveBranches(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+
+	head, err := repo.repo.Head()
+	if err != nil {
+		t.Fatalf("Head() error = %v", err)
+	}
+	mainBranch := head.Name().Short()
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: "refs/heads/stale", Create: true}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	commitFile(t, repo, "old.txt", "old\n", now.Add(-30*24*time.Hour))
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature", Create: true}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	commitFile(t, repo, "a.txt", "a\n", now.Add(-time.Hour))
+
+	// A remote-tracking branch at the local commit is left out, one that
+	// is ahead is listed
+	for name, hash := range map[string]plumbing.Hash{"feature": headHash(t, repo), mainBranch: headHash(t, repo)} {
+		ref := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", name), hash)
+		if err := repo.repo.Storer.SetReference(ref); err != nil {
+			t.Fatalf("SetReference() error = %v", err)
+		}
+	}
+
+	branches, err := repo.ActiveBranches(now.Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("ActiveBranches() error = %v", err)
+	}
+	var names []string
+	for _, b := range branches {
+		names = append(names, b.Name)
+	}
+	// The initial commit is the newest, the stale branch is left out
+	got := strings.Join(names, ",")
+	want := mainBranch + ",feature,origin/" + mainBranch
+	if got != want {
+		t.Errorf("ActiveBranches() = %s, want %s", got, want)
+	}
+	if branches[1].Subject != "Update a.txt" || branches[1].Author != "Test" {
+		t.Errorf("ActiveBranches()[1] = %+v, want the latest commit of feature", branches[1])
+	}
+}
+
+func TestTopAuthors(t *testing.T) {
+	commits := []AuthoredCommit{
+		{Author: "Ann Lee", Email: "ann@example.com"},
+		{Author: "Bob", Email: "bob@example.com"},
+		{Author: "Ann", Email: "ANN@example.com"},
+		{Author: "Bob", Email: "bob@example.com"},
+		{Author: "Bob", Email: "bob@example.com"},
+	}
+
+	authors := TopAuthors(commits)
+	if len(authors) != 2 {
+		t.Fatalf("TopAuthors() = %+v, want 2 authors", authors)
+	}
+	if authors[0].Name != "Bob" || authors[0].Commits != 3 {
+		t.Errorf("TopAuthors()[0] = %+v, want Bob with 3 commits", authors[0])
+	}
+	if authors[1].Name != "Ann Lee" || authors[1].Commits != 2 {
+		t.Errorf("TopAuthors()[1] = %+v, want Ann Lee with 2 commits", authors[1])
+	}
+}
+
+func TestCommitsPerWeek(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	commits := []AuthoredCommit{
+		{When: now.Add(-time.Hour)},
+		{When: now.Add(-6 * day)},
+		{When: now.Add(-8 * day)},
+		{When: now.Add(-22 * day)},
+		{When: now.Add(-40 * day)},
+		{When: now.Add(time.Hour)},
+	}
+
+	got := CommitsPerWeek(commits, now, 4)
+	want := []int{1, 0, 1, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CommitsPerWeek() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+// commitAs commits a file authored by name and email
+func commitAs(t *testing.T, repo *Repository, file, name, email string, when time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(repo.Root(), file), []byte(file), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if _, err := worktree.Add(file); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	sig := &object.Signature{Name: name, Email: email, When: when}
+	if _, err := worktree.Commit("Add "+file, &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// sshKeyFiles are the default private keys tried when no SSH agent is
+// running, in the order OpenSSH tries them
+var sshKeyFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// IsSSHURL reports whether a remote URL uses the SSH transport, either as
+// ssh://host/path or scp-style user@host:path
+func IsSSHURL(remoteURL string) bool {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	return err == nil && endpoint.Protocol == "ssh"
+}
+
+// remoteAuth returns the credentials for pushing to remoteURL: the SSH
+// agent or the user's default keys for SSH remotes, token for HTTPS and
+// none for local remotes
+func remoteAuth(remoteURL, token string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote URL %s: %w", remoteURL, err)
+	}
+
+	switch endpoint.Protocol {
+	case "ssh":
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		return sshAuth(user)
+
+	case "http", "https":
+		if token == "" {
+			return nil, fmt.Errorf("no GitHub token available for push")
+		}
+		return &http.BasicAuth{
+			Username: "x-access-token", // GitHub uses this for token auth
+			Password: token,
+		}, nil
+
+	default:
+		// Local paths and git:// need no credentials
+		return nil, nil
+	}
+}
+
+// sshAuth authenticates as user with the running SSH agent, or with the
+// first unencrypted default key in ~/.ssh
+func sshAuth(user string) (transport.AuthMethod, error) {
+	if os.Getenv("SSH_AUTH_SOCK") != "" {
+		if auth, err := ssh.NewSSHAgentAuth(user); err == nil {
+			return auth, nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err == nil {
+		for _, name := range sshKeyFiles {
+			path := filepath.Join(home, ".ssh", name)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			// Keys protected by a passphrase fail here, they need the agent
+			if auth, err := ssh.NewPublicKeysFromFile(user, path, ""); err == nil {
+				return auth, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf(`no usable SSH key found for push
+
+Start an SSH agent and add your key:
+  eval "$(ssh-agent)"
+  ssh-add ~/.ssh/id_ed25519`)
+}
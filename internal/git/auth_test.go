@@ -0,0 +1,32 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestIsSSHURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "git@github.com:owner/repo.git", want: true},
+		{url: "ssh://git@github.com:2222/owner/repo.git", want: true},
+		{url: "https://github.com/owner/repo.git", want: false},
+		{url: "http://ghe.company.com/team/service", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsSSHURL(tt.url); got != tt.want {
+			t.Errorf("IsSSHURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRemoteAuthRequiresTokenForHTTPS(t *testing.T) {
+	if _, err := remoteAuth("https://github.com/owner/repo.git", ""); err == nil {
+		t.Error("remoteAuth() for HTTPS without token should fail")
+	}
+	if _, err := remoteAuth("https://github.com/owner/repo.git", "token"); err != nil {
+		t.Errorf("remoteAuth() unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// BlamedLine is a line of a file with the commit that last changed it
+type BlamedLine struct {
+	Number int
+	Text   string
+
+	// Hash is the full hash of the commit that last changed the line
+	Hash string
+}
+
+// RepoPath turns a path relative to the working directory into the
+// slash-separated path from the repository root that git uses
+func (r *Repository) RepoPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// Compare resolved paths, the root may be reached through a symlink
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(resolved, filepath.Base(abs))
+	}
+	root := r.Root()
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%s is outside the repository", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// Blame returns the lines start to end (1-based, inclusive) of the file at
+// path, as committed in HEAD, with the commits that last changed them, like
+// git blame -L start,end HEAD
+func (r *Repository) Blame(path string, start, end int) ([]BlamedLine, error) {
+	if start < 1 || end < start {
+		return nil, fmt.Errorf("invalid line range %d-%d", start, end)
+	}
+	if r.system {
+		return r.systemBlame(path, start, end)
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	if _, err := commit.File(path); err != nil {
+		return nil, fmt.Errorf("%s is not in the last commit: %w", path, err)
+	}
+
+	result, err := git.Blame(commit, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", path, err)
+	}
+	if end > len(result.Lines) {
+		return nil, fmt.Errorf("%s has only %d lines", path, len(result.Lines))
+	}
+
+	lines := make([]BlamedLine, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		line := result.Lines[n-1]
+		lines = append(lines, BlamedLine{Number: n, Text: line.Text, Hash: line.Hash.String()})
+	}
+	return lines, nil
+}
+
+func (r *Repository) systemBlame(path string, start, end int) ([]BlamedLine, error) {
+	output, err := r.runGit("", "blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", start, end), "HEAD", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", path, err)
+	}
+	lines, err := parseBlamePorcelain(output)
+	if err != nil {
+		return nil, err
+	}
+	// git cuts ranges running past the end short
+	if len(lines) < end-start+1 {
+		return nil, fmt.Errorf("%s has only %d lines", path, start-1+len(lines))
+	}
+	return lines, nil
+}
+
+// parseBlamePorcelain parses the output of git blame --porcelain. Each line
+// starts with a "<hash> <original line> <final line> [<group size>]" header,
+// followed by commit details the first time a commit appears, and the
+// line's content prefixed with a tab.
+func parseBlamePorcelain(output string) ([]BlamedLine, error) {
+	var lines []BlamedLine
+	var current *BlamedLine
+	for _, text := range strings.Split(output, "\n") {
+		if strings.HasPrefix(text, "\t") {
+			if current == nil {
+				return nil, fmt.Errorf("unexpected git blame output: %q", text)
+			}
+			current.Text = text[1:]
+			lines = append(lines, *current)
+			current = nil
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if current != nil || len(fields) < 3 || len(fields[0]) != len(zeroHash) {
+			// Commit details such as "author" and "summary"
+			continue
+		}
+		number, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		current = &BlamedLine{Number: number, Hash: fields[0]}
+	}
+	return lines, nil
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBlame(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+	commitFile(t, repo, "a.go", "one\ntwo\n", now.Add(time.Minute))
+	first := headHash(t, repo).String()
+	commitFile(t, repo, "a.go", "one\nTWO\nthree\n", now.Add(2*time.Minute))
+	second := headHash(t, repo).String()
+
+	backends := []bool{false}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, true)
+	}
+	for _, system := range backends {
+		repo.system = system
+
+		lines, err := repo.Blame("a.go", 1, 3)
+		if err != nil {
+			t.Fatalf("Blame() error = %v", err)
+		}
+		want := []BlamedLine{
+			{Number: 1, Text: "one", Hash: first},
+			{Number: 2, Text: "TWO", Hash: second},
+			{Number: 3, Text: "three", Hash: second},
+		}
+		if len(lines) != len(want) {
+			t.Fatalf("Blame() = %+v, want %+v", lines, want)
+		}
+		for i := range want {
+			if lines[i] != want[i] {
+				t.Errorf("Blame()[%d] = %+v, want %+v (system %v)", i, lines[i], want[i], system)
+			}
+		}
+
+		if _, err := repo.Blame("a.go", 2, 9); err == nil {
+			t.Errorf("Blame() past the end succeeded, want an error (system %v)", system)
+		}
+		if _, err := repo.Blame("missing.go", 1, 1); err == nil {
+			t.Errorf("Blame() of a missing file succeeded, want an error (system %v)", system)
+		}
+	}
+}
+
+func TestParseBlamePorcelain(t *testing.T) {
+	hash := "1234567890123456789012345678901234567890"
+	output := hash + " 3 5 2\n" +
+		"author Test\n" +
+		"summary Fix the 1234567890123456789012345678901234567890 bug\n" +
+		"filename a.go\n" +
+		"\tfirst line\n" +
+		hash + " 4 6\n" +
+		"\t\tindented\n"
+
+	lines, err := parseBlamePorcelain(output)
+	if err != nil {
+		t.Fatalf("parseBlamePorcelain() error = %v", err)
+	}
+	want := []BlamedLine{
+		{Number: 5, Text: "first line", Hash: hash},
+		{Number: 6, Text: "\tindented", Hash: hash},
+	}
+	if len(lines) != len(want) || lines[0] != want[0] || lines[1] != want[1] {
+		t.Errorf("parseBlamePorcelain() = %+v, want %+v", lines, want)
+	}
+}
+
+func TestRepoPath(t *testing.T) {
+	repo := newTestRepo(t)
+	sub := filepath.Join(repo.Root(), "internal")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+	t.Chdir(sub)
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"a.go", "internal/a.go", false},
+		{"../README.md", "README.md", false},
+		{filepath.Join(repo.Root(), "cmd", "x.go"), "cmd/x.go", false},
+		{"../../elsewhere.go", "", true},
+	}
+	for _, tt := range tests {
+		got, err := repo.RepoPath(tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("RepoPath(%q) = %q, %v, want %q, error %v", tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
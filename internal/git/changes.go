@@ -0,0 +1,168 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// ChangedFile is a file with staged or unstaged changes, or an untracked
+// file
+type ChangedFile struct {
+	Path string
+
+	// Status is the two-letter code of git status --short: the staged
+	// change, then the unstaged one, e.g. "M " or "??"
+	Status string
+}
+
+// Staged reports whether the file has staged changes
+func (f ChangedFile) Staged() bool {
+	return f.Status[0] != ' ' && f.Status[0] != '?'
+}
+
+// Unstaged reports whether the file has changes that aren't staged,
+// untracked files included
+func (f ChangedFile) Unstaged() bool {
+	return f.Status[1] != ' '
+}
+
+// ChangedFiles returns the files with staged or unstaged changes and the
+// untracked files, ordered by path
+func (r *Repository) ChangedFiles() ([]ChangedFile, error) {
+	if r.system {
+		return r.systemChangedFiles()
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var files []ChangedFile
+	for path, s := range status {
+		if s.Staging == git.Unmodified && s.Worktree == git.Unmodified {
+			continue
+		}
+		files = append(files, ChangedFile{Path: path, Status: string([]byte{byte(s.Staging), byte(s.Worktree)})})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// StageFiles stages the worktree state of paths, including deletions
+func (r *Repository) StageFiles(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if r.system {
+		if _, err := r.runGit("", append([]string{"add", "--all", "--"}, paths...)...); err != nil {
+			return fmt.Errorf("failed to stage files: %w", err)
+		}
+		return r.reopen()
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	for _, path := range paths {
+		if _, err := worktree.Add(path); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// UnstageFiles resets the index entries of paths to HEAD, like git reset
+// -- <paths>. The worktree is not touched.
+func (r *Repository) UnstageFiles(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if r.system {
+		if _, err := r.runGit("", append([]string{"reset", "--quiet", "--"}, paths...)...); err != nil {
+			return fmt.Errorf("failed to unstage files: %w", err)
+		}
+		return r.reopen()
+	}
+
+	headFiles, err := r.headFiles()
+	if err != nil {
+		return err
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to get index: %w", err)
+	}
+
+	for _, path := range paths {
+		head, inHead := headFiles[path]
+		entry, err := idx.Entry(path)
+		switch {
+		case err != nil && !inHead:
+			// Neither staged nor committed
+		case !inHead:
+			// A staged new file becomes untracked again
+			if _, err := idx.Remove(path); err != nil {
+				return fmt.Errorf("failed to unstage %s: %w", path, err)
+			}
+		default:
+			if err != nil {
+				// A staged deletion is restored
+				entry = idx.Add(path)
+			}
+			entry.Hash, entry.Mode = head.hash, head.mode
+			// Make git recheck the file's content instead of trusting the
+			// cached stat data, which belongs to the staged version
+			entry.Size, entry.ModifiedAt = 0, time.Time{}
+		}
+	}
+
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// headFiles returns the files in the HEAD tree by path, or none before the
+// first commit
+func (r *Repository) headFiles() (map[string]*diffFile, error) {
+	tree, err := r.headTree()
+	if err != nil || tree == nil {
+		return map[string]*diffFile{}, err
+	}
+	return treeFiles(tree)
+}
+
+func (r *Repository) systemChangedFiles() ([]ChangedFile, error) {
+	output, err := r.runGit("", "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []ChangedFile
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if len(field) < 4 {
+			continue
+		}
+		files = append(files, ChangedFile{Path: field[3:], Status: field[:2]})
+		// Renames and copies are followed by their source path
+		if field[0] == 'R' || field[0] == 'C' {
+			i++
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
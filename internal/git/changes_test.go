@@ -0,0 +1,62 @@
+package git
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChangedFilesAndUnstage(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "gone.txt", "bye\n", time.Now())
+	root := repo.Root()
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	write("README.md", "changed\n")
+	write("new.txt", "new\n")
+	write("untracked.txt", "untracked\n")
+	if err := os.Remove(filepath.Join(root, "gone.txt")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := repo.StageFiles([]string{"README.md", "new.txt", "gone.txt"}); err != nil {
+		t.Fatalf("StageFiles() error = %v", err)
+	}
+
+	statuses := func() map[string]string {
+		t.Helper()
+		files, err := repo.ChangedFiles()
+		if err != nil {
+			t.Fatalf("ChangedFiles() error = %v", err)
+		}
+		got := make(map[string]string)
+		for _, f := range files {
+			got[f.Path] = f.Status
+		}
+		return got
+	}
+
+	want := map[string]string{"README.md": "M ", "gone.txt": "D ", "new.txt": "A ", "untracked.txt": "??"}
+	if got := statuses(); !maps.Equal(got, want) {
+		t.Fatalf("ChangedFiles() = %v, want %v", got, want)
+	}
+
+	if err := repo.UnstageFiles([]string{"README.md", "new.txt", "gone.txt"}); err != nil {
+		t.Fatalf("UnstageFiles() error = %v", err)
+	}
+
+	want = map[string]string{"README.md": " M", "gone.txt": " D", "new.txt": "??", "untracked.txt": "??"}
+	if got := statuses(); !maps.Equal(got, want) {
+		t.Errorf("ChangedFiles() after UnstageFiles() = %v, want %v", got, want)
+	}
+	if staged, err := repo.HasStagedChanges(); err != nil || staged {
+		t.Errorf("HasStagedChanges() = %v, %v, want false", staged, err)
+	}
+}
@@ -0,0 +1,169 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// maxChurnCommits caps the commits walked for churn, so long windows on big
+// histories stay fast
+const maxChurnCommits = 1000
+
+// hotspotCommits is how many commits must change a large file before it is
+// a refactor candidate
+const hotspotCommits = 3
+
+// FileChurn is how often and how much a file changed
+type FileChurn struct {
+	Path string
+
+	// Commits is the number of commits that changed the file
+	Commits int
+
+	Added   int
+	Deleted int
+
+	// Lines is the file's current length, 0 for binary and large files
+	Lines int
+}
+
+// Changed returns the total number of changed lines
+func (f FileChurn) Changed() int {
+	return f.Added + f.Deleted
+}
+
+// Hotspot reports whether the file changes often and has at least
+// minLines lines, which makes it a refactor candidate
+func (f FileChurn) Hotspot(minLines int) bool {
+	return f.Commits >= hotspotCommits && f.Lines >= minLines
+}
+
+// Churn walks the history of HEAD back to the given time and counts the
+// changes of each file that still exists, most often changed first (most
+// changed lines breaking ties). Merge commits are skipped and files
+// matching any of the exclude patterns are left out, like FilterDiff. It
+// also returns the number of commits walked, at most maxChurnCommits.
+func (r *Repository) Churn(since time.Time, exclude []string) ([]FileChurn, int, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	current, err := commitFiles(headCommit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d := r.newDiffer()
+	churn := make(map[string]*FileChurn)
+	walked, err := r.walkCommitStats(d, since, maxChurnCommits, func(_ *object.Commit, stats []FileStat) {
+		for _, stat := range stats {
+			if _, ok := current[stat.Path]; !ok || matchesAny(stat.Path, exclude) {
+				continue
+			}
+			f, ok := churn[stat.Path]
+			if !ok {
+				f = &FileChurn{Path: stat.Path}
+				churn[stat.Path] = f
+			}
+			f.Commits++
+			f.Added += stat.Added
+			f.Deleted += stat.Deleted
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	files := make([]FileChurn, 0, len(churn))
+	for path, f := range churn {
+		b, err := d.read(current[path])
+		if err != nil {
+			return nil, 0, err
+		}
+		if !b.binary && b.content != "" {
+			f.Lines = strings.Count(b.content, "\n")
+			if !strings.HasSuffix(b.content, "\n") {
+				f.Lines++
+			}
+		}
+		files = append(files, *f)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Commits != files[j].Commits {
+			return files[i].Commits > files[j].Commits
+		}
+		if files[i].Changed() != files[j].Changed() {
+			return files[i].Changed() > files[j].Changed()
+		}
+		return files[i].Path < files[j].Path
+	})
+	return files, walked, nil
+}
+
+// walkCommitStats walks the history of HEAD back to the given time, newest
+// first, and calls fn with each commit and the changed lines of its files.
+// Merge commits are skipped. With a positive limit, at most that many
+// commits are walked. It returns the number of commits walked.
+func (r *Repository) walkCommitStats(d *differ, since time.Time, limit int, fn func(*object.Commit, []FileStat)) (int, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	iter, err := r.repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get log: %w", err)
+	}
+	defer iter.Close()
+
+	walked := 0
+	for limit <= 0 || walked < limit {
+		c, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to walk history: %w", err)
+		}
+		if c.Committer.When.Before(since) {
+			break
+		}
+		if c.NumParents() > 1 {
+			continue
+		}
+		walked++
+
+		to, err := commitFiles(c)
+		if err != nil {
+			return 0, err
+		}
+		from := map[string]*diffFile{}
+		if c.NumParents() == 1 {
+			parent, err := c.Parent(0)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get parent of %s: %w", c.Hash.String()[:7], err)
+			}
+			if from, err = commitFiles(parent); err != nil {
+				return 0, err
+			}
+		}
+
+		diff, err := d.diff(from, to)
+		if err != nil {
+			return 0, err
+		}
+		fn(c, DiffStat(diff))
+	}
+	return walked, nil
+}
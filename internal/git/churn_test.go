@@ -0,0 +1,60 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestChurn(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+
+	commitFile(t, repo, "old.go", "old\n", now.Add(-48*time.Hour))
+	commitFile(t, repo, "a.go", "1\n", now.Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", now.Add(2*time.Minute))
+	commitFile(t, repo, "a.go", "1\n2\n", now.Add(3*time.Minute))
+	commitFile(t, repo, "deps.lock", "dep\n", now.Add(4*time.Minute))
+	commitFile(t, repo, "a.go", "1\n2\n3\n", now.Add(5*time.Minute))
+	commitFile(t, repo, "gone.txt", "gone\n", now.Add(6*time.Minute))
+
+	// Deleted files are left out
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if _, err := worktree.Remove("gone.txt"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: now.Add(7 * time.Minute)}
+	if _, err := worktree.Commit("Remove gone.txt", &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	files, walked, err := repo.Churn(now.Add(-time.Hour), []string{"*.lock"})
+	if err != nil {
+		t.Fatalf("Churn() error = %v", err)
+	}
+	if walked != 7 {
+		t.Errorf("Churn() walked %d commits, want 7", walked)
+	}
+
+	want := []FileChurn{
+		{Path: "a.go", Commits: 3, Added: 3, Lines: 3},
+		{Path: "b.txt", Commits: 1, Added: 1, Lines: 1},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("Churn() = %+v, want %+v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("Churn()[%d] = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+
+	if !files[0].Hotspot(3) || files[0].Hotspot(4) || files[1].Hotspot(1) {
+		t.Error("Hotspot() should need both enough commits and enough lines")
+	}
+}
@@ -0,0 +1,181 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Conflict markers, as written by git's merge, rebase, cherry-pick and
+// stash apply
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSplit  = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// ConflictHunk is a conflicted region of a file, from its "<<<<<<<" line to
+// its ">>>>>>>" line
+type ConflictHunk struct {
+	// Start and End are the 0-based numbers of the marker lines
+	Start int
+	End   int
+
+	// OursLabel and TheirsLabel are the names after the markers, e.g.
+	// "HEAD" and the merged branch
+	OursLabel   string
+	TheirsLabel string
+
+	// Ours, Base and Theirs are the lines of each side without a trailing
+	// newline. Base is only set with the diff3 and zdiff3 conflict styles.
+	Ours   string
+	Base   string
+	Theirs string
+}
+
+// ConflictedFiles returns the paths with unmerged entries in the index, as
+// left by a merge, rebase, cherry-pick or stash apply that stopped on
+// conflicts
+func (r *Repository) ConflictedFiles() ([]string, error) {
+	if r.system {
+		output, err := r.runGit("", "diff", "--name-only", "--diff-filter=U", "-z")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+		}
+		if output == "" {
+			return nil, nil
+		}
+		return strings.Split(strings.TrimSuffix(output, "\x00"), "\x00"), nil
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	seen := make(map[string]bool)
+	var paths []string
+	for _, e := range idx.Entries {
+		// Stage 0 holds merged entries, 1 to 3 the sides of a conflict
+		if e.Stage != 0 && !seen[e.Name] {
+			seen[e.Name] = true
+			paths = append(paths, e.Name)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// MarkResolved stages the worktree content of conflicted files, replacing
+// their unmerged index entries, like git add
+func (r *Repository) MarkResolved(paths []string) error {
+	if r.system || len(paths) == 0 {
+		return r.StageFiles(paths)
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to read index: %w", err)
+	}
+	resolved := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		resolved[path] = true
+	}
+	entries := idx.Entries[:0]
+	for _, e := range idx.Entries {
+		if !resolved[e.Name] {
+			entries = append(entries, e)
+		}
+	}
+	idx.Entries = entries
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return r.StageFiles(paths)
+}
+
+// ParseConflicts returns the conflicted regions of a file's content in
+// order. It fails on markers that don't form complete regions.
+func ParseConflicts(content string) ([]ConflictHunk, error) {
+	var hunks []ConflictHunk
+	var hunk *ConflictHunk
+	// side collects the lines of the current side: 0 ours, 1 base, 2 theirs
+	side := 0
+	var sides [3][]string
+
+	for i, line := range strings.Split(content, "\n") {
+		// Markers end in CRLF in files with Windows line endings
+		marker := strings.TrimSuffix(line, "\r")
+		switch {
+		case isMarker(marker, markerOurs):
+			if hunk != nil {
+				return nil, fmt.Errorf("line %d: nested conflict marker", i+1)
+			}
+			hunk = &ConflictHunk{Start: i, OursLabel: markerLabel(marker)}
+			side, sides = 0, [3][]string{}
+		case hunk == nil:
+			// Outside conflicts, "=======" is e.g. a Markdown underline
+		case isMarker(marker, markerBase) && side == 0:
+			side = 1
+		case marker == markerSplit && side < 2:
+			side = 2
+		case isMarker(marker, markerTheirs) && side == 2:
+			hunk.End = i
+			hunk.TheirsLabel = markerLabel(marker)
+			hunk.Ours = strings.Join(sides[0], "\n")
+			hunk.Base = strings.Join(sides[1], "\n")
+			hunk.Theirs = strings.Join(sides[2], "\n")
+			hunks = append(hunks, *hunk)
+			hunk = nil
+		default:
+			sides[side] = append(sides[side], line)
+		}
+	}
+
+	if hunk != nil {
+		return nil, fmt.Errorf("line %d: conflict is not closed with %s", hunk.Start+1, markerTheirs)
+	}
+	return hunks, nil
+}
+
+// ConflictContext returns up to n lines before and after a conflicted
+// region of content
+func ConflictContext(content string, hunk ConflictHunk, n int) (before, after string) {
+	lines := strings.Split(content, "\n")
+	from := max(hunk.Start-n, 0)
+	to := min(hunk.End+1+n, len(lines))
+	return strings.Join(lines[from:hunk.Start], "\n"), strings.Join(lines[hunk.End+1:to], "\n")
+}
+
+// ResolveConflicts replaces the conflicted regions of content with their
+// resolutions, keyed by the region's index in hunks. Regions without a
+// resolution keep their markers. An empty resolution removes the region.
+func ResolveConflicts(content string, hunks []ConflictHunk, resolutions map[int]string) string {
+	lines := strings.Split(content, "\n")
+	var out []string
+	next := 0
+	for i, hunk := range hunks {
+		resolution, ok := resolutions[i]
+		if !ok {
+			continue
+		}
+		out = append(out, lines[next:hunk.Start]...)
+		if resolution != "" {
+			out = append(out, strings.Split(strings.TrimSuffix(resolution, "\n"), "\n")...)
+		}
+		next = hunk.End + 1
+	}
+	out = append(out, lines[next:]...)
+	return strings.Join(out, "\n")
+}
+
+// isMarker reports whether line is the given conflict marker, optionally
+// followed by a label
+func isMarker(line, marker string) bool {
+	return line == marker || strings.HasPrefix(line, marker+" ")
+}
+
+// markerLabel returns the label after a conflict marker
+func markerLabel(line string) string {
+	return strings.TrimSpace(line[len(markerOurs):])
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+const conflicted = `Title
+=====
+<<<<<<< HEAD
+ours 1
+ours 2
+=======
+theirs
+>>>>>>> feature
+middle
+<<<<<<< HEAD
+||||||| base
+base
+=======
+theirs only
+>>>>>>> feature
+end`
+
+func TestParseConflicts(t *testing.T) {
+	hunks, err := ParseConflicts(conflicted)
+	if err != nil {
+		t.Fatalf("ParseConflicts() error = %v", err)
+	}
+	want := []ConflictHunk{
+		{Start: 2, End: 7, OursLabel: "HEAD", TheirsLabel: "feature", Ours: "ours 1\nours 2", Theirs: "theirs"},
+		{Start: 9, End: 14, OursLabel: "HEAD", TheirsLabel: "feature", Base: "base", Theirs: "theirs only"},
+	}
+	if len(hunks) != len(want) {
+		t.Fatalf("ParseConflicts() = %+v, want %+v", hunks, want)
+	}
+	for i := range want {
+		if hunks[i] != want[i] {
+			t.Errorf("ParseConflicts()[%d] = %+v, want %+v", i, hunks[i], want[i])
+		}
+	}
+
+	crlf, err := ParseConflicts(strings.ReplaceAll(conflicted, "\n", "\r\n"))
+	if err != nil || len(crlf) != 2 {
+		t.Errorf("ParseConflicts() with CRLF = %d hunks, %v, want 2", len(crlf), err)
+	}
+
+	for _, broken := range []string{
+		"<<<<<<< HEAD\nours\n=======\ntheirs\n",
+		"<<<<<<< HEAD\n<<<<<<< HEAD\n",
+	} {
+		if _, err := ParseConflicts(broken); err == nil {
+			t.Errorf("ParseConflicts(%q) succeeded, want an error", broken)
+		}
+	}
+
+	if hunks, err := ParseConflicts("no conflicts\n=======\n"); err != nil || len(hunks) != 0 {
+		t.Errorf("ParseConflicts() without conflicts = %+v, %v, want none", hunks, err)
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	hunks, err := ParseConflicts(conflicted)
+	if err != nil {
+		t.Fatalf("ParseConflicts() error = %v", err)
+	}
+
+	got := ResolveConflicts(conflicted, hunks, map[int]string{0: "merged 1\nmerged 2\n", 1: ""})
+	if want := "Title\n=====\nmerged 1\nmerged 2\nmiddle\nend"; got != want {
+		t.Errorf("ResolveConflicts() = %q, want %q", got, want)
+	}
+
+	// Unresolved regions keep their markers
+	got = ResolveConflicts(conflicted, hunks, map[int]string{1: "resolved"})
+	if left, _ := ParseConflicts(got); len(left) != 1 || !strings.HasSuffix(got, "middle\nresolved\nend") {
+		t.Errorf("ResolveConflicts() with one resolution = %q", got)
+	}
+
+	before, after := ConflictContext(conflicted, hunks[0], 1)
+	if before != "=====" || after != "middle" {
+		t.Errorf("ConflictContext() = %q, %q, want one line around the conflict", before, after)
+	}
+}
+
+func TestConflictedFiles(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a\n", time.Now().Add(time.Minute))
+
+	// Record a conflict on a.txt in the index, as a merge would
+	idx, err := repo.repo.Storer.Index()
+	if err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	entry, err := idx.Entry("a.txt")
+	if err != nil {
+		t.Fatalf("Entry() error = %v", err)
+	}
+	for _, stage := range []index.Stage{index.AncestorMode, index.OurMode, index.TheirMode} {
+		conflict := *entry
+		conflict.Stage = stage
+		idx.Entries = append(idx.Entries, &conflict)
+	}
+	if _, err := idx.Remove("a.txt"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := repo.repo.Storer.SetIndex(idx); err != nil {
+		t.Fatalf("SetIndex() error = %v", err)
+	}
+
+	backends := []bool{false}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, true)
+	}
+	for _, system := range backends {
+		repo.system = system
+		files, err := repo.ConflictedFiles()
+		if err != nil || len(files) != 1 || files[0] != "a.txt" {
+			t.Errorf("ConflictedFiles() = %v, %v, want a.txt (system %v)", files, err, system)
+		}
+	}
+
+	repo.system = false
+	if err := os.WriteFile(filepath.Join(repo.Root(), "a.txt"), []byte("resolved\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := repo.MarkResolved([]string{"a.txt"}); err != nil {
+		t.Fatalf("MarkResolved() error = %v", err)
+	}
+	if files, err := repo.ConflictedFiles(); err != nil || len(files) != 0 {
+		t.Errorf("ConflictedFiles() after MarkResolved() = %v, %v, want none", files, err)
+	}
+	if staged, err := repo.GetStagedDiff(); err != nil || !strings.Contains(staged, "+resolved") {
+		t.Errorf("GetStagedDiff() after MarkResolved() = %q, %v, want the resolution staged", staged, err)
+	}
+}
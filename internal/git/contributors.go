@@ -0,0 +1,84 @@
+package git
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Contributor sums up the commits of one author
+type Contributor struct {
+	Name  string
+	Email string
+
+	Commits int
+	Added   int
+	Deleted int
+
+	// ActiveDays is the number of days with at least one commit, in the
+	// author's time zone
+	ActiveDays int
+
+	First time.Time
+	Last  time.Time
+}
+
+// Contributors walks the history of HEAD back to the given time and sums
+// up the commits of each author, most commits first (most changed lines
+// breaking ties). Authors are told apart by email, ignoring case, and named
+// as in their latest commit. Merge commits are skipped, and lines of files
+// matching any of the exclude patterns are not counted, like FilterDiff.
+func (r *Repository) Contributors(since time.Time, exclude []string) ([]Contributor, error) {
+	index := make(map[string]int)
+	days := make(map[string]map[string]bool)
+	var contributors []Contributor
+
+	_, err := r.walkCommitStats(r.newDiffer(), since, 0, func(c *object.Commit, stats []FileStat) {
+		key := strings.ToLower(c.Author.Email)
+		i, ok := index[key]
+		if !ok {
+			// Commits come newest first
+			i = len(contributors)
+			index[key] = i
+			days[key] = make(map[string]bool)
+			contributors = append(contributors, Contributor{
+				Name:  c.Author.Name,
+				Email: c.Author.Email,
+				Last:  c.Author.When,
+			})
+		}
+
+		contributor := &contributors[i]
+		contributor.Commits++
+		for _, stat := range stats {
+			if !matchesAny(stat.Path, exclude) {
+				contributor.Added += stat.Added
+				contributor.Deleted += stat.Deleted
+			}
+		}
+		if contributor.First.IsZero() || c.Author.When.Before(contributor.First) {
+			contributor.First = c.Author.When
+		}
+		if c.Author.When.After(contributor.Last) {
+			contributor.Last = c.Author.When
+		}
+		days[key][c.Author.When.Format("2006-01-02")] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for key, i := range index {
+		contributors[i].ActiveDays = len(days[key])
+	}
+	sort.SliceStable(contributors, func(i, j int) bool {
+		a, b := contributors[i], contributors[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Added+a.Deleted > b.Added+b.Deleted
+	})
+	return contributors, nil
+}
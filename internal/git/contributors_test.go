@@ -0,0 +1,40 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContributors(t *testing.T) {
+	repo := newTestRepo(t)
+	day := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	commitFile(t, repo, "a.go", "1\n", day)
+	commitFile(t, repo, "a.go", "1\n2\n", day.Add(time.Hour))
+	commitAs(t, repo, "b.go", "Ann", "ann@example.com", day.Add(2*time.Hour))
+	commitFile(t, repo, "go.sum", "x\ny\n", day.Add(24*time.Hour))
+	commitAs(t, repo, "c.go", "Ann Lee", "ANN@example.com", day.Add(25*time.Hour))
+
+	contributors, err := repo.Contributors(day.Add(-time.Minute), []string{"go.sum"})
+	if err != nil {
+		t.Fatalf("Contributors() error = %v", err)
+	}
+	if len(contributors) != 2 {
+		t.Fatalf("Contributors() = %+v, want 2 contributors", contributors)
+	}
+
+	// The test repository's initial commit is made now
+	test := contributors[0]
+	if test.Name != "Test" || test.Commits != 4 || test.Added != 3 || test.Deleted != 0 || test.ActiveDays != 3 {
+		t.Errorf("Contributors()[0] = %+v, want Test with 4 commits, +3 lines on 3 days", test)
+	}
+	if !test.First.Equal(day) {
+		t.Errorf("Contributors()[0] first active %v, want %v", test.First, day)
+	}
+
+	// Emails are compared ignoring case, the latest name wins
+	ann := contributors[1]
+	if ann.Name != "Ann Lee" || ann.Commits != 2 || ann.Added != 2 || ann.ActiveDays != 2 {
+		t.Errorf("Contributors()[1] = %+v, want Ann Lee with 2 commits, +2 lines on 2 days", ann)
+	}
+}
@@ -0,0 +1,210 @@
+package git
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// lockfiles are generated from dependency manifests, their diffs are noise
+// once the manifest changes are summarized
+var lockfiles = map[string]bool{
+	"go.sum":              true,
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"Cargo.lock":          true,
+	"composer.lock":       true,
+	"Gemfile.lock":        true,
+}
+
+// DependencyChange is a dependency added, removed or bumped in a manifest.
+// From is empty for added dependencies, To for removed ones.
+type DependencyChange struct {
+	Name string
+	From string
+	To   string
+}
+
+// String describes the change, e.g. "bumped cobra v1.8.0→v1.9.1"
+func (c DependencyChange) String() string {
+	switch {
+	case c.From == "":
+		return strings.TrimSpace("added " + c.Name + " " + c.To)
+	case c.To == "":
+		return "removed " + c.Name
+	default:
+		return "bumped " + c.Name + " " + c.From + "→" + c.To
+	}
+}
+
+// DependencySummary is what a diff changes in dependency manifests and
+// lockfiles
+type DependencySummary struct {
+	Changes []DependencyChange
+
+	// Lockfiles are the paths of the changed lockfiles
+	Lockfiles []string
+}
+
+// Empty reports whether the diff changes no dependencies
+func (s *DependencySummary) Empty() bool {
+	return len(s.Changes) == 0 && len(s.Lockfiles) == 0
+}
+
+// String returns a one-line summary for prompts, e.g. "deps: bumped cobra
+// v1.8.0→v1.9.1, added lodash ^4.17.21 (lockfiles: go.sum)"
+func (s *DependencySummary) String() string {
+	if s.Empty() {
+		return ""
+	}
+	changes := make([]string, 0, len(s.Changes))
+	for _, c := range s.Changes {
+		changes = append(changes, c.String())
+	}
+	line := "deps: " + strings.Join(changes, ", ")
+	if len(s.Changes) == 0 {
+		line = "deps: lockfiles updated"
+	}
+	if len(s.Lockfiles) > 0 {
+		line += " (lockfiles: " + strings.Join(s.Lockfiles, ", ") + ")"
+	}
+	return line
+}
+
+// SummarizeDependencies parses the changes of go.mod, package.json and
+// requirements files in a unified diff, and removes the sections of
+// lockfiles such as go.sum and package-lock.json. It returns the diff
+// without the lockfiles and the summary of the dependency changes.
+func SummarizeDependencies(diff string) (string, *DependencySummary) {
+	summary := &DependencySummary{}
+	var result strings.Builder
+
+	for _, section := range splitDiffSections(diff) {
+		filePath := sectionPath(section)
+		name := path.Base(filePath)
+		if filePath != "" && lockfiles[name] {
+			summary.Lockfiles = append(summary.Lockfiles, filePath)
+			continue
+		}
+		result.WriteString(section)
+
+		var parse func(string) (string, string, bool)
+		switch {
+		case name == "go.mod":
+			parse = parseGoModLine
+		case name == "package.json":
+			parse = parsePackageJSONLine
+		case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"):
+			parse = parseRequirementLine
+		default:
+			continue
+		}
+		summary.Changes = append(summary.Changes, manifestChanges(section, parse)...)
+	}
+
+	return result.String(), summary
+}
+
+// manifestChanges compares the removed and added lines of a manifest's
+// diff section, parsed into dependency names and versions by parse
+func manifestChanges(section string, parse func(string) (string, string, bool)) []DependencyChange {
+	var order []string
+	removed := make(map[string]string)
+	added := make(map[string]string)
+
+	for _, line := range strings.Split(section, "\n") {
+		if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		var side map[string]string
+		switch {
+		case strings.HasPrefix(line, "-"):
+			side = removed
+		case strings.HasPrefix(line, "+"):
+			side = added
+		default:
+			continue
+		}
+		name, version, ok := parse(line[1:])
+		if !ok {
+			continue
+		}
+		if _, seen := removed[name]; !seen {
+			if _, seen := added[name]; !seen {
+				order = append(order, name)
+			}
+		}
+		side[name] = version
+	}
+
+	var changes []DependencyChange
+	for _, name := range order {
+		from, wasThere := removed[name]
+		to, isThere := added[name]
+		switch {
+		case wasThere && isThere && from == to:
+			// Moved or reformatted
+		case wasThere && isThere:
+			changes = append(changes, DependencyChange{Name: name, From: from, To: to})
+		case isThere:
+			changes = append(changes, DependencyChange{Name: name, To: to})
+		default:
+			changes = append(changes, DependencyChange{Name: name, From: from})
+		}
+	}
+	return changes
+}
+
+// parseGoModLine parses a require line of go.mod, in a block or not, and
+// the go and toolchain directives
+func parseGoModLine(line string) (string, string, bool) {
+	line, _, _ = strings.Cut(line, "//")
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "require "))
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	if fields[0] == "go" || fields[0] == "toolchain" || strings.HasPrefix(fields[1], "v") {
+		return fields[0], fields[1], true
+	}
+	return "", "", false
+}
+
+// packageJSONDependency matches a "name": "version" line of package.json
+var packageJSONDependency = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*"((?:[\^~<>=*]*v?\d|\*|latest|workspace:|npm:)[^"]*)"\s*,?\s*$`)
+
+// parsePackageJSONLine parses a dependency line of package.json. The
+// package's own version is not a dependency.
+func parsePackageJSONLine(line string) (string, string, bool) {
+	m := packageJSONDependency.FindStringSubmatch(line)
+	if m == nil || m[1] == "version" {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// parseRequirementLine parses a requirement of a pip requirements file,
+// e.g. "requests==2.31.0" or "django>=4.2"
+func parseRequirementLine(line string) (string, string, bool) {
+	line, _, _ = strings.Cut(line, " #")
+	line, _, _ = strings.Cut(line, ";")
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+		return "", "", false
+	}
+
+	end := strings.IndexAny(line, "=<>!~[@ ")
+	if end < 0 {
+		end = len(line)
+	}
+	name := strings.ToLower(strings.ReplaceAll(line[:end], "_", "-"))
+	version := strings.TrimSpace(line[end:])
+	if _, extras, ok := strings.Cut(version, "]"); ok && strings.HasPrefix(version, "[") {
+		version = strings.TrimSpace(extras)
+	}
+	return name, strings.TrimPrefix(version, "=="), true
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeDependencies(t *testing.T) {
+	tests := []struct {
+		name          string
+		diff          string
+		wantChanges   []DependencyChange
+		wantLockfiles []string
+		wantSummary   string
+	}{
+		{
+			name: "go.mod",
+			diff: `diff --git a/go.mod b/go.mod
+--- a/go.mod
++++ b/go.mod
+@@ -1,8 +1,9 @@
+ module example.com/app
+
+-go 1.21
++go 1.22
+
+ require (
+-	github.com/spf13/cobra v1.8.0
++	github.com/spf13/cobra v1.9.1
++	github.com/charmbracelet/huh v0.6.0 // indirect
+-	github.com/pkg/errors v0.9.1
+ 	golang.org/x/sys v0.20.0
+ )
+diff --git a/go.sum b/go.sum
+--- a/go.sum
++++ b/go.sum
++github.com/spf13/cobra v1.9.1 h1:abc
+`,
+			wantChanges: []DependencyChange{
+				{Name: "go", From: "1.21", To: "1.22"},
+				{Name: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.9.1"},
+				{Name: "github.com/charmbracelet/huh", To: "v0.6.0"},
+				{Name: "github.com/pkg/errors", From: "v0.9.1"},
+			},
+			wantLockfiles: []string{"go.sum"},
+			wantSummary:   "deps: bumped go 1.21→1.22, bumped github.com/spf13/cobra v1.8.0→v1.9.1, added github.com/charmbracelet/huh v0.6.0, removed github.com/pkg/errors (lockfiles: go.sum)",
+		},
+		{
+			name: "package.json",
+			diff: `diff --git a/web/package.json b/web/package.json
+--- a/web/package.json
++++ b/web/package.json
+@@ -1,9 +1,10 @@
+ {
+   "name": "web",
+-  "version": "1.0.0",
++  "version": "1.1.0",
+   "scripts": {
+-    "test": "jest"
++    "test": "vitest"
+   },
+   "dependencies": {
+-    "react": "^18.2.0",
++    "react": "^18.3.1",
++    "lodash": "^4.17.21"
+   }
+ }
+diff --git a/web/package-lock.json b/web/package-lock.json
+--- a/web/package-lock.json
++++ b/web/package-lock.json
++    "lodash": "4.17.21"
+`,
+			wantChanges: []DependencyChange{
+				{Name: "react", From: "^18.2.0", To: "^18.3.1"},
+				{Name: "lodash", To: "^4.17.21"},
+			},
+			wantLockfiles: []string{"web/package-lock.json"},
+			wantSummary:   "deps: bumped react ^18.2.0→^18.3.1, added lodash ^4.17.21 (lockfiles: web/package-lock.json)",
+		},
+		{
+			name: "requirements.txt",
+			diff: `diff --git a/requirements.txt b/requirements.txt
+--- a/requirements.txt
++++ b/requirements.txt
+@@ -1,4 +1,4 @@
+ # web
+-requests==2.31.0
++requests==2.32.3
+-Django>=4.2
++celery[redis]==5.4.0 ; python_version >= "3.8"
+`,
+			wantChanges: []DependencyChange{
+				{Name: "requests", From: "2.31.0", To: "2.32.3"},
+				{Name: "django", From: ">=4.2"},
+				{Name: "celery", To: "5.4.0"},
+			},
+			wantSummary: "deps: bumped requests 2.31.0→2.32.3, removed django, added celery 5.4.0",
+		},
+		{
+			name: "Reordered dependency",
+			diff: `diff --git a/go.mod b/go.mod
+--- a/go.mod
++++ b/go.mod
+-	github.com/spf13/cobra v1.8.0
++	github.com/spf13/cobra v1.8.0 // indirect
+`,
+		},
+		{
+			name: "Lockfile only",
+			diff: `diff --git a/yarn.lock b/yarn.lock
+--- a/yarn.lock
++++ b/yarn.lock
++lodash@^4.17.21:
+`,
+			wantLockfiles: []string{"yarn.lock"},
+			wantSummary:   "deps: lockfiles updated (lockfiles: yarn.lock)",
+		},
+		{
+			name: "No dependencies",
+			diff: sampleDiff[:strings.Index(sampleDiff, "diff --git a/go.sum")],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, summary := SummarizeDependencies(tt.diff)
+
+			if !reflect.DeepEqual(summary.Changes, tt.wantChanges) {
+				t.Errorf("SummarizeDependencies() changes = %v, want %v", summary.Changes, tt.wantChanges)
+			}
+			if !reflect.DeepEqual(summary.Lockfiles, tt.wantLockfiles) {
+				t.Errorf("SummarizeDependencies() lockfiles = %v, want %v", summary.Lockfiles, tt.wantLockfiles)
+			}
+			if s := summary.String(); s != tt.wantSummary {
+				t.Errorf("String() = %q, want %q", s, tt.wantSummary)
+			}
+			for _, path := range tt.wantLockfiles {
+				if strings.Contains(got, "diff --git a/"+path) {
+					t.Errorf("SummarizeDependencies() kept lockfile %s", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberLines prefixes the added and unchanged lines of a unified diff with
+// their line number in the new file, and pads removed lines to match, so a
+// model can point at exact lines
+func NumberLines(diff string) string {
+	var b strings.Builder
+	walkDiff(diff, func(_ string, line int, text string) {
+		switch {
+		case line > 0:
+			fmt.Fprintf(&b, "%6d %s\n", line, text)
+		case strings.HasPrefix(text, "-") && !strings.HasPrefix(text, "---"):
+			fmt.Fprintf(&b, "%6s %s\n", "", text)
+		default:
+			b.WriteString(text + "\n")
+		}
+	})
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// DiffLines returns the new-file line numbers each file's hunks show, added
+// or unchanged, which are the lines review comments can be attached to
+func DiffLines(diff string) map[string]map[int]bool {
+	lines := make(map[string]map[int]bool)
+	walkDiff(diff, func(path string, line int, _ string) {
+		if line == 0 {
+			return
+		}
+		if lines[path] == nil {
+			lines[path] = make(map[int]bool)
+		}
+		lines[path][line] = true
+	})
+	return lines
+}
+
+// walkDiff calls fn for every line of a unified diff, with the file's path
+// and, for added and unchanged lines inside hunks, the line number in the
+// new file (0 for all other lines)
+func walkDiff(diff string, fn func(path string, line int, text string)) {
+	for _, section := range splitDiffSections(diff) {
+		filePath := sectionPath(section)
+
+		line := 0
+		inHunks := false
+		for _, text := range strings.Split(strings.TrimSuffix(section, "\n"), "\n") {
+			if m := hunkHeader.FindStringSubmatch(text); m != nil {
+				line, _ = strconv.Atoi(m[1])
+				inHunks = true
+				fn(filePath, 0, text)
+				continue
+			}
+
+			if inHunks && (strings.HasPrefix(text, "+") || strings.HasPrefix(text, " ")) {
+				fn(filePath, line, text)
+				line++
+				continue
+			}
+			fn(filePath, 0, text)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"maps"
+	"testing"
+)
+
+const numberedDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3,3 +3,4 @@ import "fmt"
+ func main() {
+-	fmt.Println("hi")
++	fmt.Println("hello")
++	fmt.Println("world")
+ }
+`
+
+func TestNumberLines(t *testing.T) {
+	want := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3,3 +3,4 @@ import "fmt"
+     3  func main() {
+       -	fmt.Println("hi")
+     4 +	fmt.Println("hello")
+     5 +	fmt.Println("world")
+     6  }`
+
+	if got := NumberLines(numberedDiff); got != want {
+		t.Errorf("NumberLines() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines(numberedDiff)
+	want := map[int]bool{3: true, 4: true, 5: true, 6: true}
+	if len(got) != 1 || !maps.Equal(got["main.go"], want) {
+		t.Errorf("DiffLines() = %v, want main.go lines %v", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Divergence describes how the current branch relates to its base branch
+type Divergence struct {
+	// BaseRef is the reference the branch was compared against, e.g.
+	// "origin/main"
+	BaseRef string
+
+	// Behind is the number of base commits missing from the branch
+	Behind int
+
+	// Conflicts lists files changed both on the branch and on the base
+	// since they diverged, which may conflict when merging
+	Conflicts []string
+}
+
+// Diverged reports whether the base has commits the branch doesn't have
+func (d *Divergence) Diverged() bool {
+	return d.Behind > 0
+}
+
+// CheckDivergence compares HEAD with base and reports the base commits
+// missing from HEAD and the files changed on both sides
+func (r *Repository) CheckDivergence(base string) (*Divergence, error) {
+	headCommit, baseCommit, baseRef, err := r.headAndBase(base)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBase, err := mergeBase(headCommit, baseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	div := &Divergence{BaseRef: baseRef.Name().Short()}
+
+	missing, err := commitsBetween(baseCommit, mergeBase)
+	if err != nil {
+		return nil, err
+	}
+	div.Behind = len(missing)
+	if div.Behind == 0 {
+		return div, nil
+	}
+
+	headFiles, err := changedFiles(mergeBase, headCommit)
+	if err != nil {
+		return nil, err
+	}
+	baseFiles, err := changedFiles(mergeBase, baseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	for file := range headFiles {
+		if baseFiles[file] {
+			div.Conflicts = append(div.Conflicts, file)
+		}
+	}
+	sort.Strings(div.Conflicts)
+
+	return div, nil
+}
+
+// headAndBase returns the commits of HEAD and of the base branch, and the
+// reference the base was resolved to
+func (r *Repository) headAndBase(base string) (*object.Commit, *object.Commit, *plumbing.Reference, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	baseRef, err := r.baseRef(base)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	baseCommit, err := r.repo.CommitObject(baseRef.Hash())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get base commit: %w", err)
+	}
+
+	return headCommit, baseCommit, baseRef, nil
+}
+
+// mergeBase returns the best common ancestor of two commits
+func mergeBase(a, b *object.Commit) (*object.Commit, error) {
+	bases, err := a.MergeBase(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base: %w", err)
+	}
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("the branch and its base have no common history")
+	}
+	return bases[0], nil
+}
+
+// commitsBetween returns the commits reachable from tip but not from stop,
+// which must be an ancestor of tip, newest first
+func commitsBetween(tip, stop *object.Commit) ([]*object.Commit, error) {
+	var commits []*object.Commit
+	seen := map[plumbing.Hash]bool{stop.Hash: true}
+	queue := []*object.Commit{tip}
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if seen[c.Hash] {
+			continue
+		}
+		seen[c.Hash] = true
+
+		// Commits older than stop may still be reachable from it through
+		// merged branches, newer ones can't be
+		if !c.Committer.When.After(stop.Committer.When) {
+			if isAncestor, err := c.IsAncestor(stop); err == nil && isAncestor {
+				continue
+			}
+		}
+		commits = append(commits, c)
+
+		err := c.Parents().ForEach(func(parent *object.Commit) error {
+			queue = append(queue, parent)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk history: %w", err)
+		}
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.After(commits[j].Committer.When)
+	})
+	return commits, nil
+}
+
+// changedFiles returns the paths that differ between two commits
+func changedFiles(from, to *object.Commit) (map[string]bool, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	changes, err := fromTree.Diff(toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate diff: %w", err)
+	}
+
+	files := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if change.From.Name != "" {
+			files[change.From.Name] = true
+		}
+		if change.To.Name != "" {
+			files[change.To.Name] = true
+		}
+	}
+	return files, nil
+}
+
+// Rebase rebases the current branch onto ref (e.g. "origin/main") with the
+// git binary, as go-git can't rebase. On conflicts the rebase is aborted
+// and the branch is left unchanged.
+func (r *Repository) Rebase(ref string) error {
+	return r.integrate([]string{"rebase", ref}, []string{"rebase", "--abort"})
+}
+
+// Merge merges ref into the current branch with the git binary. On
+// conflicts the merge is aborted and the branch is left unchanged.
+func (r *Repository) Merge(ref string) error {
+	return r.integrate([]string{"merge", "--no-edit", ref}, []string{"merge", "--abort"})
+}
+
+// integrate runs a git rebase or merge, undoing it with abort if it fails
+func (r *Repository) integrate(args, abort []string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git %s needs the git binary, which was not found in PATH", args[0])
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Root()
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	undo := exec.Command("git", abort...)
+	undo.Dir = r.Root()
+	_ = undo.Run()
+
+	return fmt.Errorf("git %s failed and was aborted:\n%s", args[0], strings.TrimSpace(string(output)))
+}
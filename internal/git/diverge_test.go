@@ -0,0 +1,98 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestCheckDivergence(t *testing.T) {
+	repo := newTestRepo(t)
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+
+	// feature changes README.md and feature.go
+	err = worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true})
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	commitFile(t, repo, "README.md", "feature\n", time.Now().Add(time.Minute))
+	commitFile(t, repo, "feature.go", "package main\n", time.Now().Add(2*time.Minute))
+
+	div, err := repo.CheckDivergence("master")
+	if err != nil {
+		t.Fatalf("CheckDivergence() error = %v", err)
+	}
+	if div.Diverged() {
+		t.Errorf("CheckDivergence() = %+v, want not diverged", div)
+	}
+
+	// master moves on with its own README.md change
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	commitFile(t, repo, "README.md", "main\n", time.Now().Add(3*time.Minute))
+	commitFile(t, repo, "other.go", "package main\n", time.Now().Add(4*time.Minute))
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature")}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	div, err = repo.CheckDivergence("master")
+	if err != nil {
+		t.Fatalf("CheckDivergence() error = %v", err)
+	}
+	if div.Behind != 2 {
+		t.Errorf("Behind = %d, want 2", div.Behind)
+	}
+	if len(div.Conflicts) != 1 || div.Conflicts[0] != "README.md" {
+		t.Errorf("Conflicts = %v, want [README.md]", div.Conflicts)
+	}
+
+	// Commits and diff only cover the branch since the merge base
+	commits, err := repo.GetCommitsAhead("master")
+	if err != nil {
+		t.Fatalf("GetCommitsAhead() error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Message != "Update feature.go" {
+		t.Errorf("GetCommitsAhead() = %+v, want the 2 feature commits, newest first", commits)
+	}
+
+	diff, err := repo.GetDiffFromBase("master")
+	if err != nil {
+		t.Fatalf("GetDiffFromBase() error = %v", err)
+	}
+	if !strings.Contains(diff, "feature.go") || strings.Contains(diff, "other.go") {
+		t.Errorf("GetDiffFromBase() should contain only the branch's changes:\n%s", diff)
+	}
+}
+
+// commitFile writes a file and commits it at the given time
+func commitFile(t *testing.T, repo *Repository, name, content string, when time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(repo.Root(), name), []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if _, err := worktree.Add(name); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: when}
+	if _, err := worktree.Commit("Update "+name, &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+}
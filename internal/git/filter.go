@@ -0,0 +1,130 @@
+package git
+
+import (
+	"path"
+	"strings"
+)
+
+// FilterDiff removes the sections of a unified diff whose file path matches
+// any of the exclude glob patterns. It returns the filtered diff and the
+// paths that were excluded.
+//
+// A pattern matches if it matches the full path ("vendor/*") or the file's
+// base name ("*.lock"). A pattern ending in "/" or "/**" matches everything
+// under that directory.
+func FilterDiff(diff string, exclude []string) (string, []string) {
+	if len(exclude) == 0 || diff == "" {
+		return diff, nil
+	}
+
+	var (
+		result   strings.Builder
+		excluded []string
+	)
+
+	for _, section := range splitDiffSections(diff) {
+		filePath := sectionPath(section)
+		if filePath != "" && matchesAny(filePath, exclude) {
+			excluded = append(excluded, filePath)
+			continue
+		}
+		result.WriteString(section)
+	}
+
+	return result.String(), excluded
+}
+
+// splitDiffSections splits a diff into per-file sections, each starting with
+// its "diff --git" header. Any text before the first header is kept as its
+// own section.
+func splitDiffSections(diff string) []string {
+	var sections []string
+	var current strings.Builder
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") && current.Len() > 0 {
+			sections = append(sections, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		sections = append(sections, current.String())
+	}
+
+	return sections
+}
+
+// sectionPath returns the file path from a diff section's header
+func sectionPath(section string) string {
+	header := strings.SplitN(section, "\n", 2)[0]
+	if !strings.HasPrefix(header, "diff --git ") {
+		return ""
+	}
+
+	// Header format: diff --git a/<path> b/<path>
+	idx := strings.LastIndex(header, " b/")
+	if idx == -1 {
+		return ""
+	}
+	return header[idx+len(" b/"):]
+}
+
+// MatchingFiles returns the files of a unified diff that match any of the
+// glob patterns, in the order of the diff
+func MatchingFiles(diff string, patterns []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+	var files []string
+	for _, section := range splitDiffSections(diff) {
+		filePath := sectionPath(section)
+		if filePath != "" && matchesAny(filePath, patterns) {
+			files = append(files, filePath)
+		}
+	}
+	return files
+}
+
+// FileDiff returns the sections of a unified diff that change filePath,
+// e.g. to show a single file
+func FileDiff(diff, filePath string) string {
+	var result strings.Builder
+	for _, section := range splitDiffSections(diff) {
+		if sectionPath(section) == filePath {
+			result.WriteString(section)
+		}
+	}
+	return result.String()
+}
+
+// matchesAny reports whether filePath matches any of the glob patterns
+func matchesAny(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchPath(filePath, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether filePath matches a single glob pattern
+func matchPath(filePath, pattern string) bool {
+	// Directory patterns: "vendor/" or "vendor/**"
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return strings.HasPrefix(filePath, dir+"/")
+	}
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(filePath, pattern)
+	}
+
+	if ok, _ := path.Match(pattern, filePath); ok {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		if ok, _ := path.Match(pattern, path.Base(filePath)); ok {
+			return true
+		}
+	}
+	return false
+}
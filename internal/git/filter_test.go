@@ -0,0 +1,95 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
++func main() {}
+diff --git a/go.sum b/go.sum
+--- a/go.sum
++++ b/go.sum
++github.com/foo/bar v1.0.0 h1:abc
+diff --git a/vendor/foo/foo.go b/vendor/foo/foo.go
++package foo
+`
+
+func TestFilterDiff(t *testing.T) {
+	tests := []struct {
+		name         string
+		exclude      []string
+		wantExcluded []string
+		wantKept     []string
+	}{
+		{
+			name:     "No patterns",
+			exclude:  nil,
+			wantKept: []string{"main.go", "go.sum", "vendor/foo/foo.go"},
+		},
+		{
+			name:         "Base name pattern",
+			exclude:      []string{"*.sum"},
+			wantExcluded: []string{"go.sum"},
+			wantKept:     []string{"main.go", "vendor/foo/foo.go"},
+		},
+		{
+			name:         "Directory pattern",
+			exclude:      []string{"vendor/"},
+			wantExcluded: []string{"vendor/foo/foo.go"},
+			wantKept:     []string{"main.go", "go.sum"},
+		},
+		{
+			name:         "Recursive directory pattern",
+			exclude:      []string{"vendor/**", "go.sum"},
+			wantExcluded: []string{"go.sum", "vendor/foo/foo.go"},
+			wantKept:     []string{"main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, excluded := FilterDiff(sampleDiff, tt.exclude)
+
+			if !reflect.DeepEqual(excluded, tt.wantExcluded) {
+				t.Errorf("FilterDiff() excluded = %v, want %v", excluded, tt.wantExcluded)
+			}
+			for _, path := range tt.wantKept {
+				if !strings.Contains(got, "diff --git a/"+path) {
+					t.Errorf("FilterDiff() dropped %s", path)
+				}
+			}
+			for _, path := range tt.wantExcluded {
+				if strings.Contains(got, "diff --git a/"+path) {
+					t.Errorf("FilterDiff() kept %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchingFiles(t *testing.T) {
+	got := MatchingFiles(sampleDiff, []string{"vendor/", "*.go"})
+	want := []string{"main.go", "vendor/foo/foo.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchingFiles() = %v, want %v", got, want)
+	}
+
+	if got := MatchingFiles(sampleDiff, nil); got != nil {
+		t.Errorf("MatchingFiles() without patterns = %v, want nil", got)
+	}
+}
+
+func TestFileDiff(t *testing.T) {
+	got := FileDiff(sampleDiff, "main.go")
+	if !strings.HasPrefix(got, "diff --git a/main.go b/main.go") || strings.Count(got, "diff --git") != 1 {
+		t.Errorf("FileDiff(main.go) = %q, want only the main.go section", got)
+	}
+
+	if got := FileDiff(sampleDiff, "missing.go"); got != "" {
+		t.Errorf("FileDiff(missing.go) = %q, want empty", got)
+	}
+}
@@ -2,7 +2,10 @@ package git
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,13 +13,25 @@ import (
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
+// DefaultRemote is the remote branches are pushed to unless configured
+// otherwise
+const DefaultRemote = "origin"
+
 // Repository wraps go-git repository with helper methods
 type Repository struct {
 	repo *git.Repository
 	path string
+
+	// remote is the remote branches are pushed to and compared against
+	remote string
+
+	// system runs status, diff, commit, push and fetch with the git binary
+	// instead of go-git (see UseBackend)
+	system bool
 }
 
 // Open opens a git repository at the given path
@@ -25,200 +40,170 @@ func Open(path string) (*Repository, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
-	return &Repository{repo: repo, path: path}, nil
+	return &Repository{repo: repo, path: path, remote: DefaultRemote}, nil
 }
 
-// OpenCurrent opens the git repository in the current directory
+// OpenCurrent opens the git repository containing the current directory,
+// which may be anywhere inside its worktree
 func OpenCurrent() (*Repository, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current directory: %w", err)
 	}
-	return Open(cwd)
+	return Discover(cwd)
 }
 
-// HasStagedChanges checks if there are any staged changes
-func (r *Repository) HasStagedChanges() (bool, error) {
-	worktree, err := r.repo.Worktree()
+// Discover opens the git repository containing path, searching parent
+// directories for a .git directory like git itself does. Worktrees added
+// with git worktree are supported.
+func Discover(path string) (*Repository, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
 	if err != nil {
-		return false, fmt.Errorf("failed to get worktree: %w", err)
+		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
 
-	status, err := worktree.Status()
-	if err != nil {
-		return false, fmt.Errorf("failed to get status: %w", err)
-	}
+	r := &Repository{repo: repo, path: path, remote: DefaultRemote}
+	r.path = r.Root()
+	return r, nil
+}
 
-	for _, s := range status {
-		// Check if file is staged (in index)
-		if s.Staging != git.Unmodified && s.Staging != git.Untracked {
-			return true, nil
-		}
+// UseRemote sets the remote that branches are pushed to and whose branches
+// are used when a base branch doesn't exist locally (default "origin")
+func (r *Repository) UseRemote(name string) {
+	if name != "" {
+		r.remote = name
 	}
-	return false, nil
 }
 
-// GetStagedDiff returns the diff of all staged changes
-func (r *Repository) GetStagedDiff() (string, error) {
-	worktree, err := r.repo.Worktree()
+// Remote returns the name of the remote branches are pushed to
+func (r *Repository) Remote() string {
+	return r.remote
+}
+
+// FindRoot returns the root of the worktree containing path
+func FindRoot(path string) (string, error) {
+	repo, err := Discover(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to get worktree: %w", err)
+		return "", err
 	}
+	return repo.Root(), nil
+}
 
-	status, err := worktree.Status()
+// Root returns the root directory of the repository's worktree
+func (r *Repository) Root() string {
+	worktree, err := r.repo.Worktree()
 	if err != nil {
-		return "", fmt.Errorf("failed to get status: %w", err)
+		return r.path
 	}
+	return worktree.Filesystem.Root()
+}
 
-	// Get HEAD commit tree (if exists)
-	var headTree *object.Tree
-	headRef, err := r.repo.Head()
-	if err == nil {
-		headCommit, err := r.repo.CommitObject(headRef.Hash())
-		if err == nil {
-			headTree, _ = headCommit.Tree()
-		}
+// gitDir returns the path of the repository's .git directory
+func (r *Repository) gitDir() (string, error) {
+	storage, ok := r.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", fmt.Errorf("repository is not stored on disk")
 	}
+	return storage.Filesystem().Root(), nil
+}
 
-	// Get the index (staged changes)
-	idx, err := r.repo.Storer.Index()
+// IndexModTime returns the last modification time of the index file,
+// which changes whenever files are staged or unstaged
+func (r *Repository) IndexModTime() (time.Time, error) {
+	dir, err := r.gitDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get index: %w", err)
+		return time.Time{}, err
 	}
 
-	var diffBuilder strings.Builder
+	info, err := os.Stat(filepath.Join(dir, "index"))
+	if os.IsNotExist(err) {
+		// A fresh repository has no index until something is staged
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to stat index: %w", err)
+	}
 
-	for filePath, fileStatus := range status {
-		// Only process staged files
-		if fileStatus.Staging == git.Unmodified || fileStatus.Staging == git.Untracked {
-			continue
-		}
+	return info.ModTime(), nil
+}
 
-		diffBuilder.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", filePath, filePath))
-
-		switch fileStatus.Staging {
-		case git.Added:
-			diffBuilder.WriteString("new file\n")
-			// Read content from index
-			for _, entry := range idx.Entries {
-				if entry.Name == filePath {
-					blob, err := r.repo.BlobObject(entry.Hash)
-					if err == nil {
-						reader, err := blob.Reader()
-						if err == nil {
-							content := make([]byte, blob.Size)
-							_, _ = reader.Read(content)
-							reader.Close()
-							diffBuilder.WriteString(fmt.Sprintf("+++ b/%s\n", filePath))
-							for _, line := range strings.Split(string(content), "\n") {
-								diffBuilder.WriteString(fmt.Sprintf("+%s\n", line))
-							}
-						}
-					}
-					break
-				}
-			}
+// CommitOptions customizes Commit
+type CommitOptions struct {
+	// AllowEmpty creates the commit even when nothing is staged, e.g. to
+	// trigger CI
+	AllowEmpty bool
 
-		case git.Modified:
-			// Get old content from HEAD
-			if headTree != nil {
-				file, err := headTree.File(filePath)
-				if err == nil {
-					oldContent, _ := file.Contents()
-					diffBuilder.WriteString(fmt.Sprintf("--- a/%s\n", filePath))
-					diffBuilder.WriteString(fmt.Sprintf("+++ b/%s\n", filePath))
-
-					// Get new content from index
-					for _, entry := range idx.Entries {
-						if entry.Name == filePath {
-							blob, err := r.repo.BlobObject(entry.Hash)
-							if err == nil {
-								reader, err := blob.Reader()
-								if err == nil {
-									content := make([]byte, blob.Size)
-									_, _ = reader.Read(content)
-									reader.Close()
-									newContent := string(content)
-
-									// Simple line-by-line diff
-									oldLines := strings.Split(oldContent, "\n")
-									newLines := strings.Split(newContent, "\n")
-									diffBuilder.WriteString(formatSimpleDiff(oldLines, newLines))
-								}
-							}
-							break
-						}
-					}
-				}
-			}
+	// Signoff adds a Signed-off-by trailer for the author, like git commit
+	// --signoff
+	Signoff bool
 
-		case git.Deleted:
-			diffBuilder.WriteString("deleted file\n")
-			if headTree != nil {
-				file, err := headTree.File(filePath)
-				if err == nil {
-					content, _ := file.Contents()
-					diffBuilder.WriteString(fmt.Sprintf("--- a/%s\n", filePath))
-					for _, line := range strings.Split(content, "\n") {
-						diffBuilder.WriteString(fmt.Sprintf("-%s\n", line))
-					}
-				}
-			}
-		}
-		diffBuilder.WriteString("\n")
-	}
+	// NoVerify skips the pre-commit and commit-msg hooks, like git commit
+	// --no-verify
+	NoVerify bool
 
-	return diffBuilder.String(), nil
+	// Author overrides the author of the commit, e.g. to keep the original
+	// author of a cherry-picked commit. The committer is always the user.
+	Author *Author
 }
 
-// formatSimpleDiff creates a simple unified diff format
-func formatSimpleDiff(oldLines, newLines []string) string {
-	var result strings.Builder
+// Author is who wrote a commit and when
+type Author struct {
+	Name  string
+	Email string
+	When  time.Time
+}
 
-	// Simple approach: show removed lines then added lines
-	oldSet := make(map[string]bool)
-	for _, line := range oldLines {
-		oldSet[line] = true
+// Commit creates a new commit with the given message
+func (r *Repository) Commit(message string, opts CommitOptions) (string, error) {
+	if r.system {
+		return r.systemCommit(message, opts)
 	}
 
-	newSet := make(map[string]bool)
-	for _, line := range newLines {
-		newSet[line] = true
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	// Lines only in old (removed)
-	for _, line := range oldLines {
-		if !newSet[line] {
-			result.WriteString(fmt.Sprintf("-%s\n", line))
-		}
+	// Get author info from various sources
+	authorName, authorEmail := getAuthorInfo(r)
+
+	if opts.Signoff {
+		message = addSignoff(message, fmt.Sprintf("Signed-off-by: %s <%s>", authorName, authorEmail))
 	}
 
-	// Lines only in new (added)
-	for _, line := range newLines {
-		if !oldSet[line] {
-			result.WriteString(fmt.Sprintf("+%s\n", line))
+	// Run the hooks git would, aborting the commit when one fails
+	if !opts.NoVerify {
+		if err := r.runHook("pre-commit"); err != nil {
+			return "", err
+		}
+		if message, err = r.runCommitMsgHook(message); err != nil {
+			return "", err
 		}
 	}
 
-	return result.String()
-}
-
-// Commit creates a new commit with the given message
-func (r *Repository) Commit(message string) (string, error) {
-	worktree, err := r.repo.Worktree()
+	signer, err := r.commitSigner()
 	if err != nil {
-		return "", fmt.Errorf("failed to get worktree: %w", err)
+		return "", fmt.Errorf("failed to sign commit: %w", err)
 	}
 
-	// Get author info from various sources
-	authorName, authorEmail := getAuthorInfo(r)
+	committer := &object.Signature{
+		Name:  authorName,
+		Email: authorEmail,
+		When:  time.Now(),
+	}
+	author := committer
+	if opts.Author != nil {
+		author = &object.Signature{Name: opts.Author.Name, Email: opts.Author.Email, When: opts.Author.When}
+	}
 
 	hash, err := worktree.Commit(message, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  authorName,
-			Email: authorEmail,
-			When:  time.Now(),
-		},
+		Author:            author,
+		Committer:         committer,
+		AllowEmptyCommits: opts.AllowEmpty,
+		Signer:            signer,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to commit: %w", err)
@@ -355,11 +340,11 @@ func (r *Repository) GetDefaultBranch() (string, error) {
 			var defaultBranch string
 			_ = refs.ForEach(func(ref *plumbing.Reference) error {
 				name := ref.Name().String()
-				if strings.Contains(name, "origin/main") {
+				if strings.Contains(name, r.remote+"/main") {
 					defaultBranch = "main"
 					return fmt.Errorf("found")
 				}
-				if strings.Contains(name, "origin/master") {
+				if strings.Contains(name, r.remote+"/master") {
 					defaultBranch = "master"
 				}
 				return nil
@@ -373,86 +358,232 @@ func (r *Repository) GetDefaultBranch() (string, error) {
 	return "", fmt.Errorf("could not determine default branch (no main or master found)")
 }
 
+// baseRef resolves the base branch of a comparison. The push remote's
+// branch is preferred, since FetchBranch keeps it current while the local
+// branch may be stale.
+func (r *Repository) baseRef(base string) (*plumbing.Reference, error) {
+	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remote, base), true)
+	if err == nil {
+		return ref, nil
+	}
+
+	ref, err = r.repo.Reference(plumbing.NewBranchReferenceName(base), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find base branch %s: %w", base, err)
+	}
+	return ref, nil
+}
+
+// FetchBranch updates the push remote's copy of branch
+// (refs/remotes/<remote>/<branch>), authenticating like Push
+func (r *Repository) FetchBranch(token, branch string) error {
+	if r.system {
+		return r.systemFetchBranch(branch)
+	}
+
+	remoteURL, err := r.GetRemoteURL()
+	if err != nil {
+		return err
+	}
+
+	auth, err := remoteAuth(remoteURL, token)
+	if err != nil {
+		return err
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, r.remote, branch))
+	err = r.repo.Fetch(&git.FetchOptions{
+		RemoteName: r.remote,
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{refSpec},
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to fetch %s: %w", branch, err)
+	}
+	return nil
+}
+
 // CommitInfo holds basic commit information
 type CommitInfo struct {
-	Hash    string
+	Hash string
+
+	// Message is the subject, the first line of the commit message
 	Message string
+
+	// Body is the rest of the commit message, if any
+	Body string
+}
+
+// newCommitInfo returns the CommitInfo of c
+func newCommitInfo(c *object.Commit) CommitInfo {
+	subject, body, _ := strings.Cut(c.Message, "\n")
+	return CommitInfo{
+		Hash:    c.Hash.String()[:7],
+		Message: subject,
+		Body:    strings.TrimSpace(body),
+	}
 }
 
-// GetCommitsAhead returns commits on current branch that are ahead of base
+// GetCommitsAhead returns the commits on the current branch since it
+// diverged from base (the commits a PR into base would contain), newest first
 func (r *Repository) GetCommitsAhead(base string) ([]CommitInfo, error) {
-	// Get current branch HEAD
-	head, err := r.repo.Head()
+	headCommit, baseCommit, _, err := r.headAndBase(base)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+		return nil, err
 	}
 
-	// Get base branch reference
-	baseRef, err := r.repo.Reference(plumbing.NewBranchReferenceName(base), true)
+	mergeBase, err := mergeBase(headCommit, baseCommit)
 	if err != nil {
-		// Try remote reference
-		baseRef, err = r.repo.Reference(plumbing.NewRemoteReferenceName("origin", base), true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find base branch %s: %w", base, err)
-		}
+		return nil, err
 	}
 
-	// Get commits from HEAD
-	commitIter, err := r.repo.Log(&git.LogOptions{
-		From: head.Hash(),
-	})
+	ahead, err := commitsBetween(headCommit, mergeBase)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get log: %w", err)
+		return nil, err
 	}
 
-	var commits []CommitInfo
-	baseHash := baseRef.Hash()
+	commits := make([]CommitInfo, 0, len(ahead))
+	for _, c := range ahead {
+		commits = append(commits, newCommitInfo(c))
+	}
 
-	err = commitIter.ForEach(func(c *object.Commit) error {
-		// Stop when we reach the base branch
-		if c.Hash == baseHash {
-			return fmt.Errorf("reached base")
-		}
+	return commits, nil
+}
 
-		commits = append(commits, CommitInfo{
-			Hash:    c.Hash.String()[:7],
-			Message: strings.Split(c.Message, "\n")[0], // First line only
-		})
-		return nil
-	})
+// RecentCommits returns up to n commits reachable from HEAD, newest first
+func (r *Repository) RecentCommits(n int) ([]CommitInfo, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	iter, err := r.repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log: %w", err)
+	}
+	defer iter.Close()
 
-	// Ignore the "reached base" error
-	if err != nil && err.Error() != "reached base" {
-		// Only return error if it's not our stop condition
-		if len(commits) == 0 {
-			return nil, err
+	var commits []CommitInfo
+	for len(commits) < n {
+		c, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk history: %w", err)
 		}
+		commits = append(commits, newCommitInfo(c))
 	}
 
 	return commits, nil
 }
 
-// GetRemoteURL returns the URL of the origin remote
+// GetRemoteURL returns the URL of the push remote
 func (r *Repository) GetRemoteURL() (string, error) {
-	remote, err := r.repo.Remote("origin")
+	return r.GetRemoteURLFor(r.remote)
+}
+
+// GetRemoteURLFor returns the URL of the named remote
+func (r *Repository) GetRemoteURLFor(name string) (string, error) {
+	remote, err := r.repo.Remote(name)
 	if err != nil {
-		return "", fmt.Errorf("failed to get origin remote: %w", err)
+		return "", fmt.Errorf("failed to get %s remote: %w", name, err)
 	}
 
 	urls := remote.Config().URLs
 	if len(urls) == 0 {
-		return "", fmt.Errorf("no URLs configured for origin remote")
+		return "", fmt.Errorf("no URLs configured for %s remote", name)
 	}
 
 	return urls[0], nil
 }
 
-// Push pushes the current branch to origin
-func (r *Repository) Push() error {
-	// Get GitHub token for authentication
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return fmt.Errorf("GITHUB_TOKEN environment variable is not set")
+// HasRemote reports whether a remote with the given name exists
+func (r *Repository) HasRemote(name string) bool {
+	_, err := r.repo.Remote(name)
+	return err == nil
+}
+
+// RemoteNames returns the names of the configured remotes, sorted
+func (r *Repository) RemoteNames() ([]string, error) {
+	remotes, err := r.repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+
+	names := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		names = append(names, remote.Config().Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Branches returns the names of the local branches and of the push
+// remote's branches, which base branches are looked up in, sorted and
+// without duplicates
+func (r *Repository) Branches() ([]string, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	defer refs.Close()
+
+	seen := make(map[string]bool)
+	var names []string
+	_ = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		var branch string
+		switch {
+		case name.IsBranch():
+			branch = name.Short()
+		case name.IsRemote():
+			var ok bool
+			branch, ok = strings.CutPrefix(name.Short(), r.remote+"/")
+			if !ok || branch == "HEAD" {
+				return nil
+			}
+		default:
+			return nil
+		}
+		if !seen[branch] {
+			seen[branch] = true
+			names = append(names, branch)
+		}
+		return nil
+	})
+	sort.Strings(names)
+	return names, nil
+}
+
+// PushOptions customizes Push
+type PushOptions struct {
+	// ForceWithLease overwrites the remote branch, e.g. after a rebase, as
+	// long as it still points where the local remote-tracking branch says.
+	// Commits pushed by someone else in the meantime are never lost.
+	ForceWithLease bool
+
+	// Tags are pushed along with the branch. AllTags pushes every local tag.
+	Tags    []string
+	AllTags bool
+}
+
+// Push pushes the current branch to the push remote. SSH remotes
+// authenticate with the SSH agent or the user's keys, HTTPS remotes with
+// token.
+func (r *Repository) Push(token string, opts PushOptions) error {
+	if r.system {
+		return r.systemPush(opts)
+	}
+
+	remoteURL, err := r.GetRemoteURL()
+	if err != nil {
+		return err
+	}
+
+	auth, err := remoteAuth(remoteURL, token)
+	if err != nil {
+		return err
 	}
 
 	// Get current branch name
@@ -464,83 +595,151 @@ func (r *Repository) Push() error {
 	branchName := head.Name().Short()
 	refSpec := config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", branchName, branchName))
 
-	err = r.repo.Push(&git.PushOptions{
-		RemoteName: "origin",
-		Auth: &http.BasicAuth{
-			Username: "x-access-token", // GitHub uses this for token auth
-			Password: token,
-		},
-		RefSpecs: []config.RefSpec{refSpec},
-	})
+	pushOpts := &git.PushOptions{
+		RemoteName: r.remote,
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{refSpec},
+	}
 
-	if err == git.NoErrAlreadyUpToDate {
-		return nil
+	// The lease is the remote-tracking branch, a branch that was never
+	// pushed needs no force
+	if opts.ForceWithLease {
+		tracking := plumbing.NewRemoteReferenceName(r.remote, branchName)
+		if _, err := r.repo.Reference(tracking, true); err == nil {
+			pushOpts.ForceWithLease = &git.ForceWithLease{}
+		}
 	}
-	if err != nil {
+
+	err = r.repo.Push(pushOpts)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		if strings.Contains(err.Error(), "non-fast-forward") {
+			if opts.ForceWithLease {
+				return fmt.Errorf(`the remote branch has commits you don't have locally
+
+Someone else pushed to '%s'. Fetch and integrate their changes first:
+  git pull --rebase %s %s`, branchName, r.remote, branchName)
+			}
+			return fmt.Errorf(`the remote branch has diverged from your local branch
+
+If you rebased or amended commits, push with --force-with-lease`)
+		}
 		return fmt.Errorf("failed to push: %w", err)
 	}
 
+	if err := r.setUpstream(branchName); err != nil {
+		return err
+	}
+
+	if opts.AllTags || len(opts.Tags) > 0 {
+		// Pushed separately, the lease only applies to the branch
+		return r.pushTags(auth, opts.Tags)
+	}
 	return nil
 }
 
-// GetDiffFromBase returns the combined diff from base branch to current HEAD
-func (r *Repository) GetDiffFromBase(base string) (string, error) {
-	// Get current branch HEAD
-	head, err := r.repo.Head()
-	if err != nil {
-		return "", fmt.Errorf("failed to get HEAD: %w", err)
+// PushTags pushes tags to the push remote, or every local tag when none are
+// given. Annotated tags are pushed with their tag objects.
+func (r *Repository) PushTags(token string, tags ...string) error {
+	if r.system {
+		return r.systemPushTags(tags)
 	}
 
-	headCommit, err := r.repo.CommitObject(head.Hash())
+	remoteURL, err := r.GetRemoteURL()
 	if err != nil {
-		return "", fmt.Errorf("failed to get HEAD commit: %w", err)
+		return err
 	}
 
-	// Get base branch reference
-	baseRef, err := r.repo.Reference(plumbing.NewBranchReferenceName(base), true)
+	auth, err := remoteAuth(remoteURL, token)
 	if err != nil {
-		// Try remote reference
-		baseRef, err = r.repo.Reference(plumbing.NewRemoteReferenceName("origin", base), true)
-		if err != nil {
-			return "", fmt.Errorf("failed to find base branch %s: %w", base, err)
+		return err
+	}
+
+	return r.pushTags(auth, tags)
+}
+
+// pushTags pushes the given tags, or all tags when tags is empty
+func (r *Repository) pushTags(auth transport.AuthMethod, tags []string) error {
+	refSpecs := []config.RefSpec{"refs/tags/*:refs/tags/*"}
+	if len(tags) > 0 {
+		refSpecs = refSpecs[:0]
+		for _, tag := range tags {
+			name := plumbing.NewTagReferenceName(tag)
+			if _, err := r.repo.Reference(name, false); err != nil {
+				return fmt.Errorf("tag %s not found", tag)
+			}
+			refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("%s:%s", name, name)))
 		}
 	}
 
-	baseCommit, err := r.repo.CommitObject(baseRef.Hash())
-	if err != nil {
-		return "", fmt.Errorf("failed to get base commit: %w", err)
+	err := r.repo.Push(&git.PushOptions{
+		RemoteName: r.remote,
+		Auth:       auth,
+		RefSpecs:   refSpecs,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to push tags: %w", err)
 	}
+	return nil
+}
 
-	// Get trees
-	headTree, err := headCommit.Tree()
+// setUpstream makes the push remote's branch the upstream of a local branch
+// (branch.<name>.remote and branch.<name>.merge), like git push -u, unless
+// the branch already tracks something
+func (r *Repository) setUpstream(branchName string) error {
+	cfg, err := r.repo.Config()
 	if err != nil {
-		return "", fmt.Errorf("failed to get HEAD tree: %w", err)
+		return fmt.Errorf("failed to read git config: %w", err)
+	}
+
+	branch, ok := cfg.Branches[branchName]
+	if ok && branch.Remote != "" {
+		return nil
+	}
+	if !ok {
+		// Existing entries are updated in place to keep their other
+		// settings, such as a linked issue
+		branch = &config.Branch{Name: branchName}
+		cfg.Branches[branchName] = branch
+	}
+	branch.Remote = r.remote
+	branch.Merge = plumbing.NewBranchReferenceName(branchName)
+	if err := r.repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("failed to set upstream branch: %w", err)
+	}
+	return nil
+}
+
+// GetDiffFromBase returns the combined diff of the current branch since it
+// diverged from base, leaving out changes made on base in the meantime
+func (r *Repository) GetDiffFromBase(base string) (string, error) {
+	if r.system {
+		return r.systemDiffFromBase(base)
 	}
 
-	baseTree, err := baseCommit.Tree()
+	headCommit, baseCommit, _, err := r.headAndBase(base)
 	if err != nil {
-		return "", fmt.Errorf("failed to get base tree: %w", err)
+		return "", err
 	}
 
-	// Calculate diff
-	changes, err := baseTree.Diff(headTree)
+	mergeBase, err := mergeBase(headCommit, baseCommit)
 	if err != nil {
-		return "", fmt.Errorf("failed to calculate diff: %w", err)
+		return "", err
 	}
 
-	var diffBuilder strings.Builder
-	for _, change := range changes {
-		patch, err := change.Patch()
-		if err != nil {
-			continue
-		}
-		diffBuilder.WriteString(patch.String())
+	baseFiles, err := commitFiles(mergeBase)
+	if err != nil {
+		return "", err
+	}
+	headFiles, err := commitFiles(headCommit)
+	if err != nil {
+		return "", err
 	}
 
-	return diffBuilder.String(), nil
+	return r.newDiffer().diff(baseFiles, headFiles)
 }
 
-// NeedsPush checks if current branch has commits not yet pushed to origin
+// NeedsPush checks if current branch has commits not yet pushed to the push
+// remote
 func (r *Repository) NeedsPush() (bool, error) {
 	head, err := r.repo.Head()
 	if err != nil {
@@ -551,7 +750,7 @@ func (r *Repository) NeedsPush() (bool, error) {
 
 	// Get remote tracking branch
 	remoteRef, err := r.repo.Reference(
-		plumbing.NewRemoteReferenceName("origin", branchName),
+		plumbing.NewRemoteReferenceName(r.remote, branchName),
 		true,
 	)
 	if err != nil {
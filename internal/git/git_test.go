@@ -0,0 +1,260 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestSetUpstream(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := git.PlainInit(dir, false); err != nil {
+		t.Fatalf("PlainInit() error = %v", err)
+	}
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	repo.UseRemote("gh")
+
+	if err := repo.setUpstream("feature"); err != nil {
+		t.Fatalf("setUpstream() error = %v", err)
+	}
+
+	cfg, err := repo.repo.Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	branch := cfg.Branches["feature"]
+	if branch == nil || branch.Remote != "gh" || branch.Merge != "refs/heads/feature" {
+		t.Fatalf("branch config = %+v, want remote gh and merge refs/heads/feature", branch)
+	}
+
+	// An existing upstream is left alone
+	cfg.Branches["feature"] = &config.Branch{Name: "feature", Remote: "origin", Merge: "refs/heads/other"}
+	if err := repo.repo.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if err := repo.setUpstream("feature"); err != nil {
+		t.Fatalf("setUpstream() error = %v", err)
+	}
+	cfg, _ = repo.repo.Config()
+	if cfg.Branches["feature"].Remote != "origin" {
+		t.Errorf("setUpstream() replaced an existing upstream: %+v", cfg.Branches["feature"])
+	}
+}
+
+func TestPushTags(t *testing.T) {
+	remoteDir := t.TempDir()
+	if _, err := git.PlainInit(remoteDir, true); err != nil {
+		t.Fatalf("PlainInit() error = %v", err)
+	}
+
+	repo := newTestRepo(t)
+	if _, err := repo.repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}}); err != nil {
+		t.Fatalf("CreateRemote() error = %v", err)
+	}
+
+	head, err := repo.repo.Head()
+	if err != nil {
+		t.Fatalf("Head() error = %v", err)
+	}
+	_, err = repo.repo.CreateTag("v1.0.0", head.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		Message: "Release 1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+
+	if err := repo.pushTags(nil, []string{"missing"}); err == nil {
+		t.Error("pushTags() with unknown tag should fail")
+	}
+	if err := repo.pushTags(nil, []string{"v1.0.0"}); err != nil {
+		t.Fatalf("pushTags() error = %v", err)
+	}
+
+	remote, err := git.PlainOpen(remoteDir)
+	if err != nil {
+		t.Fatalf("PlainOpen() error = %v", err)
+	}
+	ref, err := remote.Tag("v1.0.0")
+	if err != nil {
+		t.Fatalf("tag not pushed: %v", err)
+	}
+	if _, err := remote.TagObject(ref.Hash()); err != nil {
+		t.Errorf("pushed tag is not annotated: %v", err)
+	}
+}
+
+// newTestRepo creates a repository with a single commit
+func newTestRepo(t *testing.T) *Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	gitRepo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("PlainInit() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	worktree, err := gitRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	if _, err := worktree.Add("README.md"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	_, err = worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return repo
+}
+
+func TestFetchBranchPrefersRemoteBase(t *testing.T) {
+	upstream := newTestRepo(t)
+
+	repo := newTestRepo(t)
+	if _, err := repo.repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{upstream.Root()}}); err != nil {
+		t.Fatalf("CreateRemote() error = %v", err)
+	}
+
+	if err := repo.FetchBranch("", "master"); err != nil {
+		t.Fatalf("FetchBranch() error = %v", err)
+	}
+
+	upstreamHead, _ := upstream.repo.Head()
+	ref, err := repo.baseRef("master")
+	if err != nil {
+		t.Fatalf("baseRef() error = %v", err)
+	}
+	if ref.Hash() != upstreamHead.Hash() {
+		t.Errorf("baseRef() = %s, want the fetched remote branch %s", ref.Hash(), upstreamHead.Hash())
+	}
+}
+
+func TestBranchesAndRemotes(t *testing.T) {
+	upstream := newTestRepo(t)
+	if err := upstream.CreateBranch("release"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	repo := newTestRepo(t)
+	for _, name := range []string{"origin", "fork"} {
+		if _, err := repo.repo.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{upstream.Root()}}); err != nil {
+			t.Fatalf("CreateRemote() error = %v", err)
+		}
+	}
+	if err := repo.FetchBranch("", "master"); err != nil {
+		t.Fatalf("FetchBranch() error = %v", err)
+	}
+	if err := repo.FetchBranch("", "release"); err != nil {
+		t.Fatalf("FetchBranch() error = %v", err)
+	}
+
+	remotes, err := repo.RemoteNames()
+	if err != nil {
+		t.Fatalf("RemoteNames() error = %v", err)
+	}
+	if got := strings.Join(remotes, ","); got != "fork,origin" {
+		t.Errorf("RemoteNames() = %s, want fork,origin", got)
+	}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		t.Fatalf("Branches() error = %v", err)
+	}
+	if got := strings.Join(branches, ","); got != "master,release" {
+		t.Errorf("Branches() = %s, want master,release", got)
+	}
+
+	// Only the push remote's branches are bases
+	repo.UseRemote("fork")
+	branches, _ = repo.Branches()
+	if got := strings.Join(branches, ","); got != "master" {
+		t.Errorf("Branches() with remote fork = %s, want master", got)
+	}
+}
+
+func TestOpenCurrentFromSubdirectory(t *testing.T) {
+	repo := newTestRepo(t)
+	subdir := filepath.Join(repo.Root(), "internal", "pkg")
+	if err := os.MkdirAll(subdir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	t.Chdir(subdir)
+
+	opened, err := OpenCurrent()
+	if err != nil {
+		t.Fatalf("OpenCurrent() error = %v", err)
+	}
+	if opened.Root() != repo.Root() {
+		t.Errorf("Root() = %q, want %q", opened.Root(), repo.Root())
+	}
+	if _, err := opened.GetStagedDiff(); err != nil {
+		t.Errorf("GetStagedDiff() error = %v", err)
+	}
+
+	t.Chdir(t.TempDir())
+	if _, err := OpenCurrent(); err == nil {
+		t.Error("OpenCurrent() outside a repository should fail")
+	}
+}
+
+func TestRecentCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+	commitFile(t, repo, "a.txt", "a\n", now.Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", now.Add(2*time.Minute))
+
+	commits, err := repo.RecentCommits(2)
+	if err != nil {
+		t.Fatalf("RecentCommits() error = %v", err)
+	}
+
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Message)
+	}
+	if want := "Update b.txt,Update a.txt"; strings.Join(subjects, ",") != want {
+		t.Errorf("RecentCommits(2) = %v, want %s", subjects, want)
+	}
+}
+
+func TestCommitAllowEmpty(t *testing.T) {
+	repo := newTestRepo(t)
+	before := headHash(t, repo)
+
+	if _, err := repo.Commit("Nothing staged", CommitOptions{}); err == nil {
+		t.Fatal("Commit() without changes succeeded, want an error")
+	}
+
+	if _, err := repo.Commit("Trigger CI", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Fatalf("Commit(AllowEmpty) error = %v", err)
+	}
+	commit, err := repo.repo.CommitObject(headHash(t, repo))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if commit.Message != "Trigger CI" || commit.ParentHashes[0] != before {
+		t.Errorf("HEAD = %q with parent %s, want the empty commit on top of %s", commit.Message, commit.ParentHashes[0], before)
+	}
+}
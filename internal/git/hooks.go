@@ -0,0 +1,170 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// HookError is returned when a hook rejects a commit
+type HookError struct {
+	Hook string
+	Err  error
+}
+
+func (e *HookError) Error() string { return e.Hook + " hook failed: " + e.Err.Error() }
+func (e *HookError) Unwrap() error { return e.Err }
+
+// hooksDir returns the directory hooks are run from: core.hooksPath
+// (used by husky and similar tools) or the hooks directory of the
+// repository
+func (r *Repository) hooksDir() (string, error) {
+	if path := r.configValue("core", "hookspath"); path != "" {
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to find home directory: %w", err)
+			}
+			return filepath.Join(home, rest), nil
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.Root(), path)
+		}
+		return path, nil
+	}
+
+	dir, err := r.gitDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Linked worktrees share the hooks of the main repository
+	if common, err := os.ReadFile(filepath.Join(dir, "commondir")); err == nil {
+		path := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		dir = path
+	}
+	return filepath.Join(dir, "hooks"), nil
+}
+
+// runHook runs the named hook with args from the repository root, like git
+// does, if it exists and is executable. Its output goes to the terminal.
+func (r *Repository) runHook(name string, args ...string) error {
+	dir, err := r.hooksDir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || (runtime.GOOS != "windows" && info.Mode()&0o111 == 0) {
+		return nil
+	}
+
+	cmd := exec.Command(path, args...)
+	if runtime.GOOS == "windows" {
+		// Hooks are shell scripts, Git for Windows runs them with its sh
+		cmd = exec.Command("sh", append([]string{path}, args...)...)
+	}
+	cmd.Dir = r.Root()
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	gitDir, err := r.gitDir()
+	if err == nil {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(gitDir, "index"))
+	}
+
+	if err := cmd.Run(); err != nil {
+		return &HookError{Hook: name, Err: err}
+	}
+	return nil
+}
+
+// runCommitMsgHook runs the commit-msg hook on message and returns the
+// message as the hook left it, hooks may rewrite it (e.g. adding a
+// Change-Id trailer)
+func (r *Repository) runCommitMsgHook(message string) (string, error) {
+	dir, err := r.gitDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(message), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write commit message: %w", err)
+	}
+
+	if err := r.runHook("commit-msg", path); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit message: %w", err)
+	}
+	return string(edited), nil
+}
+
+// hookMarker identifies hooks written by InstallHook, so they can be
+// replaced and removed without touching anyone else's hooks
+const hookMarker = "installed by vibe"
+
+// InstallHook writes script as the named hook and returns its path. An
+// existing hook not installed by vibe is only replaced with force.
+func (r *Repository) InstallHook(name, script string, force bool) (string, error) {
+	dir, err := r.hooksDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+
+	if existing, err := os.ReadFile(path); err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+		return "", fmt.Errorf(`a %s hook already exists at %s
+
+Add vibe to it by hand, or replace it with:
+  vibe hook install --force`, name, path)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write %s hook: %w", name, err)
+	}
+	return path, nil
+}
+
+// UninstallHook removes the named hook if vibe installed it and reports
+// whether it did
+func (r *Repository) UninstallHook(name string) (bool, error) {
+	dir, err := r.hooksDir()
+	if err != nil {
+		return false, err
+	}
+	path := filepath.Join(dir, name)
+
+	existing, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(existing), hookMarker) {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove %s hook: %w", name, err)
+	}
+	return true, nil
+}
+
+// HookScript returns a hook that hands over to vibe hook <name>, marked as
+// installed by vibe. Commits go ahead as usual when vibe isn't on the PATH.
+func HookScript(name string) string {
+	return fmt.Sprintf(`#!/bin/sh
+# %s hook %s
+command -v vibe >/dev/null 2>&1 || exit 0
+exec vibe hook %s "$@"
+`, name, hookMarker, name)
+}
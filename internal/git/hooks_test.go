@@ -0,0 +1,105 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCommitRunsHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	repo := newTestRepo(t)
+	hooks := filepath.Join(repo.Root(), ".git", "hooks")
+	writeHook := func(dir, name, script string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	// A failing pre-commit hook aborts the commit
+	before := headHash(t, repo)
+	writeHook(hooks, "pre-commit", "echo 'lint failed' >&2\nexit 1\n")
+	_, err := repo.Commit("Blocked", CommitOptions{AllowEmpty: true})
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Hook != "pre-commit" {
+		t.Fatalf("Commit() error = %v, want the pre-commit hook to fail", err)
+	}
+	if headHash(t, repo) != before {
+		t.Fatal("Commit() created a commit despite the failing hook")
+	}
+
+	// NoVerify skips the hooks
+	if _, err := repo.Commit("Emergency", CommitOptions{AllowEmpty: true, NoVerify: true}); err != nil {
+		t.Fatalf("Commit(NoVerify) error = %v", err)
+	}
+
+	// commit-msg hooks may rewrite the message
+	writeHook(hooks, "pre-commit", "exit 0\n")
+	writeHook(hooks, "commit-msg", "printf '\\n\\nChange-Id: I123\\n' >> \"$1\"\n")
+	if _, err := repo.Commit("Add feature", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	commit, err := repo.repo.CommitObject(headHash(t, repo))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if commit.Message != "Add feature\n\nChange-Id: I123\n" {
+		t.Errorf("commit message = %q, want the hook's trailer", commit.Message)
+	}
+
+	// core.hooksPath replaces the hooks directory, as husky sets it up
+	cfg, err := repo.repo.Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	cfg.Raw.Section("core").SetOption("hooksPath", ".husky")
+	if err := repo.repo.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	writeHook(filepath.Join(repo.Root(), ".husky"), "pre-commit", "exit 1\n")
+	if _, err := repo.Commit("Blocked by husky", CommitOptions{AllowEmpty: true}); err == nil {
+		t.Error("Commit() ignored the pre-commit hook in core.hooksPath")
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	repo := newTestRepo(t)
+	path := filepath.Join(repo.Root(), ".git", "hooks", "prepare-commit-msg")
+
+	if _, err := repo.InstallHook("prepare-commit-msg", HookScript("prepare-commit-msg"), false); err != nil {
+		t.Fatalf("InstallHook() error = %v", err)
+	}
+	// Reinstalling replaces vibe's own hook
+	if _, err := repo.InstallHook("prepare-commit-msg", HookScript("prepare-commit-msg"), false); err != nil {
+		t.Fatalf("InstallHook() again error = %v", err)
+	}
+	if removed, err := repo.UninstallHook("prepare-commit-msg"); err != nil || !removed {
+		t.Fatalf("UninstallHook() = %v, %v, want true", removed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("hook still exists after UninstallHook(): %v", err)
+	}
+
+	// Hooks of other tools are left alone unless forced
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := repo.InstallHook("prepare-commit-msg", HookScript("prepare-commit-msg"), false); err == nil {
+		t.Error("InstallHook() replaced a foreign hook without force")
+	}
+	if removed, _ := repo.UninstallHook("prepare-commit-msg"); removed {
+		t.Error("UninstallHook() removed a foreign hook")
+	}
+	if _, err := repo.InstallHook("prepare-commit-msg", HookScript("prepare-commit-msg"), true); err != nil {
+		t.Errorf("InstallHook(force) error = %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/utils/binary"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Hunk is a block of unstaged changes in a file
+type Hunk struct {
+	// Header is the unified diff hunk header, e.g. "@@ -10,7 +10,8 @@"
+	Header string
+
+	// Lines are the lines of the hunk with their " ", "-" or "+" prefix
+	Lines []string
+
+	// oldStart and oldCount are the staged lines the hunk replaces with
+	// newLines, context excluded
+	oldStart, oldCount int
+	newLines           []string
+}
+
+// String formats the hunk like git diff does
+func (h Hunk) String() string {
+	return h.Header + "\n" + strings.Join(h.Lines, "\n")
+}
+
+// FileHunks are the unstaged hunks of one file
+type FileHunks struct {
+	Path  string
+	Hunks []Hunk
+
+	// staged are the lines of the staged version the hunks apply to
+	staged []string
+}
+
+// lineOp is one line of a line diff
+type lineOp struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// UnstagedHunks returns the hunks of the unstaged changes to tracked text
+// files, comparing the index with the worktree like git add -p. Deleted,
+// binary and untracked files are left out.
+func (r *Repository) UnstagedHunks() ([]*FileHunks, error) {
+	files, err := r.ChangedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+
+	d := r.newDiffer()
+	var result []*FileHunks
+	for _, f := range files {
+		if f.Status[1] != 'M' {
+			continue
+		}
+
+		entry, err := idx.Entry(f.Path)
+		if err != nil || entry.Mode == filemode.Submodule {
+			continue
+		}
+
+		staged, err := d.read(&diffFile{path: f.Path, hash: entry.Hash, mode: entry.Mode})
+		if err != nil {
+			return nil, err
+		}
+		if staged.binary {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(r.Root(), f.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, err)
+		}
+		if isBinary, _ := binary.IsBinary(bytes.NewReader(content)); isBinary {
+			continue
+		}
+
+		hunks := buildHunks(lineDiff(staged.content, string(content)))
+		if len(hunks) > 0 {
+			result = append(result, &FileHunks{Path: f.Path, Hunks: hunks, staged: splitLines(staged.content)})
+		}
+	}
+
+	return result, nil
+}
+
+// StageHunks stages the hunks of f whose accepted flag is set, applying
+// them to the staged version of the file. Hunks of the same file must be
+// staged in a single call.
+func (r *Repository) StageHunks(f *FileHunks, accepted []bool) error {
+	var content strings.Builder
+	next := 0
+	for i, h := range f.Hunks {
+		if !accepted[i] {
+			continue
+		}
+		for _, line := range f.staged[next:h.oldStart] {
+			content.WriteString(line)
+		}
+		for _, line := range h.newLines {
+			content.WriteString(line)
+		}
+		next = h.oldStart + h.oldCount
+	}
+	if next == 0 {
+		// Nothing accepted
+		return nil
+	}
+	for _, line := range f.staged[next:] {
+		content.WriteString(line)
+	}
+
+	obj := r.repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Path, err)
+	}
+	if _, err := w.Write([]byte(content.String())); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Path, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Path, err)
+	}
+	hash, err := r.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Path, err)
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to get index: %w", err)
+	}
+	entry, err := idx.Entry(f.Path)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Path, err)
+	}
+	entry.Hash = hash
+	// The stat data belongs to neither version now, make git recheck
+	entry.Size, entry.ModifiedAt = 0, time.Time{}
+
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// lineDiff returns the line diff turning old into new
+func lineDiff(old, new string) []lineOp {
+	var ops []lineOp
+	for _, d := range diff.Do(old, new) {
+		for _, line := range splitLines(d.Text) {
+			ops = append(ops, lineOp{op: d.Type, text: line})
+		}
+	}
+	return ops
+}
+
+// buildHunks groups the changes of a line diff into hunks with
+// diffContextLines of context, merging changes whose context overlaps
+// like git does
+func buildHunks(ops []lineOp) []Hunk {
+	// Find the runs of changed lines, as [start, end) indexes into ops
+	var runs [][2]int
+	for i := 0; i < len(ops); {
+		if ops[i].op == diffmatchpatch.DiffEqual {
+			i++
+			continue
+		}
+		start := i
+		for i < len(ops) && ops[i].op != diffmatchpatch.DiffEqual {
+			i++
+		}
+		if n := len(runs); n > 0 && start-runs[n-1][1] <= 2*diffContextLines {
+			runs[n-1][1] = i
+		} else {
+			runs = append(runs, [2]int{start, i})
+		}
+	}
+
+	// Line numbers of the staged and worktree versions at each op
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.op != diffmatchpatch.DiffInsert {
+			oldLine[i+1]++
+		}
+		if op.op != diffmatchpatch.DiffDelete {
+			newLine[i+1]++
+		}
+	}
+
+	hunks := make([]Hunk, 0, len(runs))
+	for _, run := range runs {
+		from := max(run[0]-diffContextLines, 0)
+		to := min(run[1]+diffContextLines, len(ops))
+
+		h := Hunk{oldStart: oldLine[run[0]], oldCount: oldLine[run[1]] - oldLine[run[0]]}
+		for i := from; i < to; i++ {
+			prefix := " "
+			switch ops[i].op {
+			case diffmatchpatch.DiffDelete:
+				prefix = "-"
+			case diffmatchpatch.DiffInsert:
+				prefix = "+"
+			}
+			h.Lines = append(h.Lines, prefix+strings.TrimSuffix(ops[i].text, "\n"))
+
+			if i >= run[0] && i < run[1] && ops[i].op != diffmatchpatch.DiffDelete {
+				h.newLines = append(h.newLines, ops[i].text)
+			}
+		}
+
+		h.Header = fmt.Sprintf("@@ -%s +%s @@",
+			hunkRange(oldLine[from], oldLine[to]-oldLine[from]),
+			hunkRange(newLine[from], newLine[to]-newLine[from]))
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// hunkRange formats the line range of a hunk header from a 0-based start
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range names the line before it
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits text into lines, keeping their line endings
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
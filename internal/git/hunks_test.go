@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildHunks(t *testing.T) {
+	var old, changed strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&old, "line %d\n", i)
+		switch i {
+		case 2:
+			changed.WriteString("line two\n")
+		case 18:
+			// Deleted
+		default:
+			fmt.Fprintf(&changed, "line %d\n", i)
+		}
+	}
+	changed.WriteString("line 21\n")
+
+	hunks := buildHunks(lineDiff(old.String(), changed.String()))
+	if len(hunks) != 2 {
+		t.Fatalf("buildHunks() returned %d hunks, want 2", len(hunks))
+	}
+
+	// The deletion of line 18 and the addition after line 20 are close
+	// enough to share their context
+	tests := []struct {
+		header string
+		lines  string
+	}{
+		{"@@ -1,5 +1,5 @@", " line 1\n-line 2\n+line two\n line 3\n line 4\n line 5"},
+		{"@@ -15,6 +15,6 @@", " line 15\n line 16\n line 17\n-line 18\n line 19\n line 20\n+line 21"},
+	}
+	for i, tt := range tests {
+		if hunks[i].Header != tt.header {
+			t.Errorf("hunk %d header = %q, want %q", i, hunks[i].Header, tt.header)
+		}
+		if got := strings.Join(hunks[i].Lines, "\n"); got != tt.lines {
+			t.Errorf("hunk %d lines = %q, want %q", i, got, tt.lines)
+		}
+	}
+}
+
+func TestStageHunks(t *testing.T) {
+	repo := newTestRepo(t)
+
+	var content strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	committed := content.String()
+	commitFile(t, repo, "list.txt", committed, time.Now())
+
+	edited := strings.Replace(committed, "line 2\n", "line two\n", 1)
+	edited = strings.Replace(edited, "line 19\n", "line nineteen\n", 1)
+	path := filepath.Join(repo.Root(), "list.txt")
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	files, err := repo.UnstagedHunks()
+	if err != nil {
+		t.Fatalf("UnstagedHunks() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "list.txt" || len(files[0].Hunks) != 2 {
+		t.Fatalf("UnstagedHunks() = %+v, want 2 hunks in list.txt", files)
+	}
+
+	if err := repo.StageHunks(files[0], []bool{false, true}); err != nil {
+		t.Fatalf("StageHunks() error = %v", err)
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	if !strings.Contains(diff, "+line nineteen") || strings.Contains(diff, "line two") {
+		t.Errorf("GetStagedDiff() after staging the second hunk:\n%s", diff)
+	}
+
+	// The worktree keeps both changes and git sees the rest as unstaged
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != edited {
+		t.Errorf("worktree content changed to %q", got)
+	}
+
+	if _, err := exec.LookPath("git"); err == nil {
+		out, err := exec.Command("git", "-C", repo.Root(), "diff").CombinedOutput()
+		if err != nil {
+			t.Fatalf("git diff error = %v: %s", err, out)
+		}
+		if !strings.Contains(string(out), "+line two") || strings.Contains(string(out), "nineteen") {
+			t.Errorf("git diff after staging the second hunk:\n%s", out)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-git/go-git/v5/config"
+)
+
+// branchIssueKey is the branch.<name> setting holding the issue a branch
+// was started for
+const branchIssueKey = "vibe-issue"
+
+// LinkIssue records that branch works on an issue, so its pull request
+// can close it, in the branch's git config (branch.<name>.vibe-issue)
+func (r *Repository) LinkIssue(branch string, number int) error {
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to read git config: %w", err)
+	}
+
+	// go-git only keeps the settings of branches it knows about, so the
+	// branch entry has to exist before the raw option is added
+	if _, ok := cfg.Branches[branch]; !ok {
+		cfg.Branches[branch] = &config.Branch{Name: branch}
+		if err := r.repo.SetConfig(cfg); err != nil {
+			return fmt.Errorf("failed to link issue: %w", err)
+		}
+		if cfg, err = r.repo.Config(); err != nil {
+			return fmt.Errorf("failed to read git config: %w", err)
+		}
+	}
+
+	cfg.Raw.Section("branch").Subsection(branch).SetOption(branchIssueKey, strconv.Itoa(number))
+	if err := r.repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("failed to link issue: %w", err)
+	}
+	return nil
+}
+
+// LinkedIssue returns the issue linked to branch with LinkIssue, or 0
+func (r *Repository) LinkedIssue(branch string) int {
+	cfg, err := r.repo.Config()
+	if err != nil || !cfg.Raw.Section("branch").HasSubsection(branch) {
+		return 0
+	}
+
+	number, err := strconv.Atoi(cfg.Raw.Section("branch").Subsection(branch).Option(branchIssueKey))
+	if err != nil || number <= 0 {
+		return 0
+	}
+	return number
+}
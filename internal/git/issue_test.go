@@ -0,0 +1,35 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLinkIssue(t *testing.T) {
+	repo := newTestRepo(t)
+
+	if got := repo.LinkedIssue("123-fix-login"); got != 0 {
+		t.Errorf("LinkedIssue() before linking = %d, want 0", got)
+	}
+	if err := repo.LinkIssue("123-fix-login", 123); err != nil {
+		t.Fatalf("LinkIssue() error = %v", err)
+	}
+
+	// Setting the upstream on the first push keeps the link
+	repo.UseRemote("origin")
+	if err := repo.setUpstream("123-fix-login"); err != nil {
+		t.Fatalf("setUpstream() error = %v", err)
+	}
+	if got := repo.LinkedIssue("123-fix-login"); got != 123 {
+		t.Errorf("LinkedIssue() = %d, want 123", got)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return
+	}
+	out, err := exec.Command("git", "-C", repo.Root(), "config", "branch.123-fix-login.vibe-issue").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "123" {
+		t.Errorf("git config branch.123-fix-login.vibe-issue = %q, %v, want 123", out, err)
+	}
+}
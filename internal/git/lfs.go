@@ -0,0 +1,53 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	// lfsPointerVersion starts every Git LFS pointer file
+	lfsPointerVersion = "version https://git-lfs.github.com/spec/"
+
+	// lfsPointerMaxSize is the largest a pointer file can be, bigger files
+	// are never pointers
+	lfsPointerMaxSize = 1024
+)
+
+// parseLFSPointer reads a Git LFS pointer file, returning the size of the
+// object it points to. Pointers look like:
+//
+//	version https://git-lfs.github.com/spec/v1
+//	oid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393
+//	size 12345
+func parseLFSPointer(content []byte) (int64, bool) {
+	if len(content) > lfsPointerMaxSize || !bytes.HasPrefix(content, []byte(lfsPointerVersion)) {
+		return 0, false
+	}
+
+	var (
+		size   int64 = -1
+		hasOID bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			hasOID = strings.HasPrefix(value, "sha256:")
+		case "size":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, false
+			}
+			size = n
+		}
+	}
+
+	if !hasOID || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
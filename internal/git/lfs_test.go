@@ -0,0 +1,67 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lfsPointer(oid string, size int) string {
+	return fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, size)
+}
+
+func TestParseLFSPointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantSize int64
+		wantOK   bool
+	}{
+		{"Pointer", lfsPointer(strings.Repeat("a", 64), 12345), 12345, true},
+		{"Missing size", "version https://git-lfs.github.com/spec/v1\noid sha256:abc\n", 0, false},
+		{"Missing oid", "version https://git-lfs.github.com/spec/v1\nsize 10\n", 0, false},
+		{"Invalid size", "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize big\n", 0, false},
+		{"Plain text", "hello\n", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, ok := parseLFSPointer([]byte(tt.content))
+			if size != tt.wantSize || ok != tt.wantOK {
+				t.Errorf("parseLFSPointer() = %d, %v, want %d, %v", size, ok, tt.wantSize, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDiffSummarizesLFSObjects(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "video.mp4", lfsPointer(strings.Repeat("a", 64), 96*1024), time.Now())
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	pointer := lfsPointer(strings.Repeat("b", 64), 120*1024)
+	if err := os.WriteFile(filepath.Join(repo.Root(), "video.mp4"), []byte(pointer), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := worktree.Add("video.mp4"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+
+	if !strings.Contains(diff, "diff --git a/video.mp4 b/video.mp4\n") || !strings.HasSuffix(diff, "\nLFS object changed, 96KB -> 120KB\n") {
+		t.Errorf("GetStagedDiff() should describe the LFS object:\n%s", diff)
+	}
+	if strings.Contains(diff, "sha256:") {
+		t.Errorf("GetStagedDiff() should not show the pointer text:\n%s", diff)
+	}
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"strings"
+)
+
+// zeroHash is how git names a missing ref in hook input, e.g. the remote
+// side of a branch pushed for the first time
+const zeroHash = "0000000000000000000000000000000000000000"
+
+// OutgoingCommit is a commit about to be pushed, with its changes
+type OutgoingCommit struct {
+	Hash    string
+	Subject string
+	Patch   string
+}
+
+// OutgoingCommits returns the commits pushing local over remote on the
+// named remote would send, oldest first, as reported to the pre-push hook.
+// For new branches (remote is the zero hash) and remote commits that aren't
+// known locally, commits already on any branch of the remote are left out.
+// It uses the git binary, which is always there when git runs a hook.
+func (r *Repository) OutgoingCommits(remoteName, local, remote string) ([]OutgoingCommit, error) {
+	if local == zeroHash {
+		// Deleting a branch sends no commits
+		return nil, nil
+	}
+
+	revs := []string{local, "--not", "--remotes=" + remoteName}
+	if remote != zeroHash {
+		if _, err := r.runGit("", "cat-file", "-e", remote+"^{commit}"); err == nil {
+			revs = []string{remote + ".." + local}
+		}
+	}
+
+	args := append([]string{"log", "--reverse", "-p", "--no-color", "--no-ext-diff", "--format=%x00%H%x00%s%x00"}, revs...)
+	output, err := r.runGit("", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each commit is "\x00<hash>\x00<subject>\x00" followed by its patch
+	fields := strings.Split(output, "\x00")
+	var commits []OutgoingCommit
+	for i := 1; i+2 < len(fields); i += 3 {
+		commits = append(commits, OutgoingCommit{
+			Hash:    fields[i],
+			Subject: fields[i+1],
+			Patch:   strings.TrimPrefix(fields[i+2], "\n"),
+		})
+	}
+	return commits, nil
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/config"
+)
+
+func TestOutgoingCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	upstream := newTestRepo(t)
+	repo := newTestRepo(t)
+	if _, err := repo.repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{upstream.Root()}}); err != nil {
+		t.Fatalf("CreateRemote() error = %v", err)
+	}
+	gitRun(t, repo.Root(), "fetch", "-q", "origin")
+	gitRun(t, repo.Root(), "reset", "-q", "--hard", "origin/master")
+
+	pushed := headHash(t, repo).String()
+	now := time.Now()
+	commitFile(t, repo, "a.txt", "a\n", now.Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", now.Add(2*time.Minute))
+	local := headHash(t, repo).String()
+
+	check := func(name, remote string) {
+		t.Helper()
+		commits, err := repo.OutgoingCommits("origin", local, remote)
+		if err != nil {
+			t.Fatalf("%s: OutgoingCommits() error = %v", name, err)
+		}
+		if len(commits) != 2 || commits[0].Subject != "Update a.txt" || commits[1].Subject != "Update b.txt" {
+			t.Fatalf("%s: OutgoingCommits() = %+v, want the two new commits oldest first", name, commits)
+		}
+		if got := DiffStat(commits[1].Patch); len(got) != 1 || got[0].Path != "b.txt" || got[0].Added != 1 {
+			t.Errorf("%s: patch of the second commit = %q", name, commits[1].Patch)
+		}
+	}
+
+	check("existing branch", pushed)
+	// A new branch sends what no branch of the remote has
+	check("new branch", zeroHash)
+
+	if commits, err := repo.OutgoingCommits("origin", zeroHash, pushed); err != nil || len(commits) != 0 {
+		t.Errorf("OutgoingCommits() for a deletion = %v, %v, want none", commits, err)
+	}
+}
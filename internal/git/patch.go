@@ -0,0 +1,380 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/binary"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const (
+	// diffContextLines is the number of unchanged lines shown around each
+	// change, the same as git diff
+	diffContextLines = 3
+
+	// binarySniffLen is how much of a file is checked for NUL bytes to tell
+	// whether it is binary, the same as git
+	binarySniffLen = 8000
+
+	// maxBlobSize is the largest text file whose content is read and
+	// diffed, bigger files are only noted with their size
+	maxBlobSize = 1 << 20
+)
+
+// binaryExtensions are file types treated as binary even when their start
+// happens to contain no NUL byte
+var binaryExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".ico": true, ".webp": true, ".bmp": true,
+	".pdf": true, ".zip": true, ".gz": true, ".tgz": true, ".tar": true, ".bz2": true, ".xz": true, ".7z": true,
+	".jar": true, ".class": true, ".pyc": true, ".exe": true, ".dll": true, ".so": true, ".dylib": true,
+	".o": true, ".a": true, ".wasm": true, ".woff": true, ".woff2": true, ".ttf": true, ".otf": true,
+	".mp3": true, ".mp4": true, ".mov": true, ".wav": true,
+}
+
+// diffFile is a file in a tree or the index
+type diffFile struct {
+	path string
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+func (f *diffFile) Hash() plumbing.Hash     { return f.hash }
+func (f *diffFile) Mode() filemode.FileMode { return f.mode }
+func (f *diffFile) Path() string            { return f.path }
+
+// filePatch is the change of a single file. from is nil for added files and
+// to is nil for deleted files; their paths differ for renames and copies.
+type filePatch struct {
+	from, to *diffFile
+	copied   bool
+	binary   bool
+	lfs      bool
+	large    bool
+	chunks   []fdiff.Chunk
+
+	// fromSize and toSize are the sizes of binary, LFS and large files in
+	// bytes
+	fromSize, toSize int64
+}
+
+func (p *filePatch) IsBinary() bool        { return p.binary }
+func (p *filePatch) Chunks() []fdiff.Chunk { return p.chunks }
+
+func (p *filePatch) Files() (fdiff.File, fdiff.File) {
+	// Typed nil pointers must not leak into the interfaces
+	var from, to fdiff.File
+	if p.from != nil {
+		from = p.from
+	}
+	if p.to != nil {
+		to = p.to
+	}
+	return from, to
+}
+
+// path returns the path the change is sorted by
+func (p *filePatch) path() string {
+	if p.to != nil {
+		return p.to.path
+	}
+	return p.from.path
+}
+
+// chunk is a run of equal, added or deleted lines
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c *chunk) Content() string       { return c.content }
+func (c *chunk) Type() fdiff.Operation { return c.op }
+
+// singlePatch wraps one file change for the unified encoder
+type singlePatch struct{ file *filePatch }
+
+func (p singlePatch) FilePatches() []fdiff.FilePatch { return []fdiff.FilePatch{p.file} }
+func (p singlePatch) Message() string                { return "" }
+
+// blob is the content of a file version. The content of binary files is
+// not kept. Git LFS pointers and files over maxBlobSize count as binary,
+// pointers with the size of the object they point to.
+type blob struct {
+	content string
+	binary  bool
+	lfs     bool
+	large   bool
+	size    int64
+}
+
+// differ compares sets of files, reading each blob at most once
+type differ struct {
+	repo  *Repository
+	blobs map[plumbing.Hash]*blob
+}
+
+func (r *Repository) newDiffer() *differ {
+	return &differ{repo: r, blobs: make(map[plumbing.Hash]*blob)}
+}
+
+// diff returns the unified diff turning the from files into the to files,
+// ordered by path, with renames and copies detected
+func (d *differ) diff(from, to map[string]*diffFile) (string, error) {
+	var (
+		patches        []*filePatch
+		added, deleted []*diffFile
+	)
+
+	for path, f := range from {
+		t, ok := to[path]
+		switch {
+		case !ok:
+			deleted = append(deleted, f)
+		case f.hash != t.hash || f.mode != t.mode:
+			patches = append(patches, &filePatch{from: f, to: t})
+		}
+	}
+	for path, t := range to {
+		if _, ok := from[path]; !ok {
+			added = append(added, t)
+		}
+	}
+
+	renames, err := d.detectRenames(from, added, deleted)
+	if err != nil {
+		return "", err
+	}
+	patches = append(patches, renames...)
+
+	sort.Slice(patches, func(i, j int) bool {
+		return patches[i].path() < patches[j].path()
+	})
+
+	var buf bytes.Buffer
+	encoder := fdiff.NewUnifiedEncoder(&buf, diffContextLines)
+	for _, patch := range patches {
+		if patch.copied {
+			// The encoder only knows renames
+			fmt.Fprintf(&buf, "diff --git a/%s b/%s\ncopy from %s\ncopy to %s\n",
+				patch.from.path, patch.to.path, patch.from.path, patch.to.path)
+			continue
+		}
+
+		if isSubmodule(patch.from) || isSubmodule(patch.to) {
+			d.repo.writeSubmodule(&buf, patch)
+			continue
+		}
+
+		if err := d.fillChunks(patch); err != nil {
+			return "", err
+		}
+		if patch.binary {
+			if err := writeBinary(&buf, patch); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if err := encoder.Encode(singlePatch{patch}); err != nil {
+			return "", fmt.Errorf("failed to format diff: %w", err)
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// fillChunks computes the line changes of patch
+func (d *differ) fillChunks(patch *filePatch) error {
+	if patch.from != nil && patch.to != nil && patch.from.hash == patch.to.hash {
+		return nil
+	}
+
+	oldBlob, err := d.read(patch.from)
+	if err != nil {
+		return err
+	}
+	newBlob, err := d.read(patch.to)
+	if err != nil {
+		return err
+	}
+
+	if oldBlob.binary || newBlob.binary {
+		patch.binary = true
+		patch.lfs = oldBlob.lfs || newBlob.lfs
+		patch.large = oldBlob.large || newBlob.large
+		patch.fromSize, patch.toSize = oldBlob.size, newBlob.size
+		return nil
+	}
+
+	for _, change := range diff.Do(oldBlob.content, newBlob.content) {
+		c := &chunk{content: change.Text}
+		switch change.Type {
+		case diffmatchpatch.DiffInsert:
+			c.op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			c.op = fdiff.Delete
+		default:
+			c.op = fdiff.Equal
+		}
+		patch.chunks = append(patch.chunks, c)
+	}
+
+	return nil
+}
+
+// read returns the content of f. A nil file has no content.
+func (d *differ) read(f *diffFile) (*blob, error) {
+	if f == nil {
+		return &blob{}, nil
+	}
+	if b, ok := d.blobs[f.hash]; ok {
+		return b, nil
+	}
+
+	obj, err := d.repo.repo.BlobObject(f.hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+	}
+
+	reader, err := obj.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+	}
+	defer reader.Close()
+
+	// Sniff the start of the file like git does, binary files aren't read
+	// any further
+	head := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+	}
+	head = head[:n]
+
+	b := &blob{size: obj.Size}
+	if size, ok := parseLFSPointer(head); ok && obj.Size == int64(n) {
+		b.binary, b.lfs, b.size = true, true, size
+		d.blobs[f.hash] = b
+		return b, nil
+	}
+	if isBinary, _ := binary.IsBinary(bytes.NewReader(head)); isBinary || binaryExtensions[strings.ToLower(path.Ext(f.path))] {
+		b.binary = true
+		d.blobs[f.hash] = b
+		return b, nil
+	}
+
+	if obj.Size > maxBlobSize {
+		b.binary, b.large = true, true
+		d.blobs[f.hash] = b
+		return b, nil
+	}
+
+	var content strings.Builder
+	content.Grow(int(obj.Size))
+	content.Write(head)
+	if _, err := io.Copy(&content, io.LimitReader(reader, obj.Size-int64(n))); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+	}
+	if int64(content.Len()) != obj.Size {
+		return nil, fmt.Errorf("failed to read %s: got %d of %d bytes", f.path, content.Len(), obj.Size)
+	}
+
+	b.content = content.String()
+	d.blobs[f.hash] = b
+	return b, nil
+}
+
+// writeBinary writes the header of a binary file change followed by a note
+// with its size, in place of its content
+func writeBinary(buf *bytes.Buffer, patch *filePatch) error {
+	var section bytes.Buffer
+	if err := fdiff.NewUnifiedEncoder(&section, diffContextLines).Encode(singlePatch{patch}); err != nil {
+		return fmt.Errorf("failed to format diff: %w", err)
+	}
+
+	// The encoder ends the header with "Binary files a/x and b/x differ"
+	header := strings.TrimSuffix(section.String(), "\n")
+	if i := strings.LastIndex(header, "\n"); i >= 0 {
+		header = header[:i]
+	}
+
+	buf.WriteString(header)
+	buf.WriteString("\n")
+	buf.WriteString(binaryNote(patch))
+	buf.WriteString("\n")
+	return nil
+}
+
+// binaryNote describes a binary, LFS or large file change, e.g. "Binary file
+// changed, 96KB -> 120KB"
+func binaryNote(patch *filePatch) string {
+	kind := "Binary file"
+	switch {
+	case patch.lfs:
+		kind = "LFS object"
+	case patch.large:
+		kind = "Large file"
+	}
+
+	switch {
+	case patch.from == nil:
+		return kind + " added, " + formatSize(patch.toSize)
+	case patch.to == nil:
+		return kind + " deleted, " + formatSize(patch.fromSize)
+	default:
+		return fmt.Sprintf("%s changed, %s -> %s", kind, formatSize(patch.fromSize), formatSize(patch.toSize))
+	}
+}
+
+// formatSize formats a size in bytes, e.g. "512B", "120KB" or "3.4MB"
+func formatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%dB", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%dKB", (size+512)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(size)/(1024*1024))
+	}
+}
+
+// treeFiles returns the files in tree by path
+func treeFiles(tree *object.Tree) (map[string]*diffFile, error) {
+	files := make(map[string]*diffFile)
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk tree: %w", err)
+		}
+		if entry.Mode == filemode.Dir {
+			continue
+		}
+		files[name] = &diffFile{path: name, hash: entry.Hash, mode: entry.Mode}
+	}
+
+	return files, nil
+}
+
+// commitFiles returns the files in the tree of commit by path
+func commitFiles(commit *object.Commit) (map[string]*diffFile, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of %s: %w", commit.Hash.String()[:7], err)
+	}
+	return treeFiles(tree)
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1024, "1KB"},
+		{120 * 1024, "120KB"},
+		{3565158, "3.4MB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestDiffSummarizesBinaryFiles(t *testing.T) {
+	repo := newTestRepo(t)
+	image := append([]byte("\x89PNG\r\n\x1a\n\x00"), bytes.Repeat([]byte{0xff}, 2048)...)
+	commitFile(t, repo, "logo.png", string(image), time.Now())
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	stage := func(name string, content []byte) {
+		if err := os.WriteFile(filepath.Join(repo.Root(), name), content, 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	stage("logo.png", append(image, bytes.Repeat([]byte{0xee}, 120*1024)...))
+	stage("app.wasm", []byte("no NUL bytes but still binary"))
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"diff --git a/app.wasm b/app.wasm\nnew file mode 100644\nindex ",
+		"\nBinary file added, 29B\n",
+		"\nBinary file changed, 2KB -> 122KB\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("GetStagedDiff() missing %q in:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "\xff") || strings.Contains(diff, "Binary files") {
+		t.Errorf("GetStagedDiff() should replace binary content with a note:\n%q", diff)
+	}
+}
+
+func TestDiffReadsWholeFiles(t *testing.T) {
+	repo := newTestRepo(t)
+
+	// Long enough to need many reads past the binary sniffing
+	var lines []string
+	for i := 0; i < 3000; i++ {
+		lines = append(lines, fmt.Sprintf("line %04d", i))
+	}
+	text := strings.Join(lines, "\n") + "\n"
+	commitFile(t, repo, "long.txt", text, time.Now())
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	stage := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(repo.Root(), name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	stage("long.txt", strings.Replace(text, "line 2999\n", "last line\n", 1))
+	stage("huge.txt", strings.Repeat("x", maxBlobSize+1))
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+
+	for _, want := range []string{
+		" line 2997\n line 2998\n-line 2999\n+last line\n",
+		"diff --git a/huge.txt b/huge.txt\nnew file mode 100644\nindex ",
+		"\nLarge file added, 1.0MB\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("GetStagedDiff() missing %q in:\n%.2000s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "xxxx") {
+		t.Error("GetStagedDiff() should not inline files over maxBlobSize")
+	}
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConflictError is returned when applying a commit stops on conflicts. The
+// conflicted files are left in the worktree to be resolved.
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return "conflicts in " + strings.Join(e.Files, ", ")
+}
+
+// CherryPick applies the changes of the commit rev points at to the index
+// and worktree without committing, like git cherry-pick --no-commit, and
+// returns the commit. go-git can't cherry-pick, so the git binary is
+// always used. On conflicts a *ConflictError is returned.
+func (r *Repository) CherryPick(rev string) (*CommitDetails, error) {
+	return r.applyCommit("cherry-pick", rev)
+}
+
+// Revert applies the inverse of the changes of the commit rev points at to
+// the index and worktree without committing, like git revert --no-commit,
+// and returns the commit. The git binary is always used. On conflicts a
+// *ConflictError is returned.
+func (r *Repository) Revert(rev string) (*CommitDetails, error) {
+	return r.applyCommit("revert", rev)
+}
+
+// applyCommit runs git cherry-pick or git revert (op) for a single non-merge
+// commit without committing
+func (r *Repository) applyCommit(op, rev string) (*CommitDetails, error) {
+	if err := requireGitBinary(op); err != nil {
+		return nil, err
+	}
+
+	commit, err := r.resolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	if commit.NumParents() > 1 {
+		return nil, fmt.Errorf("%s is a merge commit, %s one of the commits it merged instead", commit.Hash.String()[:7], op)
+	}
+
+	details, err := r.ShowCommit(commit.Hash.String())
+	if err != nil {
+		return nil, err
+	}
+
+	_, applyErr := r.runGit("", op, "--no-commit", commit.Hash.String())
+	if err := r.reopen(); err != nil {
+		return nil, err
+	}
+	if applyErr != nil {
+		if files, err := r.ConflictedFiles(); err == nil && len(files) > 0 {
+			return details, &ConflictError{Files: files}
+		}
+		return nil, fmt.Errorf("failed to %s %s: %w", op, details.Hash, applyErr)
+	}
+	return details, nil
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCherryPick(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	repo := newTestRepo(t)
+	gitRun(t, repo.Root(), "checkout", "-q", "-b", "feature")
+	commitFile(t, repo, "a.txt", "feature\n", time.Now().Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", time.Now().Add(2*time.Minute))
+	gitRun(t, repo.Root(), "checkout", "-q", "master")
+	commitFile(t, repo, "a.txt", "master\n", time.Now().Add(3*time.Minute))
+
+	details, err := repo.CherryPick("feature")
+	if err != nil {
+		t.Fatalf("CherryPick() error = %v", err)
+	}
+	if details.Message != "Update b.txt" || len(details.FullHash) != 40 || details.Email != "test@example.com" {
+		t.Errorf("CherryPick() = %+v, want the picked commit", details)
+	}
+	if staged, err := repo.GetStagedDiff(); err != nil || !strings.Contains(staged, "+++ b/b.txt") {
+		t.Errorf("GetStagedDiff() after CherryPick() = %q, %v, want b.txt staged", staged, err)
+	}
+
+	// The picked changes are committed with the original author
+	author := &Author{Name: "Original", Email: "original@example.com", When: details.When}
+	if _, err := repo.Commit("Add b", CommitOptions{Author: author}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	head, err := repo.ShowCommit("HEAD")
+	if err != nil {
+		t.Fatalf("ShowCommit() error = %v", err)
+	}
+	if head.Author != "Original" || head.Email != "original@example.com" || !head.When.Equal(details.When) {
+		t.Errorf("Commit() with an author = %s <%s> at %v, want the original author", head.Author, head.Email, head.When)
+	}
+
+	_, err = repo.CherryPick("feature~1")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != "a.txt" {
+		t.Errorf("CherryPick() of a conflicting commit error = %v, want a conflict in a.txt", err)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a\n", time.Now().Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", time.Now().Add(2*time.Minute))
+
+	details, err := repo.Revert("HEAD~1")
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if details.Message != "Update a.txt" {
+		t.Errorf("Revert() = %+v, want the reverted commit", details.CommitInfo)
+	}
+	if staged, err := repo.GetStagedDiff(); err != nil || !strings.Contains(staged, "-a") || strings.Contains(staged, "b.txt") {
+		t.Errorf("GetStagedDiff() after Revert() = %q, %v, want a.txt removed", staged, err)
+	}
+}
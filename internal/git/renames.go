@@ -0,0 +1,225 @@
+package git
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+const (
+	// renameThreshold is the similarity in percent from which a deleted and
+	// an added file count as a rename, the same as git's default
+	renameThreshold = 50
+
+	// renameLimit is the most added or deleted files compared by content,
+	// as every added file is compared with every deleted one
+	renameLimit = 200
+)
+
+// emptyBlob is the hash of an empty file. Empty files are never paired as
+// renames or copies, they say nothing about where a file came from.
+var emptyBlob = plumbing.ComputeHash(plumbing.BlobObject, nil)
+
+// detectRenames pairs added files with the deleted files they were renamed
+// from, exactly or by similar content, and with the source files they are
+// exact copies of. Added and deleted files without a match are returned as
+// plain additions and deletions.
+func (d *differ) detectRenames(source map[string]*diffFile, added, deleted []*diffFile) ([]*filePatch, error) {
+	sortFiles(added)
+	sortFiles(deleted)
+
+	var patches []*filePatch
+	matched := make(map[*diffFile]bool)
+	pair := func(from, to *diffFile, copied bool) {
+		patches = append(patches, &filePatch{from: from, to: to, copied: copied})
+		matched[from] = true
+		matched[to] = true
+	}
+
+	// Exact renames: same content under a new path
+	byHash := make(map[plumbing.Hash][]*diffFile)
+	for _, f := range deleted {
+		if renameCandidate(f) {
+			byHash[f.hash] = append(byHash[f.hash], f)
+		}
+	}
+	for _, to := range added {
+		if !renameCandidate(to) {
+			continue
+		}
+		if from := bestNameMatch(to, byHash[to.hash], matched); from != nil {
+			pair(from, to, false)
+		}
+	}
+
+	// Renames with changes: the most similar pairs first
+	added, deleted = unmatched(added, matched), unmatched(deleted, matched)
+	if len(added) > 0 && len(added) <= renameLimit && len(deleted) > 0 && len(deleted) <= renameLimit {
+		candidates, err := d.similarPairs(added, deleted)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range candidates {
+			if !matched[c.from] && !matched[c.to] {
+				pair(c.from, c.to, false)
+			}
+		}
+	}
+
+	// Copies: new files identical to a source file
+	sources := make(map[plumbing.Hash]*diffFile)
+	for _, f := range source {
+		if existing, ok := sources[f.hash]; !renameCandidate(f) || (ok && existing.path < f.path) {
+			continue
+		}
+		sources[f.hash] = f
+	}
+	for _, to := range unmatched(added, matched) {
+		if from, ok := sources[to.hash]; ok && renameCandidate(to) {
+			pair(from, to, true)
+		}
+	}
+
+	for _, f := range unmatched(added, matched) {
+		patches = append(patches, &filePatch{to: f})
+	}
+	for _, f := range unmatched(deleted, matched) {
+		patches = append(patches, &filePatch{from: f})
+	}
+
+	return patches, nil
+}
+
+// renamePair is a deleted and an added file with similar content
+type renamePair struct {
+	from, to *diffFile
+	score    int
+}
+
+// similarPairs returns the deleted and added text files at least
+// renameThreshold percent similar, most similar first
+func (d *differ) similarPairs(added, deleted []*diffFile) ([]renamePair, error) {
+	var pairs []renamePair
+	for _, to := range added {
+		if !renameCandidate(to) {
+			continue
+		}
+		toBlob, err := d.read(to)
+		if err != nil {
+			return nil, err
+		}
+		if toBlob.binary {
+			continue
+		}
+
+		for _, from := range deleted {
+			if !renameCandidate(from) {
+				continue
+			}
+			fromBlob, err := d.read(from)
+			if err != nil {
+				return nil, err
+			}
+			if fromBlob.binary {
+				continue
+			}
+
+			if score := similarity(fromBlob.content, toBlob.content); score >= renameThreshold {
+				pairs = append(pairs, renamePair{from: from, to: to, score: score})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].score != pairs[j].score {
+			return pairs[i].score > pairs[j].score
+		}
+		return nameScore(pairs[i].from, pairs[i].to) > nameScore(pairs[j].from, pairs[j].to)
+	})
+	return pairs, nil
+}
+
+// similarity returns how much of two texts is the same in percent: the
+// bytes of the lines they share relative to the larger text
+func similarity(a, b string) int {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	lines := make(map[string]int)
+	for _, line := range strings.SplitAfter(a, "\n") {
+		lines[line]++
+	}
+
+	common := 0
+	for _, line := range strings.SplitAfter(b, "\n") {
+		if lines[line] > 0 {
+			lines[line]--
+			common += len(line)
+		}
+	}
+
+	return common * 100 / max(len(a), len(b))
+}
+
+// bestNameMatch returns the unmatched candidate whose path is most like
+// the path of f
+func bestNameMatch(f *diffFile, candidates []*diffFile, matched map[*diffFile]bool) *diffFile {
+	var best *diffFile
+	for _, c := range candidates {
+		if matched[c] {
+			continue
+		}
+		if best == nil || nameScore(c, f) > nameScore(best, f) {
+			best = c
+		}
+	}
+	return best
+}
+
+// nameScore rates how alike two paths are, preferring the same file name
+// and then the same directory
+func nameScore(a, b *diffFile) int {
+	score := 0
+	if baseName(a.path) == baseName(b.path) {
+		score += 2
+	}
+	if dirName(a.path) == dirName(b.path) {
+		score++
+	}
+	return score
+}
+
+func baseName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func dirName(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// renameCandidate reports whether f may be paired as a rename or copy
+func renameCandidate(f *diffFile) bool {
+	return f.hash != emptyBlob && f.mode != filemode.Submodule
+}
+
+// unmatched returns the files not yet paired
+func unmatched(files []*diffFile, matched map[*diffFile]bool) []*diffFile {
+	var rest []*diffFile
+	for _, f := range files {
+		if !matched[f] {
+			rest = append(rest, f)
+		}
+	}
+	return rest
+}
+
+// sortFiles orders files by path so matching is deterministic
+func sortFiles(files []*diffFile) {
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+}
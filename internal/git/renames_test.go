@@ -0,0 +1,83 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"Identical", "a\nb\nc\n", "a\nb\nc\n", 100},
+		{"One line changed", "aaaa\nbbbb\ncccc\ndddd\n", "aaaa\nbbbb\ncccc\neeee\n", 75},
+		{"Nothing shared", "a\n", "b\n", 0},
+		{"Empty", "", "a\n", 0},
+		{"Relative to larger", "a\nb\n", "a\nb\nc\nd\n", 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := similarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("similarity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStagedDiffDetectsRenames(t *testing.T) {
+	repo := newTestRepo(t)
+	body := "line one\nline two\nline three\nline four\nline five\n"
+	commitFile(t, repo, "old.txt", body, time.Now())
+	commitFile(t, repo, "moved.txt", "moved content\n", time.Now())
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+
+	stage := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(repo.Root(), name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	// Renamed with a change, renamed as is and copied
+	if _, err := worktree.Remove("old.txt"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	stage("new.txt", strings.Replace(body, "line five", "line 5", 1))
+	if _, err := worktree.Move("moved.txt", "dir/moved.txt"); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	stage("copy.md", "hello\n")
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"diff --git a/README.md b/copy.md\ncopy from README.md\ncopy to copy.md\n",
+		"diff --git a/moved.txt b/dir/moved.txt\nrename from moved.txt\nrename to dir/moved.txt\n",
+		"diff --git a/old.txt b/new.txt\nrename from old.txt\nrename to new.txt\n",
+		"-line five\n+line 5\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("GetStagedDiff() missing %q in:\n%s", want, diff)
+		}
+	}
+	for _, unwanted := range []string{"deleted file mode", "new file mode", "+line one"} {
+		if strings.Contains(diff, unwanted) {
+			t.Errorf("GetStagedDiff() should not contain %q:\n%s", unwanted, diff)
+		}
+	}
+}
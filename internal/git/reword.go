@@ -0,0 +1,274 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// UnpushedCommits returns up to limit commits of HEAD that are on no branch
+// of the push remote yet, newest first, with their diffs. Only the
+// first-parent history is followed, and it ends before a merge commit, as
+// rewording across merges would need to rebase the merged branches too.
+func (r *Repository) UnpushedCommits(limit int) ([]CommitDetails, error) {
+	chain, err := r.firstParentChain(limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+
+	pushed, err := r.reachableFromRemote(chain[len(chain)-1].Committer.When)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []CommitDetails
+	for _, c := range chain {
+		if pushed[c.Hash] {
+			break
+		}
+		details, err := r.ShowCommit(c.Hash.String())
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, *details)
+	}
+	return commits, nil
+}
+
+// firstParentChain returns up to limit commits following the first parents
+// of HEAD, newest first, stopping before the first merge commit
+func (r *Repository) firstParentChain(limit int) ([]*object.Commit, error) {
+	c, err := r.resolveCommit("HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []*object.Commit
+	for len(chain) < limit && c.NumParents() <= 1 {
+		chain = append(chain, c)
+		if c.NumParents() == 0 {
+			break
+		}
+		if c, err = c.Parent(0); err != nil {
+			return nil, fmt.Errorf("failed to walk history: %w", err)
+		}
+	}
+	return chain, nil
+}
+
+// reachableFromRemote returns the commits reachable from the branches of
+// the push remote, down to those committed before since. Older commits
+// can't have newer descendants, barring clock skew.
+func (r *Repository) reachableFromRemote(since time.Time) (map[plumbing.Hash]bool, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	defer refs.Close()
+
+	prefix := "refs/remotes/" + r.remote + "/"
+	var queue []plumbing.Hash
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), prefix) {
+			queue = append(queue, ref.Hash())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		if seen[hash] {
+			continue
+		}
+		c, err := r.repo.CommitObject(hash)
+		if err != nil {
+			// e.g. missing from a shallow clone
+			continue
+		}
+		seen[hash] = true
+		if c.Committer.When.Before(since) {
+			continue
+		}
+		queue = append(queue, c.ParentHashes...)
+	}
+	return seen, nil
+}
+
+// Reword replaces the messages of commits on the first-parent history of
+// HEAD, keyed by their short hashes, like an interactive rebase rewording
+// them. The content of the commits is not changed, the commits after the
+// oldest reworded one are recreated. It returns the new short hash of HEAD.
+func (r *Repository) Reword(messages map[string]string) (string, error) {
+	if len(messages) == 0 {
+		return "", fmt.Errorf("no commits to reword")
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("HEAD is detached, check out a branch to reword its commits")
+	}
+
+	// Walk back to the oldest reworded commit, newest first
+	var chain []*object.Commit
+	found := 0
+	c, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	for {
+		if c.NumParents() > 1 {
+			return "", fmt.Errorf("can't reword across the merge commit %s", c.Hash.String()[:7])
+		}
+		chain = append(chain, c)
+		if _, ok := messages[c.Hash.String()[:7]]; ok {
+			if found++; found == len(messages) {
+				break
+			}
+		}
+		if c.NumParents() == 0 {
+			return "", fmt.Errorf("%d of the commits to reword are not in the history of HEAD", len(messages)-found)
+		}
+		if c, err = c.Parent(0); err != nil {
+			return "", fmt.Errorf("failed to walk history: %w", err)
+		}
+	}
+
+	if r.system {
+		return r.systemReword(chain, messages)
+	}
+
+	signer, err := r.commitSigner()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign commit: %w", err)
+	}
+	name, email := getAuthorInfo(r)
+	now := time.Now()
+
+	// Recreate the commits oldest first, each on top of the one before
+	oldest := chain[len(chain)-1]
+	parents := oldest.ParentHashes
+	var hash plumbing.Hash
+	for i := len(chain) - 1; i >= 0; i-- {
+		c := chain[i]
+		message, ok := messages[c.Hash.String()[:7]]
+		if !ok {
+			message = c.Message
+		}
+		commit := &object.Commit{
+			Author:       c.Author,
+			Committer:    object.Signature{Name: name, Email: email, When: now},
+			Message:      message,
+			TreeHash:     c.TreeHash,
+			ParentHashes: parents,
+			Encoding:     c.Encoding,
+		}
+		if signer != nil {
+			sig, err := signCommit(signer, commit)
+			if err != nil {
+				return "", fmt.Errorf("failed to sign commit: %w", err)
+			}
+			commit.PGPSignature = string(sig)
+		}
+
+		obj := r.repo.Storer.NewEncodedObject()
+		if err := commit.Encode(obj); err != nil {
+			return "", fmt.Errorf("failed to encode commit: %w", err)
+		}
+		if hash, err = r.repo.Storer.SetEncodedObject(obj); err != nil {
+			return "", fmt.Errorf("failed to write commit: %w", err)
+		}
+		parents = []plumbing.Hash{hash}
+	}
+
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), hash)); err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", head.Name().Short(), err)
+	}
+	return hash.String()[:7], nil
+}
+
+// signCommit signs a commit the way go-git signs new ones
+func signCommit(signer git.Signer, commit *object.Commit) ([]byte, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return nil, err
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(reader)
+}
+
+// systemReword rewords the commits of chain, newest first, with git rebase
+// -i, which honors hooks and signing settings. The todo list is prepared up
+// front: each reworded commit is picked and amended with its new message.
+func (r *Repository) systemReword(chain []*object.Commit, messages map[string]string) (string, error) {
+	dir, err := os.MkdirTemp("", "vibe-reword-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	var todo strings.Builder
+	for i := len(chain) - 1; i >= 0; i-- {
+		short := chain[i].Hash.String()[:7]
+		fmt.Fprintf(&todo, "pick %s\n", chain[i].Hash)
+		message, ok := messages[short]
+		if !ok {
+			continue
+		}
+		file := filepath.Join(dir, short)
+		if err := os.WriteFile(file, []byte(message), 0o600); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&todo, "exec git commit --amend --allow-empty --no-verify --cleanup=verbatim --file=%s\n", shellQuote(file))
+	}
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(todo.String()), 0o600); err != nil {
+		return "", err
+	}
+
+	onto := chain[len(chain)-1].ParentHashes
+	args := []string{"-c", "sequence.editor=cp " + shellQuote(todoFile), "rebase", "--interactive", "--autostash"}
+	if len(onto) == 0 {
+		args = append(args, "--root")
+	} else {
+		args = append(args, onto[0].String())
+	}
+
+	if _, err := r.runGit("", args...); err != nil {
+		_, _ = r.runGit("", "rebase", "--abort")
+		return "", fmt.Errorf("failed to reword commits: %w", err)
+	}
+	if err := r.reopen(); err != nil {
+		return "", err
+	}
+
+	hash, err := r.runGit("", "rev-parse", "--short=7", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hash), nil
+}
+
+// shellQuote quotes s for the POSIX shell git runs editors and exec lines
+// with
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestUnpushedCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a\n", time.Now().Add(time.Minute))
+	pushed := headHash(t, repo)
+	commitFile(t, repo, "b.txt", "b\n", time.Now().Add(2*time.Minute))
+	commitFile(t, repo, "c.txt", "c\n", time.Now().Add(3*time.Minute))
+
+	// Without a remote, every commit is unpushed
+	if commits, err := repo.UnpushedCommits(10); err != nil || len(commits) != 4 {
+		t.Fatalf("UnpushedCommits() without a remote = %d commits, %v, want 4", len(commits), err)
+	}
+
+	ref := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "feature"), pushed)
+	if err := repo.repo.Storer.SetReference(ref); err != nil {
+		t.Fatalf("SetReference() error = %v", err)
+	}
+
+	commits, err := repo.UnpushedCommits(10)
+	if err != nil {
+		t.Fatalf("UnpushedCommits() error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Message != "Update c.txt" || commits[1].Message != "Update b.txt" {
+		t.Fatalf("UnpushedCommits() = %+v, want the 2 commits after the pushed one", commits)
+	}
+	if commits[0].Diff == "" {
+		t.Error("UnpushedCommits() returned no diff")
+	}
+
+	if commits, err := repo.UnpushedCommits(1); err != nil || len(commits) != 1 {
+		t.Errorf("UnpushedCommits(1) = %d commits, %v, want 1", len(commits), err)
+	}
+}
+
+func TestReword(t *testing.T) {
+	backends := []bool{false}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, true)
+	}
+
+	for _, system := range backends {
+		if system {
+			// Keep the user's git config (signing, hooks) out of the test
+			t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+			t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+			t.Setenv("GIT_COMMITTER_NAME", "Test")
+			t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+		}
+
+		repo := newTestRepo(t)
+		repo.system = system
+		commitFile(t, repo, "a.txt", "a\n", time.Now().Add(-3*time.Minute))
+		base := headHash(t, repo)
+		commitFile(t, repo, "b.txt", "b\n", time.Now().Add(-2*time.Minute))
+		commitFile(t, repo, "c.txt", "c\n", time.Now().Add(-time.Minute))
+
+		commits, err := repo.UnpushedCommits(2)
+		if err != nil {
+			t.Fatalf("UnpushedCommits() error = %v", err)
+		}
+		before, err := repo.repo.CommitObject(headHash(t, repo))
+		if err != nil {
+			t.Fatalf("CommitObject() error = %v", err)
+		}
+
+		if _, err := repo.Reword(map[string]string{commits[1].Hash: "Add b\n\nWith a body"}); err != nil {
+			t.Fatalf("Reword() error = %v (system %v)", err, system)
+		}
+
+		head, err := repo.repo.CommitObject(headHash(t, repo))
+		if err != nil {
+			t.Fatalf("CommitObject() error = %v", err)
+		}
+		parent, err := head.Parent(0)
+		if err != nil {
+			t.Fatalf("Parent() error = %v", err)
+		}
+		if head.TreeHash != before.TreeHash || head.Message != before.Message {
+			t.Errorf("Reword() changed HEAD to %q, want only its hash to change (system %v)", head.Message, system)
+		}
+		if parent.Message != "Add b\n\nWith a body" && parent.Message != "Add b\n\nWith a body\n" {
+			t.Errorf("Reword() left %q, want the new message (system %v)", parent.Message, system)
+		}
+		if parent.ParentHashes[0] != base || parent.Author.Name != "Test" {
+			t.Errorf("Reword() moved the commit onto %s by %s, want it on %s (system %v)", parent.ParentHashes[0], parent.Author.Name, base, system)
+		}
+
+		if _, err := repo.Reword(map[string]string{"0000000": "Nope"}); err == nil {
+			t.Errorf("Reword() of an unknown commit succeeded, want an error (system %v)", system)
+		}
+	}
+}
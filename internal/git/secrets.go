@@ -0,0 +1,80 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// secretPatterns match credentials that should never be committed, by the
+// kind of secret they reveal
+var secretPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9_-]{32,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Stripe secret key", regexp.MustCompile(`\b(sk|rk)_live_[0-9A-Za-z]{24,}\b`)},
+}
+
+// RedactSecrets replaces the likely credentials in text, e.g. a prompt
+// about to be logged, with the kind of secret they are
+func RedactSecrets(text string) string {
+	for _, p := range secretPatterns {
+		text = p.pattern.ReplaceAllString(text, "[REDACTED "+p.kind+"]")
+	}
+	return text
+}
+
+// hunkHeader captures the first new line number of a hunk
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)`)
+
+// SecretFinding is a likely credential added by a diff
+type SecretFinding struct {
+	Path string
+	Line int
+	Kind string
+}
+
+// FindSecrets returns the likely credentials on the lines a unified diff
+// adds. Removed and unchanged lines are not reported.
+func FindSecrets(diff string) []SecretFinding {
+	var findings []SecretFinding
+	for _, section := range splitDiffSections(diff) {
+		filePath := sectionPath(section)
+		if filePath == "" {
+			continue
+		}
+
+		line := 0
+		inHunks := false
+		for _, text := range strings.Split(section, "\n") {
+			if m := hunkHeader.FindStringSubmatch(text); m != nil {
+				line, _ = strconv.Atoi(m[1])
+				inHunks = true
+				continue
+			}
+			if !inHunks {
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(text, "+"):
+				for _, p := range secretPatterns {
+					if p.pattern.MatchString(text) {
+						findings = append(findings, SecretFinding{Path: filePath, Line: line, Kind: p.kind})
+						break
+					}
+				}
+				line++
+			case strings.HasPrefix(text, " "):
+				line++
+			}
+		}
+	}
+	return findings
+}
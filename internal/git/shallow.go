@@ -0,0 +1,88 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// deepenSteps are the numbers of commits a shallow clone is deepened by in
+// turn while the merge base is missing, before fetching the full history
+var deepenSteps = []int{50, 500}
+
+// IsShallow reports whether the repository is a shallow clone, as made by
+// CI checkouts and git clone --depth
+func (r *Repository) IsShallow() (bool, error) {
+	shallow, err := r.repo.Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("failed to read shallow commits: %w", err)
+	}
+	return len(shallow) > 0, nil
+}
+
+// DeepenForBase makes sure a shallow clone has the history to find where
+// the current branch forked from base, fetching more history from the push
+// remote with the git binary as go-git can't deepen clones. Complete clones
+// are left alone.
+func (r *Repository) DeepenForBase(base string) error {
+	shallow, err := r.IsShallow()
+	if err != nil || !shallow || r.hasMergeBase(base) {
+		return err
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("shallow clone lacks the history to compare with %s, and deepening it needs the git binary, which was not found in PATH", base)
+	}
+
+	refSpec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", base, r.remote, base)
+	steps := make([][]string, 0, len(deepenSteps)+1)
+	for _, depth := range deepenSteps {
+		steps = append(steps, []string{"fetch", "--deepen=" + strconv.Itoa(depth), r.remote, refSpec})
+	}
+	steps = append(steps, []string{"fetch", "--unshallow", r.remote, refSpec})
+
+	for _, args := range steps {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = r.Root()
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to deepen shallow clone:\n%s", strings.TrimSpace(string(output)))
+		}
+
+		if err := r.reopen(); err != nil {
+			return err
+		}
+		if r.hasMergeBase(base) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no common history with %s, even after fetching the full history", base)
+}
+
+// hasMergeBase reports whether the current branch and base have a common
+// ancestor in the local history
+func (r *Repository) hasMergeBase(base string) bool {
+	headCommit, baseCommit, _, err := r.headAndBase(base)
+	if err != nil {
+		return false
+	}
+	_, err = mergeBase(headCommit, baseCommit)
+	return err == nil
+}
+
+// reopen reloads the repository after the git binary changed it, so no
+// stale shallow or object data is used
+func (r *Repository) reopen() error {
+	repo, err := git.PlainOpenWithOptions(r.Root(), &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	r.repo = repo
+	return nil
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestDeepenForBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	// Upstream: a feature branch forked 60 commits before the tip of master
+	upstream := newTestRepo(t)
+	start := time.Now().Add(-time.Hour)
+	commitFile(t, upstream, "base.txt", "fork point\n", start)
+	gitRun(t, upstream.Root(), "branch", "feature")
+	for i := 1; i <= 60; i++ {
+		commitFile(t, upstream, "base.txt", fmt.Sprintf("master %d\n", i), start.Add(time.Duration(i)*time.Second))
+	}
+	gitRun(t, upstream.Root(), "checkout", "-q", "feature")
+	commitFile(t, upstream, "feature.txt", "feature\n", start.Add(time.Minute))
+	gitRun(t, upstream.Root(), "checkout", "-q", "master")
+
+	dir := t.TempDir()
+	gitRun(t, dir, "clone", "-q", "--depth=1", "--no-single-branch", "--branch=feature", "file://"+upstream.Root(), "clone")
+
+	repo, err := Open(dir + "/clone")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if shallow, err := repo.IsShallow(); err != nil || !shallow {
+		t.Fatalf("IsShallow() = %v, %v, want true", shallow, err)
+	}
+	if _, err := repo.GetCommitsAhead("master"); err == nil {
+		t.Fatal("GetCommitsAhead() should fail before deepening")
+	}
+
+	if err := repo.DeepenForBase("master"); err != nil {
+		t.Fatalf("DeepenForBase() error = %v", err)
+	}
+
+	commits, err := repo.GetCommitsAhead("master")
+	if err != nil {
+		t.Fatalf("GetCommitsAhead() error = %v", err)
+	}
+	if len(commits) != 1 || commits[0].Message != "Update feature.txt" {
+		t.Errorf("GetCommitsAhead() = %+v, want the feature commit", commits)
+	}
+}
+
+// gitRun runs the git binary in dir
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
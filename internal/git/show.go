@@ -0,0 +1,74 @@
+package git
+
+import (
+	"fmt"
+	"time"
+)
+
+// emptyTree is the hash of the empty tree, which root commits are diffed
+// against
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// CommitDetails holds a commit with its author and the changes it made
+type CommitDetails struct {
+	CommitInfo
+
+	// FullHash is the full hash, Hash the short one
+	FullHash string
+
+	Author string
+	Email  string
+	When   time.Time
+
+	// Diff is the diff against the first parent, for merges the changes
+	// the merge brought in
+	Diff string
+}
+
+// ShowCommit returns the details of the commit rev points at, such as a
+// hash, tag or branch
+func (r *Repository) ShowCommit(rev string) (*CommitDetails, error) {
+	commit, err := r.resolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &CommitDetails{
+		CommitInfo: newCommitInfo(commit),
+		FullHash:   commit.Hash.String(),
+		Author:     commit.Author.Name,
+		Email:      commit.Author.Email,
+		When:       commit.Author.When,
+	}
+
+	if r.system {
+		from := emptyTree
+		if commit.NumParents() > 0 {
+			from = commit.ParentHashes[0].String()
+		}
+		if details.Diff, err = r.runGit("", append(systemDiffArgs, from, commit.Hash.String())...); err != nil {
+			return nil, err
+		}
+		return details, nil
+	}
+
+	parentFiles := map[string]*diffFile{}
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of %s: %w", details.Hash, err)
+		}
+		if parentFiles, err = commitFiles(parent); err != nil {
+			return nil, err
+		}
+	}
+	files, err := commitFiles(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	if details.Diff, err = r.newDiffer().diff(parentFiles, files); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShowCommit(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a\n", time.Now().Add(time.Minute))
+	hash := headHash(t, repo).String()
+
+	backends := []bool{false}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, true)
+	}
+	for _, system := range backends {
+		repo.system = system
+
+		for _, rev := range []string{"HEAD", hash, hash[:7]} {
+			details, err := repo.ShowCommit(rev)
+			if err != nil {
+				t.Fatalf("ShowCommit(%s) error = %v", rev, err)
+			}
+			if details.Message != "Update a.txt" || details.Author != "Test" {
+				t.Errorf("ShowCommit(%s) = %+v, want the latest commit", rev, details.CommitInfo)
+			}
+			if !strings.Contains(details.Diff, "+++ b/a.txt") || strings.Contains(details.Diff, "README.md") {
+				t.Errorf("ShowCommit(%s).Diff = %q, want only the change to a.txt", rev, details.Diff)
+			}
+		}
+
+		// The root commit is diffed against nothing
+		root, err := repo.ShowCommit("HEAD~1")
+		if err != nil {
+			t.Fatalf("ShowCommit(HEAD~1) error = %v", err)
+		}
+		if !strings.Contains(root.Diff, "+hello") {
+			t.Errorf("ShowCommit(HEAD~1).Diff = %q, want README.md added", root.Diff)
+		}
+	}
+
+	if _, err := repo.ShowCommit("nope"); err == nil {
+		t.Error("ShowCommit() with an unknown revision succeeded, want an error")
+	}
+}
@@ -0,0 +1,226 @@
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	// sshSigNamespace is the namespace git signs commits in, so a commit
+	// signature can't be passed off as a signature of anything else
+	sshSigNamespace = "git"
+
+	// sshSigHash is the hash of the signed message, the same as ssh-keygen
+	sshSigHash = "sha512"
+)
+
+// commitSigner returns the signer for new commits as configured with
+// commit.gpgsign, gpg.format and user.signingkey, or nil when commits
+// aren't signed. Only SSH signing is supported with go-git; GPG signing
+// needs the git backend.
+func (r *Repository) commitSigner() (git.Signer, error) {
+	if !parseBool(r.configValue("commit", "gpgsign")) || r.configValue("gpg", "format") != "ssh" {
+		return nil, nil
+	}
+
+	key := r.configValue("user", "signingkey")
+	if key == "" {
+		return nil, fmt.Errorf(`commit.gpgsign is set but user.signingkey is not
+
+Set the SSH key to sign with:
+  git config --global user.signingkey ~/.ssh/id_ed25519.pub`)
+	}
+
+	signer, err := sshSigningKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &sshSigner{signer: signer}, nil
+}
+
+// sshSigningKey resolves user.signingkey, a "key::" literal public key or
+// the path to a public or private key file, to a signer. Public keys and
+// encrypted private keys are looked up in the SSH agent.
+func sshSigningKey(key string) (ssh.Signer, error) {
+	if literal, ok := strings.CutPrefix(key, "key::"); ok {
+		public, _, _, _, err := ssh.ParseAuthorizedKey([]byte(literal))
+		if err != nil {
+			return nil, fmt.Errorf("invalid user.signingkey: %w", err)
+		}
+		return agentSigner(public)
+	}
+
+	path := key
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find home directory: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	if public, _, _, _, err := ssh.ParseAuthorizedKey(data); err == nil {
+		return agentSigner(public)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) && missing.PublicKey != nil {
+		// Passphrase protected keys must be unlocked in the agent
+		return agentSigner(missing.PublicKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+	return signer, nil
+}
+
+// agentSigner returns the signer of the SSH agent for public
+func agentSigner(public ssh.PublicKey) (ssh.Signer, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf(`the signing key is not available without an SSH agent
+
+Start an SSH agent and add your key:
+  eval "$(ssh-agent)"
+  ssh-add ~/.ssh/id_ed25519`)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SSH agent: %w", err)
+	}
+
+	signers, err := agent.NewClient(conn).Signers()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to list SSH agent keys: %w", err)
+	}
+
+	want := public.Marshal()
+	for _, s := range signers {
+		if bytes.Equal(s.PublicKey().Marshal(), want) {
+			// The connection stays open for signing, the process is short-lived
+			return s, nil
+		}
+	}
+	conn.Close()
+
+	return nil, fmt.Errorf(`the signing key is not loaded in the SSH agent
+
+Add it with:
+  ssh-add <private key>`)
+}
+
+// sshSigner signs commits in the SSHSIG format of ssh-keygen -Y sign, which
+// git writes for gpg.format=ssh
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, err
+	}
+
+	signed := ssh.Marshal(struct {
+		Namespace string
+		Reserved  string
+		Hash      string
+		Digest    []byte
+	}{sshSigNamespace, "", sshSigHash, h.Sum(nil)})
+
+	sig, err := signSSH(s.signer, append([]byte("SSHSIG"), signed...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	blob := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Version   uint32
+		PublicKey []byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Signature []byte
+	}{1, s.signer.PublicKey().Marshal(), sshSigNamespace, "", sshSigHash, ssh.Marshal(sig)})...)
+
+	return armorSSHSig(blob), nil
+}
+
+// signSSH signs data, with SHA-512 for RSA keys like ssh-keygen
+func signSSH(signer ssh.Signer, data []byte) (*ssh.Signature, error) {
+	if as, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		return as.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	}
+	return signer.Sign(rand.Reader, data)
+}
+
+// armorSSHSig wraps a signature blob in the PEM-like armor of ssh-keygen
+func armorSSHSig(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 70 {
+		buf.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	buf.WriteString(encoded + "\n")
+	buf.WriteString("-----END SSH SIGNATURE-----\n")
+	return buf.Bytes()
+}
+
+// configValue returns a setting from the repository's git config, falling
+// back to the global and system config like git does. Includes are not
+// followed.
+func (r *Repository) configValue(section, key string) string {
+	if cfg, err := r.repo.Config(); err == nil {
+		if value := rawValue(cfg, section, key); value != "" {
+			return value
+		}
+	}
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		if cfg, err := config.LoadConfig(scope); err == nil {
+			if value := rawValue(cfg, section, key); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func rawValue(cfg *config.Config, section, key string) string {
+	if cfg.Raw == nil || !cfg.Raw.HasSection(section) {
+		return ""
+	}
+	return cfg.Raw.Section(section).Option(key)
+}
+
+// parseBool parses a git config boolean
+func parseBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
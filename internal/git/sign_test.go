@@ -0,0 +1,133 @@
+package git
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestCommitSignsWithSSHKey(t *testing.T) {
+	repo := newTestRepo(t)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(private, "test")
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey() error = %v", err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := repo.repo.Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	cfg.Raw.Section("commit").SetOption("gpgsign", "true")
+	cfg.Raw.Section("gpg").SetOption("format", "ssh")
+	cfg.Raw.Section("user").SetOption("signingkey", keyFile)
+	if err := repo.repo.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	if _, err := repo.Commit("Signed", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	commit, err := repo.repo.CommitObject(headHash(t, repo))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	armored := commit.PGPSignature
+	if !strings.HasPrefix(armored, "-----BEGIN SSH SIGNATURE-----\n") {
+		t.Fatalf("commit signature = %q, want an SSH signature", armored)
+	}
+
+	// Check the SSHSIG blob against the signed commit
+	encoded := strings.TrimSuffix(strings.TrimPrefix(armored, "-----BEGIN SSH SIGNATURE-----\n"), "-----END SSH SIGNATURE-----\n")
+	blob, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\n", ""))
+	if err != nil || !bytes.HasPrefix(blob, []byte("SSHSIG")) {
+		t.Fatalf("signature blob = %q, %v", blob, err)
+	}
+	var sig struct {
+		Version   uint32
+		PublicKey []byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Signature []byte
+	}
+	if err := ssh.Unmarshal(blob[6:], &sig); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	public, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &signature); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	obj := repo.repo.Storer.NewEncodedObject()
+	if err := commit.EncodeWithoutSignature(obj); err != nil {
+		t.Fatalf("EncodeWithoutSignature() error = %v", err)
+	}
+	reader, err := obj.Reader()
+	if err != nil {
+		t.Fatalf("Reader() error = %v", err)
+	}
+	var payload bytes.Buffer
+	if _, err := payload.ReadFrom(reader); err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	digest := sha512.Sum512(payload.Bytes())
+
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace string
+		Reserved  string
+		Hash      string
+		Digest    []byte
+	}{"git", "", "sha512", digest[:]})...)
+	if err := public.Verify(signed, &signature); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+
+	// git itself accepts the signature when ssh-keygen is available
+	if _, err := exec.LookPath("ssh-keygen"); err == nil {
+		allowed := filepath.Join(t.TempDir(), "allowed_signers")
+		line := "test@example.com " + string(ssh.MarshalAuthorizedKey(public))
+		if err := os.WriteFile(allowed, []byte(line), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		out, err := exec.Command("git", "-C", repo.Root(), "-c", "gpg.ssh.allowedSignersFile="+allowed, "verify-commit", "HEAD").CombinedOutput()
+		if err != nil {
+			t.Errorf("git verify-commit error = %v: %s", err, out)
+		}
+	}
+}
+
+func TestSSHSigningKeyErrors(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	public := "key::ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGcK0Uw0Ztmj5wq6x0p0E4Ye6nxG1yT1uoh0QWZ1HY1x"
+	if _, err := sshSigningKey(public); err == nil || !strings.Contains(err.Error(), "SSH agent") {
+		t.Errorf("sshSigningKey(literal) without agent error = %v, want an SSH agent hint", err)
+	}
+	if _, err := sshSigningKey(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("sshSigningKey(missing file) succeeded, want an error")
+	}
+}
@@ -0,0 +1,120 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// StagedPaths returns the paths with staged changes, ordered by path. A
+// rename counts as the deletion of its old path and the addition of its
+// new one.
+func (r *Repository) StagedPaths() ([]string, error) {
+	changes, err := r.stagedChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(changes))
+	for path := range changes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// CommitFiles commits the staged changes of paths only and leaves the
+// staged changes of all other files staged. Unlike git commit --only, the
+// staged content is committed, not the worktree content.
+func (r *Repository) CommitFiles(paths []string, message string, opts CommitOptions) (string, error) {
+	changes, err := r.stagedChanges()
+	if err != nil {
+		return "", err
+	}
+
+	include := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		include[path] = true
+	}
+
+	// Set the other staged changes aside, they're put back after the commit
+	aside := make(map[string]*diffFile)
+	var unstage []string
+	for path, f := range changes {
+		if !include[path] {
+			aside[path] = f
+			unstage = append(unstage, path)
+		}
+	}
+	if len(aside) == len(changes) {
+		return "", fmt.Errorf("none of the files have staged changes")
+	}
+
+	if err := r.UnstageFiles(unstage); err != nil {
+		return "", err
+	}
+
+	hash, commitErr := r.Commit(message, opts)
+	if err := r.restageFiles(aside); err != nil {
+		return "", fmt.Errorf("failed to restore staged changes: %w", err)
+	}
+	return hash, commitErr
+}
+
+// stagedChanges returns the staged version of every path whose index
+// entry differs from HEAD, nil for staged deletions
+func (r *Repository) stagedChanges() (map[string]*diffFile, error) {
+	staged, err := r.stagedFiles()
+	if err != nil || staged == nil {
+		return map[string]*diffFile{}, err
+	}
+
+	changes := make(map[string]*diffFile)
+	for path, f := range staged.index {
+		if h, ok := staged.head[path]; !ok || h.hash != f.hash || h.mode != f.mode {
+			changes[path] = f
+		}
+	}
+	for path := range staged.head {
+		if _, ok := staged.index[path]; !ok {
+			changes[path] = nil
+		}
+	}
+	return changes, nil
+}
+
+// restageFiles puts the given staged versions back into the index,
+// removing the paths whose version is nil
+func (r *Repository) restageFiles(files map[string]*diffFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to get index: %w", err)
+	}
+
+	for path, f := range files {
+		entry, err := idx.Entry(path)
+		switch {
+		case f == nil:
+			if err == nil {
+				if _, err := idx.Remove(path); err != nil {
+					return fmt.Errorf("failed to stage %s: %w", path, err)
+				}
+			}
+		default:
+			if err != nil {
+				entry = idx.Add(path)
+			}
+			entry.Hash, entry.Mode = f.hash, f.mode
+			entry.Size, entry.ModifiedAt = 0, time.Time{}
+		}
+	}
+
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCommitFiles(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "gone.txt", "bye\n", time.Now())
+	root := repo.Root()
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	write("README.md", "staged\n")
+	write("new.txt", "new\n")
+	if err := os.Remove(filepath.Join(root, "gone.txt")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := repo.StageFiles([]string{"README.md", "new.txt", "gone.txt"}); err != nil {
+		t.Fatalf("StageFiles() error = %v", err)
+	}
+	// Only the staged content of README.md may be committed
+	write("README.md", "staged\nand more\n")
+
+	paths, err := repo.StagedPaths()
+	if err != nil {
+		t.Fatalf("StagedPaths() error = %v", err)
+	}
+	if want := []string{"README.md", "gone.txt", "new.txt"}; !slices.Equal(paths, want) {
+		t.Fatalf("StagedPaths() = %v, want %v", paths, want)
+	}
+
+	if _, err := repo.CommitFiles([]string{"README.md"}, "Update readme", CommitOptions{}); err != nil {
+		t.Fatalf("CommitFiles() error = %v", err)
+	}
+
+	commit, err := repo.repo.CommitObject(headHash(t, repo))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	files, err := commitFiles(commit)
+	if err != nil {
+		t.Fatalf("commitFiles() error = %v", err)
+	}
+	if _, ok := files["gone.txt"]; !ok {
+		t.Error("CommitFiles() committed the deletion of gone.txt")
+	}
+	if _, ok := files["new.txt"]; ok {
+		t.Error("CommitFiles() committed new.txt")
+	}
+	b, err := repo.newDiffer().read(files["README.md"])
+	if err != nil || b.content != "staged\n" {
+		t.Errorf("committed README.md = %q, %v, want the staged content", b.content, err)
+	}
+
+	// The other changes are still staged
+	changes, err := repo.ChangedFiles()
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	got := make(map[string]string)
+	for _, f := range changes {
+		got[f.Path] = f.Status
+	}
+	want := map[string]string{"README.md": " M", "gone.txt": "D ", "new.txt": "A "}
+	if !maps.Equal(got, want) {
+		t.Errorf("ChangedFiles() after CommitFiles() = %v, want %v", got, want)
+	}
+}
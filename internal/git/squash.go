@@ -0,0 +1,147 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// SquashPlan is a range of commits to squash into a single one
+type SquashPlan struct {
+	// Base and Head are the full hashes of the commit the squashed commit
+	// goes on top of and of the current HEAD
+	Base string
+	Head string
+
+	// Commits are the commits to squash, newest first
+	Commits []CommitInfo
+
+	// Diff is the combined diff of the commits
+	Diff string
+
+	// Pushed reports whether some of the commits are already on the
+	// branch's remote tracking branch, so squashing needs a force push
+	Pushed bool
+}
+
+// PlanSquash returns the commits of HEAD since base, a revision such as
+// HEAD~3 or a branch, and their combined diff. For a branch that moved on
+// since HEAD forked from it, the commits since the fork point are taken.
+func (r *Repository) PlanSquash(base string) (*SquashPlan, error) {
+	head, err := r.resolveCommit("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	baseCommit, err := r.resolveCommit(base)
+	if err != nil {
+		return nil, err
+	}
+	if baseCommit, err = mergeBase(head, baseCommit); err != nil {
+		return nil, err
+	}
+
+	commits, err := commitsBetween(head, baseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SquashPlan{
+		Base:    baseCommit.Hash.String(),
+		Head:    head.Hash.String(),
+		Commits: make([]CommitInfo, 0, len(commits)),
+		Pushed:  r.anyPushed(commits),
+	}
+	for _, c := range commits {
+		plan.Commits = append(plan.Commits, newCommitInfo(c))
+	}
+
+	if r.system {
+		plan.Diff, err = r.runGit("", append(systemDiffArgs, plan.Base, plan.Head)...)
+		if err != nil {
+			return nil, err
+		}
+		return plan, nil
+	}
+
+	baseFiles, err := commitFiles(baseCommit)
+	if err != nil {
+		return nil, err
+	}
+	headFiles, err := commitFiles(head)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Diff, err = r.newDiffer().diff(baseFiles, headFiles); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// anyPushed reports whether any of the commits is reachable from the
+// current branch's remote tracking branch
+func (r *Repository) anyPushed(commits []*object.Commit) bool {
+	branch, err := r.GetCurrentBranch()
+	if err != nil {
+		return false
+	}
+	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName(r.remote, branch), true)
+	if err != nil {
+		return false
+	}
+	remote, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return false
+	}
+	for _, c := range commits {
+		if pushed, err := c.IsAncestor(remote); err == nil && pushed {
+			return true
+		}
+	}
+	return false
+}
+
+// Squash replaces the commits of HEAD since base, a full hash from
+// PlanSquash, with a single commit of the same content, like git reset
+// --soft base followed by git commit. Staged changes would end up in the
+// new commit, so the caller makes sure there are none. When the commit
+// fails, HEAD is moved back to where it was.
+func (r *Repository) Squash(base, message string, opts CommitOptions) (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	if err := r.softReset(base); err != nil {
+		return "", err
+	}
+	hash, err := r.Commit(message, opts)
+	if err != nil {
+		if resetErr := r.softReset(head.Hash().String()); resetErr != nil {
+			return "", fmt.Errorf("%w (restoring HEAD failed too, run git reset --soft %s)", err, head.Hash().String()[:7])
+		}
+		return "", err
+	}
+	return hash, nil
+}
+
+// softReset moves the current branch to the commit hash, keeping the index
+// and worktree as they are
+func (r *Repository) softReset(hash string) error {
+	if r.system {
+		if _, err := r.runGit("", "reset", "--soft", hash); err != nil {
+			return fmt.Errorf("failed to reset to %s: %w", hash[:7], err)
+		}
+		return r.reopen()
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: plumbing.NewHash(hash), Mode: git.SoftReset}); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", hash[:7], err)
+	}
+	return nil
+}
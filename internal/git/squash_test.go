@@ -0,0 +1,105 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestPlanSquash(t *testing.T) {
+	repo := newTestRepo(t)
+	base := headHash(t, repo)
+	commitFile(t, repo, "a.txt", "a\n", time.Now().Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", time.Now().Add(2*time.Minute))
+	commitFile(t, repo, "a.txt", "a\nmore\n", time.Now().Add(3*time.Minute))
+
+	backends := []bool{false}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, true)
+	}
+	for _, system := range backends {
+		repo.system = system
+
+		plan, err := repo.PlanSquash("HEAD~3")
+		if err != nil {
+			t.Fatalf("PlanSquash() error = %v", err)
+		}
+		if plan.Base != base.String() || plan.Head != headHash(t, repo).String() {
+			t.Errorf("PlanSquash() base, head = %s, %s, want %s and HEAD", plan.Base, plan.Head, base)
+		}
+		if len(plan.Commits) != 3 || plan.Commits[0].Message != "Update a.txt" || plan.Commits[1].Message != "Update b.txt" {
+			t.Errorf("PlanSquash().Commits = %+v, want the 3 commits newest first", plan.Commits)
+		}
+		// The diff is combined, a.txt appears once with its final content
+		if strings.Count(plan.Diff, "+++ b/a.txt") != 1 || !strings.Contains(plan.Diff, "+more") || !strings.Contains(plan.Diff, "+++ b/b.txt") {
+			t.Errorf("PlanSquash().Diff = %q, want the combined changes (system %v)", plan.Diff, system)
+		}
+		if plan.Pushed {
+			t.Error("PlanSquash().Pushed = true without a remote, want false")
+		}
+	}
+
+	if _, err := repo.PlanSquash("HEAD~9"); err == nil {
+		t.Error("PlanSquash() past the first commit succeeded, want an error")
+	}
+}
+
+func TestSquash(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a\n", time.Now().Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", time.Now().Add(2*time.Minute))
+	before := headHash(t, repo)
+
+	plan, err := repo.PlanSquash("HEAD~2")
+	if err != nil {
+		t.Fatalf("PlanSquash() error = %v", err)
+	}
+
+	// A failing commit leaves the history as it was
+	if runtime.GOOS != "windows" {
+		hook := filepath.Join(repo.Root(), ".git", "hooks", "pre-commit")
+		if err := os.MkdirAll(filepath.Dir(hook), 0o755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := repo.Squash(plan.Base, "Blocked", CommitOptions{}); err == nil {
+			t.Fatal("Squash() with a failing hook succeeded, want an error")
+		}
+		if headHash(t, repo) != before {
+			t.Fatal("Squash() with a failing hook moved HEAD, want it restored")
+		}
+		if err := os.Remove(hook); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+	}
+
+	if _, err := repo.Squash(plan.Base, "Add a and b", CommitOptions{}); err != nil {
+		t.Fatalf("Squash() error = %v", err)
+	}
+
+	head, err := repo.repo.CommitObject(headHash(t, repo))
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if head.Message != "Add a and b" || head.ParentHashes[0] != plumbing.NewHash(plan.Base) {
+		t.Errorf("Squash() made %q on %s, want one commit on the base", head.Message, head.ParentHashes[0])
+	}
+	old, err := repo.repo.CommitObject(before)
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if head.TreeHash != old.TreeHash {
+		t.Error("Squash() changed the content, want the tree of the squashed commits")
+	}
+	if staged, err := repo.HasStagedChanges(); err != nil || staged {
+		t.Errorf("HasStagedChanges() after Squash() = %v, %v, want false", staged, err)
+	}
+}
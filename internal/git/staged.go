@@ -0,0 +1,166 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GetStagedDiff returns the unified diff of all staged changes, comparing
+// the HEAD tree with the index like git diff --cached
+func (r *Repository) GetStagedDiff() (string, error) {
+	if r.system {
+		return r.systemStagedDiff()
+	}
+
+	staged, err := r.stagedFiles()
+	if err != nil || staged == nil {
+		return "", err
+	}
+
+	return r.newDiffer().diff(staged.head, staged.index)
+}
+
+// HasStagedChanges reports whether the index differs from HEAD. Only the
+// HEAD tree and the index are compared, the worktree is never scanned.
+func (r *Repository) HasStagedChanges() (bool, error) {
+	if r.system {
+		return r.systemHasStagedChanges()
+	}
+
+	staged, err := r.stagedFiles()
+	if err != nil || staged == nil {
+		return false, err
+	}
+
+	if len(staged.head) != len(staged.index) {
+		return true, nil
+	}
+	for path, f := range staged.index {
+		h, ok := staged.head[path]
+		if !ok || h.hash != f.hash || h.mode != f.mode {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// stagedSides are the files of the HEAD tree and of the index by path
+type stagedSides struct {
+	head, index map[string]*diffFile
+}
+
+// stagedFiles returns the files of the HEAD tree and of the index, or nil
+// when the index is known to match HEAD
+func (r *Repository) stagedFiles() (*stagedSides, error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+
+	tree, err := r.headTree()
+	if err != nil {
+		return nil, err
+	}
+
+	if tree != nil && cachedTreeMatches(idx, tree.Hash) {
+		return nil, nil
+	}
+
+	sides := &stagedSides{head: map[string]*diffFile{}, index: indexFiles(idx)}
+	if tree != nil {
+		if sides.head, err = treeFiles(tree); err != nil {
+			return nil, err
+		}
+	}
+	return sides, nil
+}
+
+// cachedTreeMatches reports whether the tree git cached in the index for
+// the whole worktree is hash. git keeps the cache up to date and drops it
+// as soon as anything is staged, so a match means nothing is staged
+// without reading a single tree.
+func cachedTreeMatches(idx *index.Index, hash plumbing.Hash) bool {
+	if idx.Cache == nil || len(idx.Cache.Entries) == 0 {
+		return false
+	}
+
+	// The root comes first and covers every entry. go-git skips invalidated
+	// entries when reading the cache, so anything else means it's stale.
+	root := idx.Cache.Entries[0]
+	return root.Path == "" && root.Entries == len(idx.Entries) && root.Hash == hash
+}
+
+// indexFiles returns the files in the index by path
+func indexFiles(idx *index.Index) map[string]*diffFile {
+	files := make(map[string]*diffFile, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		// Unmerged entries (stages 1-3) are not staged changes
+		if entry.Stage != 0 {
+			continue
+		}
+		files[entry.Name] = &diffFile{path: entry.Name, hash: entry.Hash, mode: entry.Mode}
+	}
+	return files
+}
+
+// headTree returns the tree of HEAD, or nil before the first commit
+func (r *Repository) headTree() (*object.Tree, error) {
+	head, err := r.repo.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD tree: %w", err)
+	}
+	return tree, nil
+}
+
+// StageTracked stages every modified and deleted tracked file, like
+// git commit -a. Untracked files are left alone. It returns the staged
+// paths.
+func (r *Repository) StageTracked() ([]string, error) {
+	if r.system {
+		return r.systemStageTracked()
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var paths []string
+	for path, s := range status {
+		if s.Worktree == git.Modified || s.Worktree == git.Deleted {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		// Add stages deletions of missing files too
+		if _, err := worktree.Add(path); err != nil {
+			return nil, fmt.Errorf("failed to stage %s: %w", path, err)
+		}
+	}
+	return paths, nil
+}
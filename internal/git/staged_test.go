@@ -0,0 +1,179 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetStagedDiff(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "list.txt", "one\ntwo\nthree\nfour\nfive\nsix\nseven\n", time.Now())
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+
+	writeAndStage := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(repo.Root(), name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	writeAndStage("list.txt", "one\ntwo\nthree\n3.5\nfour\nfive\nsix\nseven\n")
+	writeAndStage("new.txt", "brand new\n")
+	if _, err := worktree.Remove("README.md"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"diff --git a/README.md b/README.md\ndeleted file mode 100644",
+		"@@ -1,6 +1,7 @@\n one\n two\n three\n+3.5\n four\n five\n six\n",
+		"diff --git a/new.txt b/new.txt\nnew file mode 100644",
+		"+brand new\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("GetStagedDiff() missing %q in:\n%s", want, diff)
+		}
+	}
+
+	// Files are ordered by path
+	if strings.Index(diff, "README.md") > strings.Index(diff, "list.txt") {
+		t.Errorf("GetStagedDiff() files out of order:\n%s", diff)
+	}
+}
+
+func TestHasStagedChanges(t *testing.T) {
+	repo := newTestRepo(t)
+	readme := filepath.Join(repo.Root(), "README.md")
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	stage := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(readme, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := worktree.Add("README.md"); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"Staged change", "changed\n", true},
+		{"Change reverted", "hello\n", false},
+	}
+
+	// Unstaged changes don't count
+	if err := os.WriteFile(readme, []byte("unstaged\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if staged, err := repo.HasStagedChanges(); err != nil || staged {
+		t.Fatalf("HasStagedChanges() with unstaged changes = %v, %v, want false", staged, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage(tt.content)
+			if staged, err := repo.HasStagedChanges(); err != nil || staged != tt.want {
+				t.Errorf("HasStagedChanges() = %v, %v, want %v", staged, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedTreeMatches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	repo := newTestRepo(t)
+	if err := os.Mkdir(filepath.Join(repo.Root(), "src"), 0o755); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+	commitFile(t, repo, "src/main.go", "package main\n", time.Now())
+	// git write-tree fills the index's tree cache like git commit does
+	gitRun(t, repo.Root(), "write-tree")
+
+	matches := func() bool {
+		t.Helper()
+		if err := repo.reopen(); err != nil {
+			t.Fatalf("reopen() error = %v", err)
+		}
+		idx, err := repo.repo.Storer.Index()
+		if err != nil {
+			t.Fatalf("Index() error = %v", err)
+		}
+		tree, err := repo.headTree()
+		if err != nil {
+			t.Fatalf("headTree() error = %v", err)
+		}
+		return cachedTreeMatches(idx, tree.Hash)
+	}
+
+	if !matches() {
+		t.Fatal("cachedTreeMatches() = false for a clean index")
+	}
+
+	if err := os.WriteFile(filepath.Join(repo.Root(), "src", "main.go"), []byte("package lib\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	gitRun(t, repo.Root(), "add", "src/main.go")
+
+	if matches() {
+		t.Error("cachedTreeMatches() = true with a staged change")
+	}
+	if staged, err := repo.HasStagedChanges(); err != nil || !staged {
+		t.Errorf("HasStagedChanges() = %v, %v, want true", staged, err)
+	}
+}
+
+func TestStageTracked(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "gone.txt", "bye\n", time.Now())
+
+	root := repo.Root()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "gone.txt")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "untracked.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	staged, err := repo.StageTracked()
+	if err != nil {
+		t.Fatalf("StageTracked() error = %v", err)
+	}
+	if want := []string{"README.md", "gone.txt"}; strings.Join(staged, ",") != strings.Join(want, ",") {
+		t.Errorf("StageTracked() = %v, want %v", staged, want)
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	if !strings.Contains(diff, "+changed") || !strings.Contains(diff, "deleted file mode") || strings.Contains(diff, "untracked.txt") {
+		t.Errorf("GetStagedDiff() after StageTracked() = %s", diff)
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// StashEntry is an entry of the stash list
+type StashEntry struct {
+	// Ref is the entry's name, e.g. "stash@{0}"
+	Ref string
+
+	// Message is the stash message, e.g. "On main: WIP: migrate auth"
+	Message string
+
+	// Age is when the entry was made, relative to now, e.g. "2 hours ago"
+	Age string
+}
+
+// Stash saves the staged and unstaged changes under message and reverts
+// them, like git stash push. With untracked, untracked files are stashed
+// too. go-git has no stash support, so the git binary is always used.
+func (r *Repository) Stash(message string, untracked bool) error {
+	if err := requireGitBinary("stash"); err != nil {
+		return err
+	}
+
+	args := []string{"stash", "push", "--quiet", "--message", message}
+	if untracked {
+		args = append(args, "--include-untracked")
+	}
+	if _, err := r.runGit("", args...); err != nil {
+		return fmt.Errorf("failed to stash changes: %w", err)
+	}
+	return r.reopen()
+}
+
+// StashList returns the stash entries, newest first
+func (r *Repository) StashList() ([]StashEntry, error) {
+	if err := requireGitBinary("stash"); err != nil {
+		return nil, err
+	}
+
+	output, err := r.runGit("", "stash", "list", "--format=%gd%x00%gs%x00%cr")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return parseStashList(output), nil
+}
+
+// parseStashList parses git stash list output with NUL-separated ref,
+// message and age fields
+func parseStashList(output string) []StashEntry {
+	var entries []StashEntry
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		entries = append(entries, StashEntry{Ref: fields[0], Message: fields[1], Age: fields[2]})
+	}
+	return entries
+}
+
+// requireGitBinary fails with an explanation when what needs the git
+// binary and it isn't installed
+func requireGitBinary(what string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("%s needs the git binary, which was not found in PATH", what)
+	}
+	return nil
+}
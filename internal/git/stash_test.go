@@ -0,0 +1,55 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestStash(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	repo := newTestRepo(t)
+	readme := filepath.Join(repo.Root(), "README.md")
+	if err := os.WriteFile(readme, []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	untracked := filepath.Join(repo.Root(), "notes.txt")
+	if err := os.WriteFile(untracked, []byte("notes\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := repo.Stash("WIP: rewrite the readme", false); err != nil {
+		t.Fatalf("Stash() error = %v", err)
+	}
+	if content, _ := os.ReadFile(readme); string(content) != "hello\n" {
+		t.Errorf("README.md after Stash() = %q, want the change reverted", content)
+	}
+	if _, err := os.Stat(untracked); err != nil {
+		t.Errorf("untracked file was stashed without untracked: %v", err)
+	}
+
+	if err := repo.Stash("WIP: take notes", true); err != nil {
+		t.Fatalf("Stash(untracked) error = %v", err)
+	}
+	if _, err := os.Stat(untracked); !os.IsNotExist(err) {
+		t.Errorf("untracked file still exists after Stash(untracked)")
+	}
+
+	entries, err := repo.StashList()
+	if err != nil {
+		t.Fatalf("StashList() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("StashList() = %+v, want 2 entries", entries)
+	}
+	if entries[0].Ref != "stash@{0}" || entries[0].Message != "On master: WIP: take notes" || entries[0].Age == "" {
+		t.Errorf("StashList()[0] = %+v, want the latest stash", entries[0])
+	}
+	if entries[1].Message != "On master: WIP: rewrite the readme" {
+		t.Errorf("StashList()[1] = %+v, want the first stash", entries[1])
+	}
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"path"
+	"strings"
+)
+
+// FileStat counts the lines a diff adds to and deletes from one file
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+
+	// SizeOnly is set for binary, LFS and large files, whose changes are
+	// only noted with their size
+	SizeOnly bool
+}
+
+// Changed returns the total number of changed lines
+func (s FileStat) Changed() int {
+	return s.Added + s.Deleted
+}
+
+// DiffStat counts the changed lines of each file in a unified diff, in the
+// order of the diff
+func DiffStat(diff string) []FileStat {
+	var stats []FileStat
+	for _, section := range splitDiffSections(diff) {
+		filePath := sectionPath(section)
+		if filePath == "" {
+			continue
+		}
+
+		stat := FileStat{Path: filePath}
+		inHunks := false
+		for _, line := range strings.Split(section, "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				inHunks = true
+			case !inHunks:
+				// Header lines, "---" and "+++" included
+				stat.SizeOnly = stat.SizeOnly || isSizeNote(line)
+			case strings.HasPrefix(line, "+"):
+				stat.Added++
+			case strings.HasPrefix(line, "-"):
+				stat.Deleted++
+			}
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// isSizeNote reports whether a header line stands in for file content that
+// isn't shown, as written by git and by binaryNote
+func isSizeNote(line string) bool {
+	for _, prefix := range []string{"Binary file", "LFS object", "Large file"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// testDirs are directories whose files are tests whatever their names
+var testDirs = []string{"test", "tests", "__tests__", "spec", "testdata"}
+
+// IsTestFile reports whether path looks like a test file by the usual
+// naming conventions, e.g. foo_test.go, foo.spec.ts, test_foo.py or any
+// file under a tests/ directory
+func IsTestFile(filePath string) bool {
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		for _, testDir := range testDirs {
+			if dir == testDir {
+				return true
+			}
+		}
+	}
+
+	name := path.Base(filePath)
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	switch {
+	case strings.HasSuffix(stem, "_test"), strings.HasSuffix(stem, "_spec"):
+		return true
+	case strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"):
+		return true
+	case ext == ".py" && strings.HasPrefix(stem, "test_"):
+		return true
+	case (ext == ".java" || ext == ".kt" || ext == ".cs") && (strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffStat(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
+-import "fmt"
++import (
++	"fmt"
++)
+diff --git a/logo.png b/logo.png
+index 3333333..4444444 100644
+Binary file changed, 2KB -> 3KB
+diff --git a/old.txt b/new.txt
+rename from old.txt
+rename to new.txt
+diff --git a/go.sum b/go.sum
+deleted file mode 100644
+index 5555555..0000000
+--- a/go.sum
++++ /dev/null
+@@ -1,2 +0,0 @@
+-a
+---b
+`
+
+	want := []FileStat{
+		{Path: "main.go", Added: 3, Deleted: 1},
+		{Path: "logo.png", SizeOnly: true},
+		{Path: "new.txt"},
+		{Path: "go.sum", Deleted: 2},
+	}
+	if got := DiffStat(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffStat() = %+v, want %+v", got, want)
+	}
+
+	if got := DiffStat(""); got != nil {
+		t.Errorf("DiffStat(\"\") = %+v, want nil", got)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"internal/git/stat_test.go", true},
+		{"internal/git/stat.go", false},
+		{"web/src/app.spec.ts", true},
+		{"web/src/app.test.jsx", true},
+		{"web/src/__tests__/app.js", true},
+		{"tests/conftest.py", true},
+		{"pkg/test_parser.py", true},
+		{"pkg/parser.py", false},
+		{"src/main/java/ParserTest.java", true},
+		{"spec/models/user_spec.rb", true},
+		{"docs/testing.md", false},
+		{"latest.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsTestFile(tt.path); got != tt.want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// maxSubmoduleLog is the most submodule commits listed for an update
+const maxSubmoduleLog = 20
+
+// isSubmodule reports whether f is a gitlink, the commit a submodule points to
+func isSubmodule(f *diffFile) bool {
+	return f != nil && f.mode == filemode.Submodule
+}
+
+// writeSubmodule writes a submodule change as a note on the commits it
+// moved between, as its content lives in another repository. When the
+// submodule is checked out, the subjects of the new commits are listed.
+func (r *Repository) writeSubmodule(buf *bytes.Buffer, patch *filePatch) {
+	fmt.Fprintf(buf, "diff --git a/%s b/%s\n", patch.path(), patch.path())
+
+	from, to := patch.from, patch.to
+	switch {
+	case !isSubmodule(from):
+		fmt.Fprintf(buf, "Submodule %s added at %s\n", to.path, shortHash(to.hash))
+	case !isSubmodule(to):
+		fmt.Fprintf(buf, "Submodule %s removed, was at %s\n", from.path, shortHash(from.hash))
+	default:
+		fmt.Fprintf(buf, "Submodule %s updated from %s to %s\n", to.path, shortHash(from.hash), shortHash(to.hash))
+		for _, subject := range r.submoduleLog(to.path, from.hash, to.hash) {
+			fmt.Fprintf(buf, "  > %s\n", subject)
+		}
+	}
+}
+
+// submoduleLog returns the subjects of the commits between from and to in
+// the submodule checked out at path, newest first. It returns nothing if
+// the submodule isn't checked out, lacks the commits or was rewound.
+func (r *Repository) submoduleLog(path string, from, to plumbing.Hash) []string {
+	sub, err := git.PlainOpen(filepath.Join(r.Root(), path))
+	if err != nil {
+		return nil
+	}
+
+	fromCommit, err := sub.CommitObject(from)
+	if err != nil {
+		return nil
+	}
+	toCommit, err := sub.CommitObject(to)
+	if err != nil {
+		return nil
+	}
+	if isAncestor, err := fromCommit.IsAncestor(toCommit); err != nil || !isAncestor {
+		return nil
+	}
+
+	commits, err := commitsBetween(toCommit, fromCommit)
+	if err != nil {
+		return nil
+	}
+
+	var subjects []string
+	for i, c := range commits {
+		if i == maxSubmoduleLog {
+			subjects = append(subjects, fmt.Sprintf("... and %d more", len(commits)-i))
+			break
+		}
+		subjects = append(subjects, strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0])
+	}
+	return subjects
+}
+
+// shortHash abbreviates a hash like git does
+func shortHash(hash plumbing.Hash) string {
+	return hash.String()[:7]
+}
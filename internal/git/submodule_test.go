@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestStagedDiffDescribesSubmodules(t *testing.T) {
+	repo := newTestRepo(t)
+
+	// A checked out submodule with two commits
+	sub := newTestRepo(t)
+	commitFile(t, sub, "lib.go", "package lib\n", time.Now())
+	first := headHash(t, sub)
+	commitFile(t, sub, "lib.go", "package lib\n\nfunc A() {}\n", time.Now())
+	second := headHash(t, sub)
+
+	stageGitlink := func(hash plumbing.Hash) {
+		t.Helper()
+		idx, err := repo.repo.Storer.Index()
+		if err != nil {
+			t.Fatalf("Index() error = %v", err)
+		}
+		idx.Remove("vendor/lib")
+		idx.Entries = append(idx.Entries, &index.Entry{Name: "vendor/lib", Hash: hash, Mode: filemode.Submodule})
+		if err := repo.repo.Storer.SetIndex(idx); err != nil {
+			t.Fatalf("SetIndex() error = %v", err)
+		}
+	}
+
+	stageGitlink(first)
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	if want := "Submodule vendor/lib added at " + first.String()[:7] + "\n"; !strings.Contains(diff, want) {
+		t.Errorf("GetStagedDiff() missing %q in:\n%s", want, diff)
+	}
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() error = %v", err)
+	}
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	if _, err := worktree.Commit("Add submodule", &git.CommitOptions{Author: sig}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	// Check the submodule out where the gitlink points
+	if err := os.MkdirAll(filepath.Join(repo.Root(), "vendor"), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.Rename(sub.Root(), filepath.Join(repo.Root(), "vendor", "lib")); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+
+	stageGitlink(second)
+	diff, err = repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	want := "diff --git a/vendor/lib b/vendor/lib\n" +
+		"Submodule vendor/lib updated from " + first.String()[:7] + " to " + second.String()[:7] + "\n" +
+		"  > Update lib.go\n"
+	if diff != want {
+		t.Errorf("GetStagedDiff() = %q, want %q", diff, want)
+	}
+}
+
+// headHash returns the commit HEAD points to
+func headHash(t *testing.T, repo *Repository) plumbing.Hash {
+	t.Helper()
+	head, err := repo.repo.Head()
+	if err != nil {
+		t.Fatalf("Head() error = %v", err)
+	}
+	return head.Hash()
+}
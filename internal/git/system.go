@@ -0,0 +1,247 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// BackendGoGit runs git operations in-process with go-git (the default)
+	BackendGoGit = "go-git"
+
+	// BackendSystem runs status, diff, commit, push and fetch with the
+	// installed git binary, which honors hooks, credential helpers and
+	// signing settings and is faster on huge repositories
+	BackendSystem = "git"
+)
+
+// Backends lists the valid backend names
+var Backends = []string{BackendGoGit, BackendSystem}
+
+// UseBackend selects the backend for status, diff, commit, push and fetch
+// by name, see BackendGoGit and BackendSystem. An empty name keeps go-git.
+func (r *Repository) UseBackend(name string) error {
+	switch name {
+	case "", BackendGoGit:
+		r.system = false
+	case BackendSystem:
+		if _, err := exec.LookPath("git"); err != nil {
+			return fmt.Errorf("the git backend needs the git binary, which was not found in PATH")
+		}
+		r.system = true
+	default:
+		return fmt.Errorf("unknown git backend %q (use %s)", name, strings.Join(Backends, ", "))
+	}
+	return nil
+}
+
+// runGit runs the git binary in the worktree root with stdin as input and
+// returns its output. Failures carry git's error message.
+func (r *Repository) runGit(stdin string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Root()
+	cmd.Stdin = strings.NewReader(stdin)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	slog.Debug("git", "args", args, "duration", time.Since(start), "error", err)
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return stdout.String(), &gitError{args: args, message: message, err: err}
+	}
+	return stdout.String(), nil
+}
+
+// gitError is a failed run of the git binary
+type gitError struct {
+	args    []string
+	message string
+	err     error
+}
+
+func (e *gitError) Error() string {
+	return fmt.Sprintf("git %s failed:\n%s", e.args[0], e.message)
+}
+
+func (e *gitError) Unwrap() error { return e.err }
+
+// IsGitError reports whether err comes from git itself: a failed git
+// command, a rejected hook, conflicts or a missing repository
+func IsGitError(err error) bool {
+	var (
+		runErr      *gitError
+		hookErr     *HookError
+		conflictErr *ConflictError
+	)
+	return errors.As(err, &runErr) || errors.As(err, &hookErr) ||
+		errors.As(err, &conflictErr) || errors.Is(err, git.ErrRepositoryNotExists)
+}
+
+// exitCode returns the exit code of a failed git run, or -1 if git didn't
+// run at all
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// systemDiffArgs are the options diffs are made with, matching the go-git
+// backend: renames, copies and submodule logs, and no external diff tools
+var systemDiffArgs = []string{"diff", "--no-color", "--no-ext-diff", "--find-renames", "--find-copies", "--submodule=log"}
+
+func (r *Repository) systemHasStagedChanges() (bool, error) {
+	_, err := r.runGit("", "diff", "--cached", "--quiet", "--ignore-submodules=dirty")
+	switch exitCode(err) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *Repository) systemStageTracked() ([]string, error) {
+	// List first, git add reports nothing
+	output, err := r.runGit("", "diff", "--name-only", "-z")
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+
+	if _, err := r.runGit("", "add", "--update"); err != nil {
+		return nil, fmt.Errorf("failed to stage tracked files: %w", err)
+	}
+	if err := r.reopen(); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(output, "\x00"), "\x00"), nil
+}
+
+func (r *Repository) systemStagedDiff() (string, error) {
+	return r.runGit("", append(systemDiffArgs, "--cached")...)
+}
+
+func (r *Repository) systemDiffFromBase(base string) (string, error) {
+	ref, err := r.baseRef(base)
+	if err != nil {
+		return "", err
+	}
+	// The three-dot form diffs against the merge base
+	return r.runGit("", append(systemDiffArgs, ref.Name().String()+"...HEAD")...)
+}
+
+func (r *Repository) systemCommit(message string, opts CommitOptions) (string, error) {
+	args := []string{"commit", "--file=-"}
+	if opts.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	if opts.Signoff {
+		args = append(args, "--signoff")
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if opts.Author != nil {
+		args = append(args, fmt.Sprintf("--author=%s <%s>", opts.Author.Name, opts.Author.Email), "--date="+opts.Author.When.Format(time.RFC3339))
+	}
+
+	// Hooks and commit signing run as configured
+	if _, err := r.runGit(message, args...); err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+	if err := r.reopen(); err != nil {
+		return "", err
+	}
+
+	hash, err := r.runGit("", "rev-parse", "--short=7", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hash), nil
+}
+
+func (r *Repository) systemPush(opts PushOptions) error {
+	branch, err := r.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"push", "--porcelain"}
+	// The lease is the remote-tracking branch, a branch that was never
+	// pushed needs no force
+	tracking := plumbing.NewRemoteReferenceName(r.remote, branch)
+	if _, err := r.repo.Reference(tracking, true); opts.ForceWithLease && err == nil {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, r.remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+
+	if _, err := r.runGit("", args...); err != nil {
+		if strings.Contains(err.Error(), "non-fast-forward") || strings.Contains(err.Error(), "stale info") {
+			if opts.ForceWithLease {
+				return fmt.Errorf(`the remote branch has commits you don't have locally
+
+Someone else pushed to '%s'. Fetch and integrate their changes first:
+  git pull --rebase %s %s`, branch, r.remote, branch)
+			}
+			return fmt.Errorf(`the remote branch has diverged from your local branch
+
+If you rebased or amended commits, push with --force-with-lease`)
+		}
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	if err := r.reopen(); err != nil {
+		return err
+	}
+	if err := r.setUpstream(branch); err != nil {
+		return err
+	}
+
+	if opts.AllTags || len(opts.Tags) > 0 {
+		return r.systemPushTags(opts.Tags)
+	}
+	return nil
+}
+
+func (r *Repository) systemPushTags(tags []string) error {
+	args := []string{"push", "--porcelain", r.remote}
+	if len(tags) == 0 {
+		args = append(args, "--tags")
+	}
+	for _, tag := range tags {
+		args = append(args, fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag))
+	}
+
+	if _, err := r.runGit("", args...); err != nil {
+		return fmt.Errorf("failed to push tags: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) systemFetchBranch(branch string) error {
+	refSpec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, r.remote, branch)
+	if _, err := r.runGit("", "fetch", "--quiet", r.remote, refSpec); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", branch, err)
+	}
+	return r.reopen()
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUseBackend(t *testing.T) {
+	repo := newTestRepo(t)
+
+	if err := repo.UseBackend("svn"); err == nil {
+		t.Error("UseBackend(svn) should fail")
+	}
+	if err := repo.UseBackend(""); err != nil || repo.system {
+		t.Errorf("UseBackend(\"\") = %v, system %v, want go-git", err, repo.system)
+	}
+}
+
+func TestSystemBackend(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+	// Keep the user's git config (signing, hooks) out of the test
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := newTestRepo(t)
+	if err := repo.UseBackend(BackendSystem); err != nil {
+		t.Fatalf("UseBackend() error = %v", err)
+	}
+
+	if staged, err := repo.HasStagedChanges(); err != nil || staged {
+		t.Fatalf("HasStagedChanges() = %v, %v, want false", staged, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo.Root(), "README.md"), []byte("hello\nworld\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	gitRun(t, repo.Root(), "add", "README.md")
+
+	if staged, err := repo.HasStagedChanges(); err != nil || !staged {
+		t.Fatalf("HasStagedChanges() = %v, %v, want true", staged, err)
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	if !strings.Contains(diff, "diff --git a/README.md b/README.md") || !strings.Contains(diff, "+world") {
+		t.Errorf("GetStagedDiff() = %q, want the README change", diff)
+	}
+
+	hash, err := repo.Commit("Add world\n\n# not a comment", CommitOptions{})
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	head := headHash(t, repo)
+	if !strings.HasPrefix(head.String(), hash) || len(hash) != 7 {
+		t.Errorf("Commit() = %q, want prefix of HEAD %s", hash, head)
+	}
+
+	commit, err := repo.repo.CommitObject(head)
+	if err != nil {
+		t.Fatalf("CommitObject() error = %v", err)
+	}
+	if commit.Message != "Add world\n\n# not a comment\n" {
+		t.Errorf("commit message = %q, want the message kept as written", commit.Message)
+	}
+}
+
+func TestIsGitError(t *testing.T) {
+	_, openErr := Open(t.TempDir())
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Git command", fmt.Errorf("failed to commit: %w", &gitError{args: []string{"commit"}}), true},
+		{"Hook", &HookError{Hook: "pre-commit", Err: errors.New("exit status 1")}, true},
+		{"Conflict", &ConflictError{Files: []string{"main.go"}}, true},
+		{"Not a repository", openErr, true},
+		{"Other", errors.New("OpenAI API error"), false},
+		{"Nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGitError(tt.err); got != tt.want {
+				t.Errorf("IsGitError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// maxTagCommits caps the commits listed for a first tag, which covers the
+// whole history
+const maxTagCommits = 200
+
+// PreviousTag returns the most recent tag reachable from HEAD, like git
+// describe --tags --abbrev=0, or "" if there is none
+func (r *Repository) PreviousTag() (string, error) {
+	tagged, err := r.taggedCommits()
+	if err != nil {
+		return "", err
+	}
+	if len(tagged) == 0 {
+		return "", nil
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	iter, err := r.repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return "", fmt.Errorf("failed to get log: %w", err)
+	}
+	defer iter.Close()
+
+	for {
+		c, err := iter.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to walk history: %w", err)
+		}
+		if names := tagged[c.Hash]; len(names) > 0 {
+			// Several tags on one commit: take the last in name order,
+			// usually the highest version
+			sort.Strings(names)
+			return names[len(names)-1], nil
+		}
+	}
+}
+
+// taggedCommits maps commits to the names of the tags pointing at them,
+// with annotated tags peeled to their commit
+func (r *Repository) taggedCommits() (map[plumbing.Hash][]string, error) {
+	refs, err := r.repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tagged := make(map[plumbing.Hash][]string)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		if tag, err := r.repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				// Tags of trees or blobs have no place in history
+				return nil
+			}
+			hash = commit.Hash
+		}
+		tagged[hash] = append(tagged[hash], ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tagged, nil
+}
+
+// CommitsSinceTag returns the commits reachable from HEAD but not from tag,
+// newest first. With an empty tag, the latest commits of the whole history
+// are returned.
+func (r *Repository) CommitsSinceTag(tag string) ([]CommitInfo, error) {
+	if tag == "" {
+		return r.RecentCommits(maxTagCommits)
+	}
+	return r.commitsInRange("refs/tags/"+tag, "HEAD")
+}
+
+// CommitsInRange returns the commits of a range such as "v1.2.0..v1.3.0",
+// newest first: those reachable from the end but not from the start, like
+// git log. The end defaults to HEAD, and a single revision means the
+// commits since it.
+func (r *Repository) CommitsInRange(spec string) ([]CommitInfo, error) {
+	from, to, ok := strings.Cut(spec, "..")
+	if ok {
+		// The symmetric form a...b lists the same commits here, only the
+		// side of b is of interest
+		to = strings.TrimPrefix(to, ".")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("invalid range %q, use <from>..<to>, e.g. v1.2.0..v1.3.0", spec)
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	return r.commitsInRange(from, to)
+}
+
+// commitsInRange returns the commits reachable from to but not from from,
+// newest first
+func (r *Repository) commitsInRange(from, to string) ([]CommitInfo, error) {
+	fromCommit, err := r.resolveCommit(from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := r.resolveCommit(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commits of from that to doesn't have don't count, only where to
+	// forked from it
+	base, err := mergeBase(toCommit, fromCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	since, err := commitsBetween(toCommit, base)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]CommitInfo, 0, len(since))
+	for _, c := range since {
+		commits = append(commits, newCommitInfo(c))
+	}
+	return commits, nil
+}
+
+// resolveCommit resolves a revision such as a tag, branch or hash to the
+// commit it points at
+func (r *Repository) resolveCommit(rev string) (*object.Commit, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(rev + "^{commit}"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %s: %w", strings.TrimPrefix(rev, "refs/tags/"), err)
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", rev, err)
+	}
+	return commit, nil
+}
+
+// TagExists reports whether a tag exists
+func (r *Repository) TagExists(name string) bool {
+	_, err := r.repo.Tag(name)
+	return err == nil
+}
+
+// CreateTag creates an annotated tag at HEAD with message, like git tag -a
+func (r *Repository) CreateTag(name, message string) error {
+	if err := plumbing.NewTagReferenceName(name).Validate(); err != nil {
+		return fmt.Errorf("invalid tag name %q: %w", name, err)
+	}
+	if r.TagExists(name) {
+		return fmt.Errorf("tag %s already exists", name)
+	}
+
+	if r.system {
+		// tag.gpgSign and other settings apply as configured
+		if _, err := r.runGit(message, "tag", "--annotate", "--file=-", name); err != nil {
+			return fmt.Errorf("failed to create tag %s: %w", name, err)
+		}
+		return r.reopen()
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	authorName, authorEmail := getAuthorInfo(r)
+	_, err = r.repo.CreateTag(name, head.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: authorName, Email: authorEmail, When: time.Now()},
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+	return nil
+}
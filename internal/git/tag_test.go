@@ -0,0 +1,108 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTags(t *testing.T) {
+	repo := newTestRepo(t)
+
+	if tag, err := repo.PreviousTag(); err != nil || tag != "" {
+		t.Errorf("PreviousTag() without tags = %q, %v, want none", tag, err)
+	}
+	if _, err := repo.repo.CreateTag("v1.0.0", headHash(t, repo), nil); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+
+	now := time.Now()
+	commitFile(t, repo, "a.txt", "a\n", now.Add(time.Minute))
+	commitFile(t, repo, "b.txt", "b\n", now.Add(2*time.Minute))
+
+	if tag, err := repo.PreviousTag(); err != nil || tag != "v1.0.0" {
+		t.Errorf("PreviousTag() = %q, %v, want v1.0.0", tag, err)
+	}
+	commits, err := repo.CommitsSinceTag("v1.0.0")
+	if err != nil {
+		t.Fatalf("CommitsSinceTag() error = %v", err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Message)
+	}
+	if want := "Update b.txt,Update a.txt"; strings.Join(subjects, ",") != want {
+		t.Errorf("CommitsSinceTag() = %v, want %s", subjects, want)
+	}
+
+	if err := repo.CreateTag("v1.1.0", "Release 1.1.0\n\n- Add a and b\n"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := repo.CreateTag("v1.1.0", "Again"); err == nil {
+		t.Error("CreateTag() with an existing name succeeded, want an error")
+	}
+
+	ref, err := repo.repo.Tag("v1.1.0")
+	if err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	tag, err := repo.repo.TagObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("created tag is not annotated: %v", err)
+	}
+	if tag.Message != "Release 1.1.0\n\n- Add a and b\n" || tag.Target != headHash(t, repo) {
+		t.Errorf("tag = %q on %s, want the message on HEAD", tag.Message, tag.Target)
+	}
+
+	// Annotated tags are found through their commit
+	if name, err := repo.PreviousTag(); err != nil || name != "v1.1.0" {
+		t.Errorf("PreviousTag() = %q, %v, want v1.1.0", name, err)
+	}
+	if commits, err := repo.CommitsSinceTag("v1.1.0"); err != nil || len(commits) != 0 {
+		t.Errorf("CommitsSinceTag(v1.1.0) = %v, %v, want none", commits, err)
+	}
+}
+
+func TestCommitsInRange(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+	commitFile(t, repo, "a.txt", "a\n", now.Add(time.Minute))
+	if _, err := repo.repo.CreateTag("v1.0.0", headHash(t, repo), nil); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	commitFile(t, repo, "b.txt", "b\n", now.Add(2*time.Minute))
+	if _, err := repo.repo.CreateTag("v1.1.0", headHash(t, repo), nil); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	commitFile(t, repo, "c.txt", "c\n", now.Add(3*time.Minute))
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "v1.0.0..v1.1.0", want: "Update b.txt"},
+		{spec: "v1.0.0..", want: "Update c.txt,Update b.txt"},
+		{spec: "v1.1.0", want: "Update c.txt"},
+		{spec: "v1.0.0...master", want: "Update c.txt,Update b.txt"},
+		{spec: "v1.1.0..v1.0.0", want: ""},
+		{spec: "..v1.1.0", wantErr: true},
+		{spec: "v9.9.9..v1.1.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			commits, err := repo.CommitsInRange(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CommitsInRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var subjects []string
+			for _, c := range commits {
+				subjects = append(subjects, c.Message)
+			}
+			if got := strings.Join(subjects, ","); got != tt.want {
+				t.Errorf("CommitsInRange() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// prTemplateLocations are the places GitHub looks for a pull request
+// template, relative to the repository root. Matching is case-insensitive.
+var prTemplateLocations = []string{
+	".github/pull_request_template.md",
+	"pull_request_template.md",
+	"docs/pull_request_template.md",
+}
+
+// prTemplateDirs hold multiple templates, the first one (by name) is used
+var prTemplateDirs = []string{
+	".github/PULL_REQUEST_TEMPLATE",
+	"PULL_REQUEST_TEMPLATE",
+	"docs/PULL_REQUEST_TEMPLATE",
+}
+
+// commitTemplateFile is the conventional name of a commit message
+// template, used from the repository root when commit.template isn't set
+const commitTemplateFile = ".gitmessage"
+
+// CommitTemplate returns the commit message template configured with
+// commit.template, or the repository's .gitmessage, if there is one
+func (r *Repository) CommitTemplate() (string, bool) {
+	path := r.configValue("commit", "template")
+	switch {
+	case path == "":
+		path = filepath.Join(r.Root(), commitTemplateFile)
+	case strings.HasPrefix(path, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		path = filepath.Join(home, path[2:])
+	case !filepath.IsAbs(path):
+		path = filepath.Join(r.Root(), path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(content)) == "" {
+		return "", false
+	}
+	return string(content), true
+}
+
+// PRTemplate returns the repository's pull request template, if it has one
+func (r *Repository) PRTemplate() (string, bool) {
+	root := r.Root()
+
+	for _, location := range prTemplateLocations {
+		if path, ok := findFileFold(root, location); ok {
+			if content, err := os.ReadFile(path); err == nil {
+				return string(content), true
+			}
+		}
+	}
+
+	for _, dir := range prTemplateDirs {
+		dirPath, ok := findFileFold(root, dir)
+		if !ok {
+			continue
+		}
+
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			continue
+		}
+
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+
+		if len(names) > 0 {
+			if content, err := os.ReadFile(filepath.Join(dirPath, names[0])); err == nil {
+				return string(content), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// findFileFold resolves a slash-separated path below root, matching each
+// path element case-insensitively
+func findFileFold(root, relPath string) (string, bool) {
+	current := root
+
+	for _, part := range strings.Split(relPath, "/") {
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return "", false
+		}
+
+		found := false
+		for _, entry := range entries {
+			if strings.EqualFold(entry.Name(), part) {
+				current = filepath.Join(current, entry.Name())
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", false
+		}
+	}
+
+	return current, true
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommitTemplate(t *testing.T) {
+	repo := newTestRepo(t)
+
+	if _, ok := repo.CommitTemplate(); ok {
+		t.Fatal("CommitTemplate() found a template in a repository without one")
+	}
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo.Root(), name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	write(".gitmessage", "Subject\n\nWhy:\n")
+	if got, ok := repo.CommitTemplate(); !ok || got != "Subject\n\nWhy:\n" {
+		t.Errorf("CommitTemplate() = %q, %v, want .gitmessage", got, ok)
+	}
+
+	// commit.template takes precedence, relative to the repository root
+	write("template.txt", "Why:\nHow:\n")
+	cfg, err := repo.repo.Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	cfg.Raw.Section("commit").SetOption("template", "template.txt")
+	if err := repo.repo.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if got, ok := repo.CommitTemplate(); !ok || got != "Why:\nHow:\n" {
+		t.Errorf("CommitTemplate() = %q, %v, want the configured template", got, ok)
+	}
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// trailerLine matches a git trailer such as "Signed-off-by: Name <email>"
+var trailerLine = regexp.MustCompile(`^[A-Za-z0-9-]+: `)
+
+// addSignoff appends trailer to message like git commit --signoff: to the
+// trailer block ending the message, or as a new paragraph. A message that
+// already ends with the same trailer is returned as is.
+func addSignoff(message, trailer string) string {
+	message = strings.TrimRight(message, "\n")
+
+	paragraphs := strings.Split(message, "\n\n")
+	last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	if last[len(last)-1] == trailer {
+		return message
+	}
+
+	if len(paragraphs) > 1 && isTrailerBlock(last) {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+// isTrailerBlock reports whether every line of a paragraph is a trailer
+func isTrailerBlock(lines []string) bool {
+	for _, line := range lines {
+		if !trailerLine.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package git
+
+import "testing"
+
+func TestAddSignoff(t *testing.T) {
+	const signoff = "Signed-off-by: Test <test@example.com>"
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "Subject only",
+			message: "Add feature\n",
+			want:    "Add feature\n\n" + signoff,
+		},
+		{
+			name:    "Joins an existing trailer block",
+			message: "Add feature\n\nLonger body.\n\nCo-authored-by: Other <other@example.com>",
+			want:    "Add feature\n\nLonger body.\n\nCo-authored-by: Other <other@example.com>\n" + signoff,
+		},
+		{
+			name:    "Subject that looks like a trailer",
+			message: "fix: handle empty input",
+			want:    "fix: handle empty input\n\n" + signoff,
+		},
+		{
+			name:    "Already signed off",
+			message: "Add feature\n\n" + signoff + "\n",
+			want:    "Add feature\n\n" + signoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addSignoff(tt.message, signoff); got != tt.want {
+				t.Errorf("addSignoff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// WorktreeDiff returns the changes that aren't staged, like git diff. With
+// untracked, a header is added for each untracked file so new files are
+// visible too.
+func (r *Repository) WorktreeDiff(untracked bool) (string, error) {
+	files, err := r.ChangedFiles()
+	if err != nil {
+		return "", err
+	}
+
+	var diff string
+	if r.system {
+		if diff, err = r.runGit("", systemDiffArgs...); err != nil {
+			return "", err
+		}
+	} else if diff, err = r.goGitWorktreeDiff(files); err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	buf.WriteString(diff)
+	for _, f := range files {
+		if untracked && f.Status == "??" {
+			fmt.Fprintf(&buf, "diff --git a/%s b/%s\nnew file (untracked)\n", f.Path, f.Path)
+		}
+	}
+	return buf.String(), nil
+}
+
+// goGitWorktreeDiff formats the unstaged changes of files from their hunks.
+// Deleted and binary files get a header only.
+func (r *Repository) goGitWorktreeDiff(files []ChangedFile) (string, error) {
+	hunks, err := r.UnstagedHunks()
+	if err != nil {
+		return "", err
+	}
+	byPath := make(map[string]*FileHunks, len(hunks))
+	for _, f := range hunks {
+		byPath[f.Path] = f
+	}
+
+	var buf strings.Builder
+	for _, f := range files {
+		switch f.Status[1] {
+		case 'D':
+			fmt.Fprintf(&buf, "diff --git a/%s b/%s\ndeleted file\n", f.Path, f.Path)
+		case 'M':
+			fh, ok := byPath[f.Path]
+			if !ok {
+				fmt.Fprintf(&buf, "diff --git a/%s b/%s\nBinary files a/%s and b/%s differ\n", f.Path, f.Path, f.Path, f.Path)
+				continue
+			}
+			fmt.Fprintf(&buf, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", f.Path, f.Path, f.Path, f.Path)
+			for _, h := range fh.Hunks {
+				buf.WriteString(h.String())
+				buf.WriteString("\n")
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+// BranchExists reports whether a local branch exists
+func (r *Repository) BranchExists(name string) bool {
+	_, err := r.repo.Reference(plumbing.NewBranchReferenceName(name), false)
+	return err == nil
+}
+
+// CreateBranch creates a branch at HEAD and switches to it, keeping staged
+// and unstaged changes, like git switch -c
+func (r *Repository) CreateBranch(name string) error {
+	ref := plumbing.NewBranchReferenceName(name)
+	if err := ref.Validate(); err != nil {
+		return fmt.Errorf("invalid branch name %q: %w", name, err)
+	}
+	if r.BranchExists(name) {
+		return fmt.Errorf("a branch named %q already exists", name)
+	}
+
+	if r.system {
+		if _, err := r.runGit("", "switch", "--quiet", "--create", name); err != nil {
+			return fmt.Errorf("failed to create branch %s: %w", name, err)
+		}
+		return r.reopen()
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: ref, Create: true, Keep: true}); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeDiff(t *testing.T) {
+	repo := newTestRepo(t)
+	root := repo.Root()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("hello\nworld\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "new.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	diff, err := repo.WorktreeDiff(true)
+	if err != nil {
+		t.Fatalf("WorktreeDiff() error = %v", err)
+	}
+	for _, want := range []string{"diff --git a/README.md b/README.md", "+world", "diff --git a/new.txt b/new.txt"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("WorktreeDiff() = %q, want it to contain %q", diff, want)
+		}
+	}
+}
+
+func TestCreateBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo.Root(), "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := repo.CreateBranch("feat/add..thing"); err == nil {
+		t.Error("CreateBranch() with an invalid name succeeded, want an error")
+	}
+	if err := repo.CreateBranch("feat/add-thing"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	branch, err := repo.GetCurrentBranch()
+	if err != nil || branch != "feat/add-thing" {
+		t.Errorf("GetCurrentBranch() = %q, %v, want feat/add-thing", branch, err)
+	}
+	content, _ := os.ReadFile(filepath.Join(repo.Root(), "README.md"))
+	if string(content) != "changed\n" {
+		t.Errorf("README.md = %q after switching, want the change kept", content)
+	}
+
+	if err := repo.CreateBranch("feat/add-thing"); err == nil {
+		t.Error("CreateBranch() with an existing name succeeded, want an error")
+	}
+}
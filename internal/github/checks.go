@@ -0,0 +1,132 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// CheckState is the outcome of a CI check
+type CheckState string
+
+const (
+	CheckPending CheckState = "pending"
+	CheckPassed  CheckState = "passed"
+	CheckFailed  CheckState = "failed"
+)
+
+// Check is a single CI check reported for a pull request, either a check
+// run (GitHub Actions, apps) or a commit status (external CI)
+type Check struct {
+	Name  string
+	State CheckState
+	URL   string
+}
+
+// ChecksSummary counts checks by state
+type ChecksSummary struct {
+	Pending int
+	Passed  int
+	Failed  int
+}
+
+// Summarize counts checks by state
+func Summarize(checks []Check) ChecksSummary {
+	var s ChecksSummary
+	for _, check := range checks {
+		switch check.State {
+		case CheckPassed:
+			s.Passed++
+		case CheckFailed:
+			s.Failed++
+		default:
+			s.Pending++
+		}
+	}
+	return s
+}
+
+// Done reports whether all checks have finished
+func (s ChecksSummary) Done() bool {
+	return s.Pending == 0
+}
+
+// String formats the summary, e.g. "3 passed, 1 failed, 2 pending"
+func (s ChecksSummary) String() string {
+	return fmt.Sprintf("%d passed, %d failed, %d pending", s.Passed, s.Failed, s.Pending)
+}
+
+// ListChecks returns the check runs and commit statuses reported for the
+// head commit of a pull request
+func (c *Client) ListChecks(owner, repo string, number int) ([]Check, error) {
+	pr, _, err := c.client.PullRequests.Get(c.ctx, owner, repo, number)
+	if err != nil {
+		return nil, formatGitHubError(err)
+	}
+	sha := pr.GetHead().GetSHA()
+
+	var checks []Check
+
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := c.client.Checks.ListCheckRunsForRef(c.ctx, owner, repo, sha, opts)
+		if err != nil {
+			return nil, formatGitHubError(err)
+		}
+
+		for _, run := range runs.CheckRuns {
+			checks = append(checks, Check{
+				Name:  run.GetName(),
+				State: checkRunState(run.GetStatus(), run.GetConclusion()),
+				URL:   run.GetHTMLURL(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	combined, _, err := c.client.Repositories.GetCombinedStatus(c.ctx, owner, repo, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, formatGitHubError(err)
+	}
+
+	for _, status := range combined.Statuses {
+		checks = append(checks, Check{
+			Name:  status.GetContext(),
+			State: commitStatusState(status.GetState()),
+			URL:   status.GetTargetURL(),
+		})
+	}
+
+	return checks, nil
+}
+
+// checkRunState maps a check run's status and conclusion to a CheckState
+func checkRunState(status, conclusion string) CheckState {
+	if status != "completed" {
+		return CheckPending
+	}
+
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return CheckPassed
+	default:
+		// failure, cancelled, timed_out, action_required, stale
+		return CheckFailed
+	}
+}
+
+// commitStatusState maps a commit status state to a CheckState
+func commitStatusState(state string) CheckState {
+	switch state {
+	case "success":
+		return CheckPassed
+	case "failure", "error":
+		return CheckFailed
+	default:
+		return CheckPending
+	}
+}
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestCheckRunState(t *testing.T) {
+	tests := []struct {
+		status     string
+		conclusion string
+		want       CheckState
+	}{
+		{status: "queued", want: CheckPending},
+		{status: "in_progress", want: CheckPending},
+		{status: "completed", conclusion: "success", want: CheckPassed},
+		{status: "completed", conclusion: "skipped", want: CheckPassed},
+		{status: "completed", conclusion: "neutral", want: CheckPassed},
+		{status: "completed", conclusion: "failure", want: CheckFailed},
+		{status: "completed", conclusion: "timed_out", want: CheckFailed},
+		{status: "completed", conclusion: "cancelled", want: CheckFailed},
+	}
+
+	for _, tt := range tests {
+		if got := checkRunState(tt.status, tt.conclusion); got != tt.want {
+			t.Errorf("checkRunState(%q, %q) = %q, want %q", tt.status, tt.conclusion, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	checks := []Check{
+		{Name: "build", State: CheckPassed},
+		{Name: "lint", State: CheckFailed},
+		{Name: "test", State: CheckPending},
+		{Name: "ci/jenkins", State: commitStatusState("error")},
+	}
+
+	got := Summarize(checks)
+	want := ChecksSummary{Pending: 1, Passed: 1, Failed: 2}
+	if got != want {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+	if got.Done() {
+		t.Error("Done() = true with pending checks")
+	}
+	if got.String() != "1 passed, 2 failed, 1 pending" {
+		t.Errorf("String() = %q", got.String())
+	}
+}
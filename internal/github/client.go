@@ -2,23 +2,41 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"regexp"
+	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v60/github"
 	"golang.org/x/oauth2"
+
+	"github.com/user/vibe/internal/auth"
 )
 
-// Client wraps the GitHub API client
+// Client wraps the GitHub API client and implements Forge
 type Client struct {
 	client *github.Client
 	ctx    context.Context
+	host   string
+
+	// limits tracks the API quota and retries rate-limited requests
+	limits *rateLimitTransport
+
+	// repos caches repository data fetched over GraphQL, keyed by
+	// "owner/name"
+	repos map[string]*repoData
 }
 
-// RepoInfo holds repository owner and name
+var (
+	_ Forge       = (*Client)(nil)
+	_ RateLimited = (*Client)(nil)
+)
+
+// RepoInfo holds repository host, owner and name
 type RepoInfo struct {
+	Host  string
 	Owner string
 	Name  string
 }
@@ -29,32 +47,168 @@ type PRResult struct {
 	URL    string
 }
 
-// NewClient creates a new GitHub client from environment variable
-func NewClient() (*Client, error) {
-	token := os.Getenv("GITHUB_TOKEN")
+// PullRequest holds the details of an existing pull request
+type PullRequest struct {
+	Number int
+	URL    string
+	Title  string
+	Body   string
+}
+
+// Issue holds the details of an issue
+type Issue struct {
+	Number int
+	URL    string
+	Title  string
+	State  string
+}
+
+// MergedPR is a pull request that was merged
+type MergedPR struct {
+	Number   int
+	URL      string
+	Title    string
+	Author   string
+	MergedAt time.Time
+}
+
+// ReviewComment is a comment on a line of a pull request's changes
+type ReviewComment struct {
+	Path string
+
+	// Line is the line in the new version of the file, which must be part
+	// of the pull request's diff
+	Line int
+
+	Body string
+}
+
+// Comparison holds the changes of a branch since it forked from its base,
+// as computed by the forge
+type Comparison struct {
+	// Commits are the commits on the branch, newest first
+	Commits []ComparedCommit
+
+	// Diff is the unified diff of the branch
+	Diff string
+}
+
+// ComparedCommit is a commit in a Comparison
+type ComparedCommit struct {
+	SHA     string
+	Message string
+}
+
+// NewClient creates a new GitHub client for host authenticated with token.
+// Hosts other than github.com are treated as GitHub Enterprise instances.
+func NewClient(host, token string) (*Client, error) {
 	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN environment variable is not set")
+		return nil, fmt.Errorf("no GitHub token available for %s", host)
 	}
 
-	ctx := context.Background()
+	// Rate limits are handled below the token transport, so retried
+	// requests are authenticated again
+	limits := newRateLimitTransport(nil)
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: limits})
+
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
 
+	client := github.NewClient(tc)
+	if host != "" && host != auth.DefaultGitHubHost {
+		baseURL := fmt.Sprintf("https://%s/api/v3/", host)
+		uploadURL := fmt.Sprintf("https://%s/api/uploads/", host)
+
+		var err error
+		client, err = client.WithEnterpriseURLs(baseURL, uploadURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub Enterprise host %s: %w", host, err)
+		}
+	}
+
+	if host == "" {
+		host = auth.DefaultGitHubHost
+	}
+
 	return &Client{
-		client: github.NewClient(tc),
+		client: client,
 		ctx:    ctx,
+		host:   host,
+		limits: limits,
+		repos:  make(map[string]*repoData),
 	}, nil
 }
 
-// CreatePR creates a new pull request
+// RateLimit returns the API quota reported by the last response, or nil
+// before the first request
+func (c *Client) RateLimit() *RateLimit {
+	return c.limits.RateLimit()
+}
+
+// ParseRemote extracts owner and repo from a remote URL on the client's host
+func (c *Client) ParseRemote(url string) (*RepoInfo, error) {
+	return ParseRemoteURL(url, c.host)
+}
+
+// CreatePR creates a new pull request. The repository ID comes from the
+// cached repository data, so usually only the mutation itself is sent.
 func (c *Client) CreatePR(owner, repo, base, head, title, body string) (*PRResult, error) {
-	pr, _, err := c.client.PullRequests.Create(c.ctx, owner, repo, &github.NewPullRequest{
+	data, err := c.repoData(owner, repo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		CreatePullRequest struct {
+			PullRequest struct {
+				Number int    `json:"number"`
+				URL    string `json:"url"`
+			} `json:"pullRequest"`
+		} `json:"createPullRequest"`
+	}
+
+	err = c.graphQL(`mutation($repo: ID!, $base: String!, $head: String!, $title: String!, $body: String!) {
+  createPullRequest(input: {repositoryId: $repo, baseRefName: $base, headRefName: $head, title: $title, body: $body}) {
+    pullRequest { number url }
+  }
+}`, map[string]interface{}{
+		"repo":  data.ID,
+		"base":  base,
+		"head":  head,
+		"title": title,
+		"body":  body,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PRResult{
+		Number: resp.CreatePullRequest.PullRequest.Number,
+		URL:    resp.CreatePullRequest.PullRequest.URL,
+	}, nil
+}
+
+// FindOpenPR returns the open pull request for head, or nil if none exists.
+// head is a branch name, or "owner:branch" for pull requests from forks.
+// The lookup is part of the batched repository data query.
+func (c *Client) FindOpenPR(owner, repo, head string) (*PullRequest, error) {
+	headOwner, branch := splitHead(owner, head)
+
+	data, err := c.repoData(owner, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOpenPR(data.OpenPRs, headOwner), nil
+}
+
+// UpdatePR replaces the title and description of an existing pull request
+func (c *Client) UpdatePR(owner, repo string, number int, title, body string) (*PRResult, error) {
+	pr, _, err := c.client.PullRequests.Edit(c.ctx, owner, repo, number, &github.PullRequest{
 		Title: github.String(title),
 		Body:  github.String(body),
-		Head:  github.String(head),
-		Base:  github.String(base),
 	})
 	if err != nil {
 		return nil, formatGitHubError(err)
@@ -66,14 +220,49 @@ func (c *Client) CreatePR(owner, repo, base, head, title, body string) (*PRResul
 	}, nil
 }
 
+// APIError is a failed GitHub API request, with a user-friendly message
+type APIError struct {
+	// Auth is set when the token was rejected or lacks permissions
+	Auth bool
+	err  error
+}
+
+func (e *APIError) Error() string { return e.err.Error() }
+func (e *APIError) Unwrap() error { return e.err }
+
 // formatGitHubError converts GitHub API errors into user-friendly messages
 func formatGitHubError(err error) error {
 	if err == nil {
 		return nil
 	}
+	auth := false
+	if ghErr, ok := err.(*github.ErrorResponse); ok {
+		code := ghErr.Response.StatusCode
+		auth = code == 401 || (code == 403 && !strings.Contains(err.Error(), "rate limit"))
+	}
+	return &APIError{Auth: auth, err: describeGitHubError(err)}
+}
 
+// describeGitHubError explains a GitHub API error and how to fix it
+func describeGitHubError(err error) error {
 	errStr := err.Error()
 
+	// Rate limits that could not be waited out
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return fmt.Errorf(`GitHub API rate limit exceeded
+
+The quota resets at %s. Check your rate limit at:
+  https://api.github.com/rate_limit`, rateErr.Rate.Reset.Local().Format("15:04"))
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return fmt.Errorf(`GitHub secondary rate limit exceeded
+
+Too many requests in a short time. Please wait a minute and try again.`)
+	}
+
 	// Check for GitHub error response
 	if ghErr, ok := err.(*github.ErrorResponse); ok {
 		switch ghErr.Response.StatusCode {
@@ -106,11 +295,8 @@ Please verify:
   3. The remote URL is correct`)
 
 		case 422:
-			if strings.Contains(errStr, "already exists") {
-				return fmt.Errorf("a pull request already exists for this branch")
-			}
-			if strings.Contains(errStr, "No commits between") {
-				return fmt.Errorf("no changes between the base branch and your branch - nothing to merge")
+			if err := validationError(errStr); err != nil {
+				return err
 			}
 			return fmt.Errorf("GitHub validation error: %s", ghErr.Message)
 		}
@@ -119,55 +305,489 @@ Please verify:
 	return fmt.Errorf("GitHub API error: %w", err)
 }
 
-// ParseRemoteURL extracts owner and repo from a git remote URL
-// Supports both HTTPS and SSH formats:
+// validationError returns a friendly error for well-known validation
+// failures in a REST or GraphQL error message, or nil
+func validationError(message string) error {
+	switch {
+	case strings.Contains(message, "already exists"):
+		return fmt.Errorf("a pull request already exists for this branch - run 'vibe pr --update' to refresh it")
+	case strings.Contains(message, "No commits between"):
+		return fmt.Errorf("no changes between the base branch and your branch - nothing to merge")
+	}
+	return nil
+}
+
+// ParseRemoteURL extracts host, owner and repo from a git remote URL.
+// github.com is always recognized, additional GitHub Enterprise hosts can be
+// passed in hosts.
+// Supports HTTPS, SSH and scp-style formats, with or without ".git", a
+// user name or a port:
 // - https://github.com/owner/repo.git
 // - git@github.com:owner/repo.git
-// - https://github.com/owner/repo
-// - git@github.com:owner/repo
-func ParseRemoteURL(url string) (*RepoInfo, error) {
+// - ssh://git@github.com/owner/repo.git
+// - ssh://git@ghe.company.com:2222/owner/repo
+func ParseRemoteURL(url string, hosts ...string) (*RepoInfo, error) {
+	info, err := parseRemote(url, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	// GitHub repositories are always exactly owner/name
+	if strings.Contains(info.Owner, "/") {
+		return nil, fmt.Errorf("could not parse GitHub remote URL: %s", strings.TrimSpace(url))
+	}
+	return info, nil
+}
+
+// ParseNestedRemoteURL is like ParseRemoteURL but accepts repositories
+// nested in groups, as used by GitLab. For
+// https://gitlab.com/group/subgroup/repo the owner is "group/subgroup".
+func ParseNestedRemoteURL(url string, hosts ...string) (*RepoInfo, error) {
+	return parseRemote(url, hosts)
+}
+
+// parseRemote splits url into host, owner and name, where the owner is
+// everything before the last path element. Only github.com and hosts are
+// accepted.
+func parseRemote(url string, hosts []string) (*RepoInfo, error) {
 	url = strings.TrimSpace(url)
 
-	// SSH format: git@github.com:owner/repo.git
-	sshPattern := regexp.MustCompile(`git@github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?$`)
-	if matches := sshPattern.FindStringSubmatch(url); len(matches) == 3 {
-		return &RepoInfo{
-			Owner: matches[1],
-			Name:  matches[2],
-		}, nil
+	host, repoPath, err := SplitRemoteURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse GitHub remote URL: %s", url)
 	}
 
-	// HTTPS format: https://github.com/owner/repo.git
-	httpsPattern := regexp.MustCompile(`https?://github\.com/([^/]+)/([^/]+?)(?:\.git)?$`)
-	if matches := httpsPattern.FindStringSubmatch(url); len(matches) == 3 {
-		return &RepoInfo{
-			Owner: matches[1],
-			Name:  matches[2],
-		}, nil
+	known := host == auth.DefaultGitHubHost
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			host, known = h, true
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("could not parse GitHub remote URL: %s", url)
 	}
 
-	return nil, fmt.Errorf("could not parse GitHub remote URL: %s", url)
+	idx := strings.LastIndex(repoPath, "/")
+	if idx <= 0 || idx == len(repoPath)-1 {
+		return nil, fmt.Errorf("could not parse GitHub remote URL: %s", url)
+	}
+
+	return &RepoInfo{
+		Host:  host,
+		Owner: repoPath[:idx],
+		Name:  repoPath[idx+1:],
+	}, nil
+}
+
+// CreateReview posts a review that only comments, neither approving nor
+// requesting changes, with body and a comment on each line in comments. It
+// returns the review's URL.
+func (c *Client) CreateReview(owner, repo string, number int, body string, comments []ReviewComment) (string, error) {
+	request := &github.PullRequestReviewRequest{
+		Body:  github.String(body),
+		Event: github.String("COMMENT"),
+	}
+	for _, comment := range comments {
+		request.Comments = append(request.Comments, &github.DraftReviewComment{
+			Path: github.String(comment.Path),
+			Line: github.Int(comment.Line),
+			Side: github.String("RIGHT"),
+			Body: github.String(comment.Body),
+		})
+	}
+
+	review, _, err := c.client.PullRequests.CreateReview(c.ctx, owner, repo, number, request)
+	if err != nil {
+		return "", formatGitHubError(err)
+	}
+	return review.GetHTMLURL(), nil
+}
+
+// GetIssue fetches an issue. Pull requests share their numbers with issues
+// but are rejected.
+func (c *Client) GetIssue(owner, repo string, number int) (*Issue, error) {
+	issue, _, err := c.client.Issues.Get(c.ctx, owner, repo, number)
+	if err != nil {
+		return nil, formatGitHubError(err)
+	}
+	if issue.IsPullRequest() {
+		return nil, fmt.Errorf("#%d in %s/%s is a pull request, not an issue", number, owner, repo)
+	}
+
+	return &Issue{
+		Number: issue.GetNumber(),
+		URL:    issue.GetHTMLURL(),
+		Title:  issue.GetTitle(),
+		State:  issue.GetState(),
+	}, nil
+}
+
+// MergedPRs lists the pull requests merged since the given time, newest
+// first. Closed pull requests are paged through by last update, which is
+// never before the merge, until they are older than since.
+func (c *Client) MergedPRs(owner, repo string, since time.Time) ([]MergedPR, error) {
+	opts := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var merged []MergedPR
+	for {
+		prs, resp, err := c.client.PullRequests.List(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, formatGitHubError(err)
+		}
+
+		for _, pr := range prs {
+			if pr.GetUpdatedAt().Before(since) {
+				return sortMergedPRs(merged), nil
+			}
+			if pr.MergedAt == nil || pr.GetMergedAt().Before(since) {
+				continue
+			}
+			merged = append(merged, MergedPR{
+				Number:   pr.GetNumber(),
+				URL:      pr.GetHTMLURL(),
+				Title:    pr.GetTitle(),
+				Author:   pr.GetUser().GetLogin(),
+				MergedAt: pr.GetMergedAt().Time,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			return sortMergedPRs(merged), nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// openPRCountQuery counts a repository's open pull requests
+const openPRCountQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: OPEN) { totalCount }
+  }
+}`
+
+// CountOpenPRs returns the number of open pull requests in the repository
+func (c *Client) CountOpenPRs(owner, repo string) (int, error) {
+	var resp struct {
+		Repository struct {
+			PullRequests struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"pullRequests"`
+		} `json:"repository"`
+	}
+
+	err := c.graphQL(openPRCountQuery, map[string]interface{}{
+		"owner": owner,
+		"name":  repo,
+	}, &resp)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Repository.PullRequests.TotalCount, nil
+}
+
+// sortMergedPRs orders pull requests by merge time, newest first
+func sortMergedPRs(prs []MergedPR) []MergedPR {
+	sort.SliceStable(prs, func(i, j int) bool {
+		return prs[i].MergedAt.After(prs[j].MergedAt)
+	})
+	return prs
+}
+
+// ForkParent returns the parent repository of a fork, or nil if the
+// repository is not a fork
+func (c *Client) ForkParent(owner, repo string) (*RepoInfo, error) {
+	data, err := c.repoData(owner, repo, "")
+	if err != nil {
+		return nil, err
+	}
+	return data.Parent, nil
 }
 
 // GetDefaultBranch fetches the default branch for a repository
 func (c *Client) GetDefaultBranch(owner, repo string) (string, error) {
-	repository, _, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	data, err := c.repoData(owner, repo, "")
 	if err != nil {
 		return "", fmt.Errorf("failed to get repository info: %w", err)
 	}
-
-	return repository.GetDefaultBranch(), nil
+	return data.DefaultBranch, nil
 }
 
 // BranchExists checks if a branch exists on the remote
 func (c *Client) BranchExists(owner, repo, branch string) (bool, error) {
-	_, _, err := c.client.Repositories.GetBranch(c.ctx, owner, repo, branch, 0)
+	_, resp, err := c.client.Repositories.GetBranch(c.ctx, owner, repo, branch, 0)
 	if err != nil {
-		// Check if it's a 404 (branch not found)
-		if _, ok := err.(*github.ErrorResponse); ok {
+		// GetBranch reports an error status as a plain error; rebuild the
+		// error response so that only a 404 means the branch is missing and
+		// anything else, such as a bad token, is reported as usual
+		var ghErr *github.ErrorResponse
+		if !errors.As(err, &ghErr) && resp != nil {
+			ghErr = &github.ErrorResponse{Response: resp.Response, Message: resp.Status}
+			err = ghErr
+		}
+		if ghErr != nil && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound {
 			return false, nil
 		}
-		return false, fmt.Errorf("failed to check branch: %w", err)
+		return false, formatGitHubError(err)
 	}
 	return true, nil
 }
+
+// Compare returns the commits and the diff of head since it forked from
+// base. GitHub lists at most 250 commits.
+func (c *Client) Compare(owner, repo, base, head string) (*Comparison, error) {
+	comparison, _, err := c.client.Repositories.CompareCommits(c.ctx, owner, repo, base, head, &github.ListOptions{PerPage: 250})
+	if err != nil {
+		return nil, formatGitHubError(err)
+	}
+
+	diff, _, err := c.client.Repositories.CompareCommitsRaw(c.ctx, owner, repo, base, head, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return nil, formatGitHubError(err)
+	}
+
+	result := &Comparison{Diff: diff}
+	// GitHub lists the commits oldest first
+	for i := len(comparison.Commits) - 1; i >= 0; i-- {
+		commit := comparison.Commits[i]
+		result.Commits = append(result.Commits, ComparedCommit{
+			SHA:     commit.GetSHA(),
+			Message: commit.GetCommit().GetMessage(),
+		})
+	}
+	return result, nil
+}
+
+// ListLabels returns the names of all labels defined in the repository.
+// The first page comes with the repository data, the rest is paged in only
+// for repositories with more than 100 labels.
+func (c *Client) ListLabels(owner, repo string) ([]string, error) {
+	data, err := c.repoData(owner, repo, "")
+	if err != nil {
+		return nil, err
+	}
+	if data.LabelsComplete {
+		return data.Labels, nil
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var names []string
+	for {
+		labels, resp, err := c.client.Issues.ListLabels(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, formatGitHubError(err)
+		}
+
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return names, nil
+}
+
+// AddLabels applies labels to a pull request
+func (c *Client) AddLabels(owner, repo string, number int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	// Pull requests share their number with the underlying issue
+	_, _, err := c.client.Issues.AddLabelsToIssue(c.ctx, owner, repo, number, labels)
+	if err != nil {
+		return formatGitHubError(err)
+	}
+	return nil
+}
+
+// RequestReviewers requests reviews on a pull request from users and teams.
+// Teams are given by slug, optionally prefixed with the org ("org/team").
+func (c *Client) RequestReviewers(owner, repo string, number int, users, teams []string) error {
+	if len(users) == 0 && len(teams) == 0 {
+		return nil
+	}
+
+	slugs := make([]string, 0, len(teams))
+	for _, team := range teams {
+		// The API only takes the slug, the org is implied by the repository
+		if idx := strings.LastIndex(team, "/"); idx != -1 {
+			team = team[idx+1:]
+		}
+		slugs = append(slugs, team)
+	}
+
+	_, _, err := c.client.PullRequests.RequestReviewers(c.ctx, owner, repo, number, github.ReviewersRequest{
+		Reviewers:     users,
+		TeamReviewers: slugs,
+	})
+	if err != nil {
+		return formatGitHubError(err)
+	}
+	return nil
+}
+
+// AddAssignees assigns users to a pull request. "@me" is resolved to the
+// authenticated user.
+func (c *Client) AddAssignees(owner, repo string, number int, users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		if user == "@me" {
+			me, _, err := c.client.Users.Get(c.ctx, "")
+			if err != nil {
+				return formatGitHubError(err)
+			}
+			user = me.GetLogin()
+		}
+		logins = append(logins, user)
+	}
+
+	_, _, err := c.client.Issues.AddAssignees(c.ctx, owner, repo, number, logins)
+	if err != nil {
+		return formatGitHubError(err)
+	}
+	return nil
+}
+
+// SetMilestone sets the milestone of a pull request, fuzzy matching name
+// against the repository's open milestones
+func (c *Client) SetMilestone(owner, repo string, number int, name string) (string, error) {
+	milestones, _, err := c.client.Issues.ListMilestones(c.ctx, owner, repo, &github.MilestoneListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return "", formatGitHubError(err)
+	}
+
+	numbers := make(map[string]int, len(milestones))
+	titles := make([]string, 0, len(milestones))
+	for _, m := range milestones {
+		numbers[m.GetTitle()] = m.GetNumber()
+		titles = append(titles, m.GetTitle())
+	}
+
+	title, err := fuzzyMatch("milestone", name, titles)
+	if err != nil {
+		return "", err
+	}
+
+	milestone := numbers[title]
+	_, _, err = c.client.Issues.Edit(c.ctx, owner, repo, number, &github.IssueRequest{
+		Milestone: &milestone,
+	})
+	if err != nil {
+		return "", formatGitHubError(err)
+	}
+
+	return title, nil
+}
+
+// AddToProject adds a pull request to a (v2) project linked to the
+// repository, fuzzy matching name against the open projects' titles
+func (c *Client) AddToProject(owner, repo string, number int, name string) (string, error) {
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				ID string `json:"id"`
+			} `json:"pullRequest"`
+			ProjectsV2 struct {
+				Nodes []struct {
+					ID     string `json:"id"`
+					Title  string `json:"title"`
+					Closed bool   `json:"closed"`
+				} `json:"nodes"`
+			} `json:"projectsV2"`
+		} `json:"repository"`
+	}
+
+	err := c.graphQL(`query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) { id }
+    projectsV2(first: 100) { nodes { id title closed } }
+  }
+}`, map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"number": number,
+	}, &data)
+	if err != nil {
+		return "", err
+	}
+
+	ids := make(map[string]string)
+	var titles []string
+	for _, p := range data.Repository.ProjectsV2.Nodes {
+		if p.Closed {
+			continue
+		}
+		ids[p.Title] = p.ID
+		titles = append(titles, p.Title)
+	}
+
+	title, err := fuzzyMatch("project", name, titles)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.graphQL(`mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`, map[string]interface{}{
+		"project": ids[title],
+		"content": data.Repository.PullRequest.ID,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return title, nil
+}
+
+// EnableAutoMerge enables auto-merge on a pull request with the given merge
+// method. The repository must allow auto-merge and the method.
+func (c *Client) EnableAutoMerge(owner, repo string, number int, method string) error {
+	if !ValidMergeMethod(method) {
+		return fmt.Errorf("invalid merge method %q (use %s)", method, strings.Join(MergeMethods, ", "))
+	}
+
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				ID string `json:"id"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+
+	err := c.graphQL(`query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) { id }
+  }
+}`, map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"number": number,
+	}, &data)
+	if err != nil {
+		return err
+	}
+
+	return c.graphQL(`mutation($pr: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $pr, mergeMethod: $method}) { clientMutationId }
+}`, map[string]interface{}{
+		"pr":     data.Repository.PullRequest.ID,
+		"method": strings.ToUpper(method),
+	}, nil)
+}
@@ -1,7 +1,15 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+
+	"github.com/google/go-github/v60/github"
 )
 
 func TestParseRemoteURL(t *testing.T) {
@@ -46,6 +54,26 @@ func TestParseRemoteURL(t *testing.T) {
 			url:  "https://github.com/my_org/my_repo.git",
 			want: &RepoInfo{Owner: "my_org", Name: "my_repo"},
 		},
+		{
+			name: "ssh:// URL",
+			url:  "ssh://git@github.com/owner/repo.git",
+			want: &RepoInfo{Owner: "owner", Name: "repo"},
+		},
+		{
+			name: "ssh:// URL with port",
+			url:  "ssh://git@github.com:22/owner/repo.git",
+			want: &RepoInfo{Owner: "owner", Name: "repo"},
+		},
+		{
+			name: "HTTPS URL with user and trailing slash",
+			url:  "https://user@github.com/owner/repo/",
+			want: &RepoInfo{Owner: "owner", Name: "repo"},
+		},
+		{
+			name:    "Invalid URL - nested path",
+			url:     "https://github.com/group/subgroup/repo.git",
+			wantErr: true,
+		},
 		{
 			name:    "Invalid URL - not GitHub",
 			url:     "https://gitlab.com/owner/repo.git",
@@ -94,3 +122,154 @@ func TestParseRemoteURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRemoteURLWithEnterpriseHosts(t *testing.T) {
+	hosts := []string{"ghe.company.com"}
+
+	tests := []struct {
+		name    string
+		url     string
+		want    *RepoInfo
+		wantErr bool
+	}{
+		{
+			name: "github.com still recognized",
+			url:  "git@github.com:owner/repo.git",
+			want: &RepoInfo{Host: "github.com", Owner: "owner", Name: "repo"},
+		},
+		{
+			name: "Enterprise SSH URL",
+			url:  "git@ghe.company.com:team/service.git",
+			want: &RepoInfo{Host: "ghe.company.com", Owner: "team", Name: "service"},
+		},
+		{
+			name: "Enterprise HTTPS URL",
+			url:  "https://ghe.company.com/team/service",
+			want: &RepoInfo{Host: "ghe.company.com", Owner: "team", Name: "service"},
+		},
+		{
+			name: "Enterprise SSH URL with custom port",
+			url:  "ssh://git@ghe.company.com:2222/team/service.git",
+			want: &RepoInfo{Host: "ghe.company.com", Owner: "team", Name: "service"},
+		},
+		{
+			name:    "Unconfigured host",
+			url:     "https://ghe.other.com/team/service",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRemoteURL(tt.url, hosts...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRemoteURL() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ParseRemoteURL() unexpected error: %v", err)
+				return
+			}
+
+			if *got != *tt.want {
+				t.Errorf("ParseRemoteURL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNestedRemoteURL(t *testing.T) {
+	got, err := ParseNestedRemoteURL("git@gitlab.example.com:group/subgroup/repo.git", "gitlab.example.com")
+	if err != nil {
+		t.Fatalf("ParseNestedRemoteURL() unexpected error: %v", err)
+	}
+
+	want := RepoInfo{Host: "gitlab.example.com", Owner: "group/subgroup", Name: "repo"}
+	if *got != want {
+		t.Errorf("ParseNestedRemoteURL() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormatGitHubError(t *testing.T) {
+	response := func(code int, message string) error {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/owner/repo", nil)
+		return &github.ErrorResponse{
+			Response: &http.Response{StatusCode: code, Request: req},
+			Message:  message,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantAuth bool
+		wantMsg  string
+	}{
+		{"Bad credentials", response(401, "Bad credentials"), true, "authentication failed"},
+		{"Missing scope", response(403, "Resource not accessible"), true, "access denied"},
+		{"Rate limit", response(403, "API rate limit exceeded"), false, "rate limit exceeded"},
+		{"Not found", response(404, "Not Found"), false, "repository not found"},
+		{"Other", errors.New("boom"), false, "GitHub API error: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := formatGitHubError(tt.err)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("formatGitHubError() = %T, want *APIError", err)
+			}
+			if apiErr.Auth != tt.wantAuth {
+				t.Errorf("Auth = %v, want %v", apiErr.Auth, tt.wantAuth)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Error() = %q, want it to contain %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestBranchExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		{"Exists", http.StatusOK, true, false},
+		{"Missing", http.StatusNotFound, false, false},
+		{"Bad credentials", http.StatusUnauthorized, false, true},
+		{"Forbidden", http.StatusForbidden, false, true},
+		{"Server error", http.StatusInternalServerError, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"name": "main"}`))
+			}))
+			defer srv.Close()
+
+			gh := github.NewClient(srv.Client())
+			gh.BaseURL, _ = url.Parse(srv.URL + "/")
+			c := &Client{client: gh, ctx: context.Background()}
+
+			got, err := c.BranchExists("owner", "repo", "main")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BranchExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BranchExists() = %v, want %v", got, tt.want)
+			}
+			var apiErr *APIError
+			if err != nil && !errors.As(err, &apiErr) {
+				t.Errorf("BranchExists() error = %T, want *APIError", err)
+			}
+		})
+	}
+}
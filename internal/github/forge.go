@@ -0,0 +1,239 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/vibe/internal/auth"
+)
+
+// Forge is a code hosting service that vibe can open pull requests on.
+// GitHub (including GitHub Enterprise) is built in; other services such as
+// GitLab, Bitbucket or Gitea plug in by registering a Factory under a forge
+// name with RegisterForge and mapping their hosts to it with RegisterHost.
+type Forge interface {
+	// ParseRemote extracts the repository owner and name from a remote URL
+	ParseRemote(url string) (*RepoInfo, error)
+
+	// CreatePR opens a pull request from head into base. For pull requests
+	// from a fork, head is qualified with the fork owner (see QualifiedHead).
+	CreatePR(owner, repo, base, head, title, body string) (*PRResult, error)
+
+	// FindOpenPR returns the open pull request for head (a branch name, or
+	// "owner:branch" for forks) in the repository, or nil if there is none
+	FindOpenPR(owner, repo, head string) (*PullRequest, error)
+
+	// UpdatePR replaces the title and description of a pull request
+	UpdatePR(owner, repo string, number int, title, body string) (*PRResult, error)
+
+	// ListChecks returns the CI checks reported for the head commit of a
+	// pull request
+	ListChecks(owner, repo string, number int) ([]Check, error)
+
+	// CreateReview posts a comment-only review on the pull request with a
+	// summary body and line comments, returning the review's URL
+	CreateReview(owner, repo string, number int, body string, comments []ReviewComment) (string, error)
+
+	// MergedPRs returns the pull requests merged since the given time,
+	// newest first
+	MergedPRs(owner, repo string, since time.Time) ([]MergedPR, error)
+
+	// CountOpenPRs returns the number of open pull requests
+	CountOpenPRs(owner, repo string) (int, error)
+
+	// GetIssue returns the issue with the given number
+	GetIssue(owner, repo string, number int) (*Issue, error)
+
+	// ForkParent returns the repository a fork was created from, or nil if
+	// the repository is not a fork
+	ForkParent(owner, repo string) (*RepoInfo, error)
+
+	// GetDefaultBranch returns the repository's default branch
+	GetDefaultBranch(owner, repo string) (string, error)
+
+	// BranchExists reports whether branch exists on the forge
+	BranchExists(owner, repo, branch string) (bool, error)
+
+	// Compare returns the commits and the diff of head (a branch name, or
+	// "owner:branch" for forks) since it forked from base
+	Compare(owner, repo, base, head string) (*Comparison, error)
+
+	// ListLabels returns the names of the labels defined in the repository
+	ListLabels(owner, repo string) ([]string, error)
+
+	// AddLabels applies labels to the pull request with the given number
+	AddLabels(owner, repo string, number int, labels []string) error
+
+	// RequestReviewers requests reviews from users and teams (team slugs)
+	RequestReviewers(owner, repo string, number int, users, teams []string) error
+
+	// AddAssignees assigns users to the pull request
+	AddAssignees(owner, repo string, number int, users []string) error
+
+	// SetMilestone sets the pull request's milestone to the open milestone
+	// best matching name, returning the matched milestone title
+	SetMilestone(owner, repo string, number int, name string) (string, error)
+
+	// AddToProject adds the pull request to the open project best matching
+	// name, returning the matched project title
+	AddToProject(owner, repo string, number int, name string) (string, error)
+
+	// EnableAutoMerge turns on auto-merge for the pull request, so it is
+	// merged with method ("merge", "squash" or "rebase") once its required
+	// checks and reviews pass
+	EnableAutoMerge(owner, repo string, number int, method string) error
+}
+
+// RateLimited is implemented by forges that report their API quota
+type RateLimited interface {
+	// RateLimit returns the quota reported by the last response, or nil
+	RateLimit() *RateLimit
+}
+
+// Factory creates a Forge for host, authenticated with token
+type Factory func(host, token string) (Forge, error)
+
+// ForgeGitHub is the name of the built-in GitHub forge
+const ForgeGitHub = "github"
+
+var (
+	registryMu sync.RWMutex
+
+	// factories maps forge names to their constructors
+	factories = map[string]Factory{
+		ForgeGitHub: newGitHubForge,
+	}
+
+	// hosts maps hostnames to forge names
+	hosts = map[string]string{
+		auth.DefaultGitHubHost: ForgeGitHub,
+	}
+)
+
+// RegisterForge makes a forge backend available under name
+func RegisterForge(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	factories[name] = factory
+}
+
+// RegisterHost maps host to the forge backend registered under forge
+func RegisterHost(host, forge string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := factories[forge]; !ok {
+		return fmt.Errorf("unknown forge %q for host %s (available: %s)", forge, host, strings.Join(forgeNames(), ", "))
+	}
+	hosts[host] = forge
+	return nil
+}
+
+// NewForge creates the forge registered for host, authenticated with token
+func NewForge(host, token string) (Forge, error) {
+	registryMu.RLock()
+	name, ok := hosts[host]
+	factory := factories[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf(`no forge configured for host %s
+
+If this is a GitHub Enterprise instance, add it to your config:
+  hosts:
+    %s:
+      forge: github`, host, host)
+	}
+
+	return factory(host, token)
+}
+
+// forgeNames returns the sorted names of all registered forges.
+// The caller must hold registryMu.
+func forgeNames() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MergeMethods are the merge methods accepted by EnableAutoMerge
+var MergeMethods = []string{"merge", "squash", "rebase"}
+
+// ValidMergeMethod reports whether method is one of MergeMethods
+func ValidMergeMethod(method string) bool {
+	for _, m := range MergeMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// QualifiedHead returns the head reference for a pull request from branch
+// in head's repository into base's repository: the plain branch name when
+// both are the same repository, or "owner:branch" for cross-fork PRs
+func QualifiedHead(head, base *RepoInfo, branch string) string {
+	if head == nil || base == nil || (head.Owner == base.Owner && head.Name == base.Name) {
+		return branch
+	}
+	return head.Owner + ":" + branch
+}
+
+// RemoteHost returns the hostname of a git remote URL, for both URL-style
+// remotes (https://host/..., ssh://git@host:22/...) and scp-style remotes
+// (git@host:owner/repo)
+func RemoteHost(remoteURL string) (string, error) {
+	host, _, err := SplitRemoteURL(remoteURL)
+	return host, err
+}
+
+// SplitRemoteURL splits a git remote URL into its hostname and repository
+// path. The path has no leading or trailing slashes and no ".git" suffix.
+// User names and ports are dropped, so all of these give
+// ("github.com", "owner/repo"):
+//   - https://github.com/owner/repo.git
+//   - https://user@github.com:443/owner/repo/
+//   - ssh://git@github.com:22/owner/repo.git
+//   - git@github.com:owner/repo.git
+func SplitRemoteURL(remoteURL string) (string, string, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var host, repoPath string
+	if strings.Contains(remoteURL, "://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil || parsed.Hostname() == "" {
+			return "", "", fmt.Errorf("could not parse remote URL: %s", remoteURL)
+		}
+		host, repoPath = parsed.Hostname(), parsed.Path
+	} else {
+		// scp-style: [user@]host:path
+		hostPart, pathPart, ok := strings.Cut(remoteURL, ":")
+		if !ok || strings.Contains(hostPart, "/") {
+			return "", "", fmt.Errorf("could not parse remote URL: %s", remoteURL)
+		}
+		if _, h, ok := strings.Cut(hostPart, "@"); ok {
+			hostPart = h
+		}
+		host, repoPath = hostPart, pathPart
+	}
+
+	if host == "" {
+		return "", "", fmt.Errorf("could not parse remote URL: %s", remoteURL)
+	}
+
+	repoPath = strings.Trim(repoPath, "/")
+	repoPath = strings.TrimSuffix(repoPath, ".git")
+	return host, repoPath, nil
+}
+
+// newGitHubForge is the Factory for github.com and GitHub Enterprise hosts
+func newGitHubForge(host, token string) (Forge, error) {
+	return NewClient(host, token)
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestRemoteHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{name: "HTTPS", url: "https://github.com/owner/repo.git", want: "github.com"},
+		{name: "SCP-style SSH", url: "git@ghe.company.com:owner/repo.git", want: "ghe.company.com"},
+		{name: "SSH URL with port", url: "ssh://git@git.example.com:2222/owner/repo.git", want: "git.example.com"},
+		{name: "SCP-style without user", url: "gitlab.com:group/repo", want: "gitlab.com"},
+		{name: "Local path", url: "/srv/git/repo.git", wantErr: true},
+		{name: "Empty", url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RemoteHost(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("RemoteHost() expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RemoteHost() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RemoteHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForgeRegistry(t *testing.T) {
+	if err := RegisterHost("git.example.com", "no-such-forge"); err == nil {
+		t.Error("RegisterHost() with unknown forge should fail")
+	}
+
+	if _, err := NewForge("unregistered.example.com", "token"); err == nil {
+		t.Error("NewForge() for unregistered host should fail")
+	}
+
+	if err := RegisterHost("ghe.example.com", ForgeGitHub); err != nil {
+		t.Fatalf("RegisterHost() error = %v", err)
+	}
+
+	forge, err := NewForge("ghe.example.com", "token")
+	if err != nil {
+		t.Fatalf("NewForge() error = %v", err)
+	}
+
+	info, err := forge.ParseRemote("git@ghe.example.com:team/service.git")
+	if err != nil {
+		t.Fatalf("ParseRemote() error = %v", err)
+	}
+	if info.Host != "ghe.example.com" || info.Owner != "team" || info.Name != "service" {
+		t.Errorf("ParseRemote() = %+v", info)
+	}
+}
+
+func TestQualifiedHead(t *testing.T) {
+	fork := &RepoInfo{Host: "github.com", Owner: "me", Name: "vibe"}
+	upstream := &RepoInfo{Host: "github.com", Owner: "org", Name: "vibe"}
+
+	if got := QualifiedHead(fork, fork, "feature"); got != "feature" {
+		t.Errorf("QualifiedHead() same repo = %q, want %q", got, "feature")
+	}
+	if got := QualifiedHead(fork, upstream, "feature"); got != "me:feature" {
+		t.Errorf("QualifiedHead() cross-fork = %q, want %q", got, "me:feature")
+	}
+}
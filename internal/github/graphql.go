@@ -0,0 +1,66 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// graphQLRequest is the body of a GraphQL API call
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the envelope of a GraphQL API response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLURL returns the GraphQL endpoint for host
+func graphQLURL(host string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com/graphql"
+	}
+	return fmt.Sprintf("https://%s/api/graphql", host)
+}
+
+// graphQL runs a GraphQL query or mutation and decodes its data into out.
+// out may be nil when the result is not needed.
+func (c *Client) graphQL(query string, variables map[string]interface{}, out interface{}) error {
+	req, err := c.client.NewRequest("POST", graphQLURL(c.host), &graphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build GraphQL request: %w", err)
+	}
+
+	var resp graphQLResponse
+	if _, err := c.client.Do(c.ctx, req, &resp); err != nil {
+		return formatGitHubError(err)
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		message := strings.Join(messages, "; ")
+		if err := validationError(message); err != nil {
+			return err
+		}
+		return fmt.Errorf("GitHub GraphQL error: %s", message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	return nil
+}
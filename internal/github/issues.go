@@ -0,0 +1,111 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultClosingKeyword is the keyword used to link issues a PR closes
+const DefaultClosingKeyword = "Closes"
+
+var (
+	// branchIssuePattern matches issue numbers in branch names such as
+	// "123-fix-login", "feature/123_login", "fix/issue-123" or "gh-123"
+	branchIssuePattern = regexp.MustCompile(`(?i)(?:^|[/_-])(?:issue-?|gh-?|#)?(\d+)(?:[-_/]|$)`)
+
+	// commitIssuePattern matches issue references in commit messages such
+	// as "#123" or "GH-123"
+	commitIssuePattern = regexp.MustCompile(`(?i)(?:^|[^\w/])(?:#|gh-)(\d+)\b`)
+
+	// closingRefPattern matches references that already close an issue
+	closingRefPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s+#(\d+)\b`)
+)
+
+// DetectIssues returns the issue numbers referenced by a branch name and
+// commit messages, in ascending order and without duplicates
+func DetectIssues(branch string, commitMessages []string) []int {
+	seen := make(map[int]bool)
+
+	add := func(matches [][]string) {
+		for _, m := range matches {
+			if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+				seen[n] = true
+			}
+		}
+	}
+
+	add(branchIssuePattern.FindAllStringSubmatch(branch, -1))
+	for _, msg := range commitMessages {
+		add(commitIssuePattern.FindAllStringSubmatch(msg, -1))
+	}
+
+	issues := make([]int, 0, len(seen))
+	for n := range seen {
+		issues = append(issues, n)
+	}
+	sort.Ints(issues)
+	return issues
+}
+
+// AppendClosingRefs appends a "<keyword> #N" line to body for each issue
+// that body doesn't already close
+func AppendClosingRefs(body, keyword string, issues []int) string {
+	if keyword == "" {
+		keyword = DefaultClosingKeyword
+	}
+
+	closed := make(map[int]bool)
+	for _, m := range closingRefPattern.FindAllStringSubmatch(body, -1) {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			closed[n] = true
+		}
+	}
+
+	var refs []string
+	for _, n := range issues {
+		if !closed[n] {
+			refs = append(refs, fmt.Sprintf("%s #%d", keyword, n))
+		}
+	}
+
+	if len(refs) == 0 {
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + strings.Join(refs, "\n")
+}
+
+// maxIssueBranchLength caps the length of branch names made from issue
+// titles
+const maxIssueBranchLength = 60
+
+// IssueBranchName returns a branch name for an issue, its number followed
+// by its title in kebab-case, e.g. "123-fix-login-redirect". The number
+// comes first so DetectIssues finds it again.
+func IssueBranchName(number int, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", number)
+
+	dash := true
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+
+	name := b.String()
+	if len(name) > maxIssueBranchLength {
+		name = name[:maxIssueBranchLength]
+		// Cut at a word boundary
+		name = name[:strings.LastIndex(name, "-")]
+	}
+	return name
+}
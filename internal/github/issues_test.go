@@ -0,0 +1,124 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectIssues(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		commits []string
+		want    []int
+	}{
+		{name: "Number prefix", branch: "123-fix-login", want: []int{123}},
+		{name: "Prefixed branch", branch: "feature/45_login", want: []int{45}},
+		{name: "Issue keyword", branch: "fix/issue-7", want: []int{7}},
+		{name: "GH keyword", branch: "gh-88-cleanup", want: []int{88}},
+		{name: "Version-like branch", branch: "release/v2", want: []int{}},
+		{
+			name:    "Commit references",
+			branch:  "feature/login",
+			commits: []string{"Fix redirect (#12)", "Handle GH-30 edge case", "Update docs"},
+			want:    []int{12, 30},
+		},
+		{
+			name:    "Duplicates across branch and commits",
+			branch:  "12-fix",
+			commits: []string{"Fix #12"},
+			want:    []int{12},
+		},
+		{
+			name:    "URL fragments are ignored",
+			branch:  "docs",
+			commits: []string{"See https://example.com/page/#5"},
+			want:    []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectIssues(tt.branch, tt.commits)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectIssues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendClosingRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		keyword string
+		issues  []int
+		want    string
+	}{
+		{
+			name:   "Default keyword",
+			body:   "Adds login.\n",
+			issues: []int{12},
+			want:   "Adds login.\n\nCloses #12",
+		},
+		{
+			name:    "Custom keyword",
+			body:    "Adds login.",
+			keyword: "Fixes",
+			issues:  []int{1, 2},
+			want:    "Adds login.\n\nFixes #1\nFixes #2",
+		},
+		{
+			name:   "Already closed",
+			body:   "Adds login.\n\nResolves #12",
+			issues: []int{12},
+			want:   "Adds login.\n\nResolves #12",
+		},
+		{
+			name:   "No issues",
+			body:   "Adds login.",
+			issues: nil,
+			want:   "Adds login.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AppendClosingRefs(tt.body, tt.keyword, tt.issues)
+			if got != tt.want {
+				t.Errorf("AppendClosingRefs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueBranchName(t *testing.T) {
+	tests := []struct {
+		name   string
+		number int
+		title  string
+		want   string
+	}{
+		{name: "Plain title", number: 123, title: "Fix login redirect", want: "123-fix-login-redirect"},
+		{name: "Punctuation", number: 7, title: "[Bug] Crash on `vibe pr --web`!", want: "7-bug-crash-on-vibe-pr-web"},
+		{name: "Empty title", number: 9, title: "", want: "9"},
+		{
+			name:   "Long title is cut at a word",
+			number: 42,
+			title:  "Support reading the configuration from environment variables as well as files",
+			want:   "42-support-reading-the-configuration-from-environment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IssueBranchName(tt.number, tt.title)
+			if got != tt.want {
+				t.Errorf("IssueBranchName() = %q, want %q", got, tt.want)
+			}
+			if issues := DetectIssues(got, nil); !reflect.DeepEqual(issues, []int{tt.number}) {
+				t.Errorf("DetectIssues(%q) = %v, want [%d]", got, issues, tt.number)
+			}
+		})
+	}
+}
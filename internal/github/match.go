@@ -0,0 +1,50 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fuzzyMatch finds the candidate best matching query, trying in order: an
+// exact (case-insensitive) match, a unique prefix match, then a unique
+// substring match. kind names what is being matched for error messages.
+func fuzzyMatch(kind, query string, candidates []string) (string, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return "", fmt.Errorf("empty %s name", kind)
+	}
+
+	for _, c := range candidates {
+		if strings.ToLower(c) == q {
+			return c, nil
+		}
+	}
+
+	matchers := []func(string) bool{
+		func(c string) bool { return strings.HasPrefix(strings.ToLower(c), q) },
+		func(c string) bool { return strings.Contains(strings.ToLower(c), q) },
+	}
+
+	for _, matches := range matchers {
+		var found []string
+		for _, c := range candidates {
+			if matches(c) {
+				found = append(found, c)
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			return "", fmt.Errorf("%s %q is ambiguous, it matches: %s", kind, query, strings.Join(found, ", "))
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no open %ss found", kind)
+	}
+	return "", fmt.Errorf("no %s matches %q (open: %s)", kind, query, strings.Join(candidates, ", "))
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	candidates := []string{"v1.4.0", "v1.5.0", "Q3 Roadmap", "Backlog"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Exact match", query: "v1.4.0", want: "v1.4.0"},
+		{name: "Case-insensitive", query: "backlog", want: "Backlog"},
+		{name: "Unique prefix", query: "q3", want: "Q3 Roadmap"},
+		{name: "Unique substring", query: "roadmap", want: "Q3 Roadmap"},
+		{name: "Ambiguous prefix", query: "v1", wantErr: true},
+		{name: "No match", query: "v2", wantErr: true},
+		{name: "Empty query", query: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fuzzyMatch("milestone", tt.query, candidates)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("fuzzyMatch() expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fuzzyMatch() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("fuzzyMatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
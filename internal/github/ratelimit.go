@@ -0,0 +1,184 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how often a rate-limited request is retried
+	maxRateLimitRetries = 3
+
+	// maxRateLimitWait is the longest vibe waits for a rate limit to reset
+	// before giving up and reporting the error
+	maxRateLimitWait = 2 * time.Minute
+
+	// secondaryRateLimitWait is the wait for secondary rate limits that
+	// come without a Retry-After header, as recommended by GitHub
+	secondaryRateLimitWait = time.Minute
+)
+
+// RateLimit is the API quota reported by the last response
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// String formats the quota, e.g. "4990/5000 remaining, resets at 15:04"
+func (r RateLimit) String() string {
+	return fmt.Sprintf("%d/%d remaining, resets at %s", r.Remaining, r.Limit, r.Reset.Local().Format("15:04"))
+}
+
+// rateLimitTransport records the quota headers of every response and
+// retries requests that hit a rate limit once it has reset
+type rateLimitTransport struct {
+	base http.RoundTripper
+
+	mu   sync.Mutex
+	last *RateLimit
+
+	// sleep is replaced in tests
+	sleep func(time.Duration)
+}
+
+// newRateLimitTransport wraps base with rate limit handling
+func newRateLimitTransport(base http.RoundTripper) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base, sleep: time.Sleep}
+}
+
+// RoundTrip sends the request, waiting and retrying on rate limits
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			slog.Debug("github request failed", "method", req.Method, "url", req.URL.String(), "duration", time.Since(start), "error", err)
+			return nil, err
+		}
+		slog.Debug("github request", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", time.Since(start))
+		t.record(resp.Header)
+
+		if attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited || wait > maxRateLimitWait {
+			return resp, nil
+		}
+
+		// Requests with a body can only be retried if it can be replayed
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		attrs := []any{"url", req.URL.String(), "wait", wait.Round(time.Second), "attempt", attempt + 1}
+		if quota := t.RateLimit(); quota != nil {
+			attrs = append(attrs, "remaining", quota.Remaining, "limit", quota.Limit)
+		}
+		slog.Info("github rate limit hit, retrying", attrs...)
+		t.sleep(wait)
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// record stores the quota reported in the response headers, if any
+func (t *rateLimitTransport) record(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}
+}
+
+// RateLimit returns the quota reported by the last response, or nil if no
+// response carried rate limit headers
+func (t *rateLimitTransport) RateLimit() *RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.last == nil {
+		return nil
+	}
+	last := *t.last
+	return &last
+}
+
+// rateLimitWait reports whether resp was rejected by a primary or secondary
+// rate limit and how long to wait before retrying
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	// Secondary rate limits say how long to back off
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	// Primary rate limit: wait for the quota to reset
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		wait := time.Unix(reset, 0).Sub(now) + time.Second
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimit(resp) {
+		return secondaryRateLimitWait, true
+	}
+	return 0, false
+}
+
+// isSecondaryRateLimit reports whether a 403 response body mentions a
+// secondary rate limit. The body is restored so it can still be decoded.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	response := func(status int, headers map[string]string, body string) *http.Response {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		for k, v := range headers {
+			resp.Header.Set(k, v)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name        string
+		resp        *http.Response
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{
+			name: "OK",
+			resp: response(200, map[string]string{"X-RateLimit-Remaining": "10"}, ""),
+		},
+		{
+			name:        "Retry-After",
+			resp:        response(403, map[string]string{"Retry-After": "30"}, ""),
+			wantWait:    30 * time.Second,
+			wantLimited: true,
+		},
+		{
+			name: "Primary limit exhausted",
+			resp: response(403, map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(now.Add(10*time.Second).Unix(), 10),
+			}, ""),
+			wantWait:    11 * time.Second,
+			wantLimited: true,
+		},
+		{
+			name:        "Secondary limit without Retry-After",
+			resp:        response(403, nil, `{"message":"You have exceeded a secondary rate limit"}`),
+			wantWait:    secondaryRateLimitWait,
+			wantLimited: true,
+		},
+		{
+			name: "Plain permission error",
+			resp: response(403, nil, `{"message":"Resource not accessible by integration"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, limited := rateLimitWait(tt.resp, now)
+			if limited != tt.wantLimited || wait != tt.wantWait {
+				t.Errorf("rateLimitWait() = %v, %v, want %v, %v", wait, limited, tt.wantWait, tt.wantLimited)
+			}
+		})
+	}
+}
+
+type fakeTransport struct {
+	responses []*http.Response
+	calls     int
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := f.responses[f.calls]
+	f.calls++
+	return resp, nil
+}
+
+func TestRateLimitTransportRetries(t *testing.T) {
+	limited := &http.Response{
+		StatusCode: 429,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	ok := &http.Response{
+		StatusCode: 200,
+		Header: http.Header{
+			"X-Ratelimit-Limit":     []string{"5000"},
+			"X-Ratelimit-Remaining": []string{"4999"},
+			"X-Ratelimit-Reset":     []string{"1700000000"},
+		},
+		Body: io.NopCloser(strings.NewReader("")),
+	}
+
+	fake := &fakeTransport{responses: []*http.Response{limited, ok}}
+	transport := newRateLimitTransport(fake)
+
+	var slept time.Duration
+	transport.sleep = func(d time.Duration) { slept += d }
+
+	req, _ := http.NewRequest("GET", "https://api.github.com/repos/o/r", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || fake.calls != 2 {
+		t.Errorf("RoundTrip() status %d after %d calls, want 200 after 2", resp.StatusCode, fake.calls)
+	}
+	if slept != time.Second {
+		t.Errorf("slept %v, want 1s", slept)
+	}
+
+	quota := transport.RateLimit()
+	if quota == nil || quota.Remaining != 4999 || quota.Limit != 5000 {
+		t.Errorf("RateLimit() = %+v, want 4999/5000", quota)
+	}
+}
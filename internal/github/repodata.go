@@ -0,0 +1,155 @@
+package github
+
+import (
+	"strings"
+)
+
+// repoData is the repository data the pr command needs, fetched in a single
+// GraphQL request instead of one REST call per item
+type repoData struct {
+	ID            string
+	DefaultBranch string
+	Parent        *RepoInfo
+
+	// Labels holds the repository's labels, LabelsComplete reports whether
+	// they all fit in the first page
+	Labels         []string
+	LabelsComplete bool
+
+	// Branch is the head branch the open pull requests were fetched for,
+	// empty if they were not requested
+	Branch  string
+	OpenPRs []openPR
+}
+
+// openPR is an open pull request for repoData.Branch
+type openPR struct {
+	PullRequest
+	HeadOwner string
+}
+
+// repoDataQuery fetches everything in repoData; pull requests are only
+// included when a branch is given
+const repoDataQuery = `query($owner: String!, $name: String!, $branch: String!, $withBranch: Boolean!) {
+  repository(owner: $owner, name: $name) {
+    id
+    defaultBranchRef { name }
+    parent { name owner { login } }
+    labels(first: 100) {
+      nodes { name }
+      pageInfo { hasNextPage }
+    }
+    pullRequests(headRefName: $branch, states: OPEN, first: 20) @include(if: $withBranch) {
+      nodes { number url title body headRepositoryOwner { login } }
+    }
+  }
+}`
+
+// repoData returns the data of a repository, fetching it on first use. When
+// branch is not empty, the open pull requests for branch are included.
+func (c *Client) repoData(owner, repo, branch string) (*repoData, error) {
+	key := owner + "/" + repo
+	if data, ok := c.repos[key]; ok && (branch == "" || data.Branch == branch) {
+		return data, nil
+	}
+
+	var resp struct {
+		Repository struct {
+			ID               string `json:"id"`
+			DefaultBranchRef struct {
+				Name string `json:"name"`
+			} `json:"defaultBranchRef"`
+			Parent *struct {
+				Name  string `json:"name"`
+				Owner struct {
+					Login string `json:"login"`
+				} `json:"owner"`
+			} `json:"parent"`
+			Labels struct {
+				Nodes []struct {
+					Name string `json:"name"`
+				} `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool `json:"hasNextPage"`
+				} `json:"pageInfo"`
+			} `json:"labels"`
+			PullRequests struct {
+				Nodes []struct {
+					Number              int    `json:"number"`
+					URL                 string `json:"url"`
+					Title               string `json:"title"`
+					Body                string `json:"body"`
+					HeadRepositoryOwner struct {
+						Login string `json:"login"`
+					} `json:"headRepositoryOwner"`
+				} `json:"nodes"`
+			} `json:"pullRequests"`
+		} `json:"repository"`
+	}
+
+	err := c.graphQL(repoDataQuery, map[string]interface{}{
+		"owner":      owner,
+		"name":       repo,
+		"branch":     branch,
+		"withBranch": branch != "",
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := resp.Repository
+	data := &repoData{
+		ID:             r.ID,
+		DefaultBranch:  r.DefaultBranchRef.Name,
+		LabelsComplete: !r.Labels.PageInfo.HasNextPage,
+		Branch:         branch,
+	}
+
+	if r.Parent != nil {
+		data.Parent = &RepoInfo{
+			Host:  c.host,
+			Owner: r.Parent.Owner.Login,
+			Name:  r.Parent.Name,
+		}
+	}
+
+	for _, label := range r.Labels.Nodes {
+		data.Labels = append(data.Labels, label.Name)
+	}
+
+	for _, pr := range r.PullRequests.Nodes {
+		data.OpenPRs = append(data.OpenPRs, openPR{
+			PullRequest: PullRequest{
+				Number: pr.Number,
+				URL:    pr.URL,
+				Title:  pr.Title,
+				Body:   pr.Body,
+			},
+			HeadOwner: pr.HeadRepositoryOwner.Login,
+		})
+	}
+
+	c.repos[key] = data
+	return data, nil
+}
+
+// findOpenPR returns the pull request in prs whose head is owned by
+// headOwner, or nil if there is none
+func findOpenPR(prs []openPR, headOwner string) *PullRequest {
+	for _, pr := range prs {
+		if strings.EqualFold(pr.HeadOwner, headOwner) {
+			found := pr.PullRequest
+			return &found
+		}
+	}
+	return nil
+}
+
+// splitHead splits a pull request head ("branch" or "owner:branch") into
+// its owner, defaulting to owner, and branch name
+func splitHead(owner, head string) (string, string) {
+	if headOwner, branch, ok := strings.Cut(head, ":"); ok {
+		return headOwner, branch
+	}
+	return owner, head
+}
@@ -0,0 +1,37 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestSplitHead(t *testing.T) {
+	tests := []struct {
+		head      string
+		wantOwner string
+		wantName  string
+	}{
+		{head: "feature/login", wantOwner: "base-owner", wantName: "feature/login"},
+		{head: "fork-owner:feature/login", wantOwner: "fork-owner", wantName: "feature/login"},
+	}
+
+	for _, tt := range tests {
+		owner, branch := splitHead("base-owner", tt.head)
+		if owner != tt.wantOwner || branch != tt.wantName {
+			t.Errorf("splitHead(%q) = %q, %q, want %q, %q", tt.head, owner, branch, tt.wantOwner, tt.wantName)
+		}
+	}
+}
+
+func TestFindOpenPR(t *testing.T) {
+	prs := []openPR{
+		{PullRequest: PullRequest{Number: 1}, HeadOwner: "someone-else"},
+		{PullRequest: PullRequest{Number: 2}, HeadOwner: "Alice"},
+	}
+
+	if pr := findOpenPR(prs, "alice"); pr == nil || pr.Number != 2 {
+		t.Errorf("findOpenPR() = %+v, want PR #2", pr)
+	}
+	if pr := findOpenPR(prs, "bob"); pr != nil {
+		t.Errorf("findOpenPR() = %+v, want nil", pr)
+	}
+}
@@ -0,0 +1,106 @@
+// Package i18n translates vibe's own messages: prompts, progress and
+// errors. Generated commit messages and PR descriptions are not affected.
+//
+// Messages are looked up by their English text, like gettext, so a message
+// missing from a catalog is shown in English. Format strings are translated
+// before formatting, e.g. fmt.Sprintf(i18n.T("Committed: %s"), hash).
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the language messages are written in
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps locales to their translations, keyed by the English text
+var catalogs = loadCatalogs()
+
+// locale is the current locale, from the environment until SetLocale
+var locale = Detect("")
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: %v", err))
+	}
+
+	catalogs := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: %v", err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = catalog
+	}
+	return catalogs
+}
+
+// Locales returns the available locales, English first
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// Supported reports whether messages are available in locale
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok || locale == DefaultLocale
+}
+
+// Detect returns the locale to use: configured if set, else the language of
+// LC_ALL, LC_MESSAGES or LANG, like other command line tools. Unavailable
+// languages fall back to English.
+func Detect(configured string) string {
+	candidates := []string{configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if l := language(c); Supported(l) {
+			return l
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// language returns the language of a locale name, e.g. "pt" for
+// "pt_BR.UTF-8", "pt-BR" or "PT"
+func language(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	name, _, _ = strings.Cut(name, "_")
+	name, _, _ = strings.Cut(name, "-")
+	return strings.ToLower(name)
+}
+
+// SetLocale makes T translate into locale, which must be supported
+func SetLocale(l string) {
+	locale = l
+}
+
+// T returns message in the current locale, or as is without a translation
+func T(message string) string {
+	if translated, ok := catalogs[locale][message]; ok && translated != "" {
+		return translated
+	}
+	return message
+}
@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		lcAll      string
+		lang       string
+		want       string
+	}{
+		{name: "nothing set", want: "en"},
+		{name: "LANG", lang: "de_DE.UTF-8", want: "de"},
+		{name: "LANG with dash", lang: "pt-BR", want: "pt"},
+		{name: "LANG with modifier", lang: "fr_FR@euro", want: "fr"},
+		{name: "LC_ALL over LANG", lcAll: "es_ES.UTF-8", lang: "de_DE.UTF-8", want: "es"},
+		{name: "config over environment", configured: "fr", lcAll: "es_ES.UTF-8", want: "fr"},
+		{name: "C locale", lang: "C", want: "en"},
+		{name: "unavailable language", lang: "ja_JP.UTF-8", want: "en"},
+		{name: "unavailable LC_ALL hides LANG", lcAll: "ja_JP.UTF-8", lang: "de_DE.UTF-8", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.lang)
+
+			if got := Detect(tt.configured); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.configured, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale(locale)
+
+	SetLocale("de")
+	if got := T("Commit cancelled."); got != "Commit abgebrochen." {
+		t.Errorf("T() = %q, want the German translation", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("T() = %q, want the message as is", got)
+	}
+
+	SetLocale("en")
+	if got := T("Commit cancelled."); got != "Commit cancelled." {
+		t.Errorf("T() = %q, want the message as is", got)
+	}
+}
+
+func TestLocales(t *testing.T) {
+	got := strings.Join(Locales(), ",")
+	if got != "en,de,es,fr,pt" {
+		t.Errorf("Locales() = %s, want en,de,es,fr,pt", got)
+	}
+}
+
+// TestCatalogsKeepFormat checks that translations keep the format verbs and
+// surrounding whitespace of their message, which callers rely on
+func TestCatalogsKeepFormat(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+
+	for name, catalog := range catalogs {
+		for message, translated := range catalog {
+			want := strings.Join(verbs.FindAllString(message, -1), " ")
+			if got := strings.Join(verbs.FindAllString(translated, -1), " "); got != want {
+				t.Errorf("%s: %q has verbs %q, want %q", name, translated, got, want)
+			}
+			if leading(translated) != leading(message) || trailing(translated) != trailing(message) {
+				t.Errorf("%s: %q doesn't keep the whitespace around %q", name, translated, message)
+			}
+		}
+	}
+}
+
+func leading(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \n"))]
+}
+
+func trailing(s string) string {
+	return s[len(strings.TrimRight(s, " \n")):]
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// debugLog receives the prompts and responses of all requests, see
+// SetDebugLog
+var debugLog struct {
+	w      io.Writer
+	redact func(string) string
+}
+
+// SetDebugLog makes every request write its prompts and the raw response,
+// or the error, to w, so prompt issues and parsing failures can be
+// reproduced. Everything written is passed through redact first, e.g. to
+// mask credentials in diffs. A nil w turns it off.
+func SetDebugLog(w io.Writer, redact func(string) string) {
+	debugLog.w = w
+	debugLog.redact = redact
+}
+
+// exchange is a request to the model and its outcome
+type exchange struct {
+	Model        string
+	MaxTokens    int
+	SystemPrompt string
+	UserPrompt   string
+	Response     string
+	Err          error
+	Duration     time.Duration
+	Usage        Usage
+	At           time.Time
+}
+
+// logExchange writes e to the debug log, if any
+func logExchange(e exchange) {
+	if debugLog.w == nil {
+		return
+	}
+	redact := debugLog.redact
+	if redact == nil {
+		redact = func(s string) string { return s }
+	}
+	_, _ = io.WriteString(debugLog.w, redact(formatExchange(e)))
+}
+
+// formatExchange lays out an exchange as plain text sections
+func formatExchange(e exchange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== %s %s (max %d tokens) ===\n", e.At.Format(time.RFC3339), e.Model, e.MaxTokens)
+	fmt.Fprintf(&b, "--- system ---\n%s\n", strings.TrimRight(e.SystemPrompt, "\n"))
+	fmt.Fprintf(&b, "--- prompt ---\n%s\n", strings.TrimRight(e.UserPrompt, "\n"))
+	if e.Err != nil {
+		fmt.Fprintf(&b, "--- error (%s) ---\n%v\n", e.Duration.Round(time.Millisecond), e.Err)
+	} else {
+		fmt.Fprintf(&b, "--- response (%s, %d prompt + %d completion tokens) ---\n%s\n",
+			e.Duration.Round(time.Millisecond), e.Usage.PromptTokens, e.Usage.CompletionTokens, strings.TrimRight(e.Response, "\n"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
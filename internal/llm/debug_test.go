@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogExchange(t *testing.T) {
+	var buf bytes.Buffer
+	SetDebugLog(&buf, func(s string) string { return strings.ReplaceAll(s, "sk-secret", "[REDACTED]") })
+	defer SetDebugLog(nil, nil)
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	logExchange(exchange{
+		Model:        "gpt-4o",
+		MaxTokens:    200,
+		SystemPrompt: "Write commit messages.",
+		UserPrompt:   "+KEY=sk-secret\n",
+		Response:     "Add key",
+		Duration:     1500 * time.Millisecond,
+		Usage:        Usage{PromptTokens: 10, CompletionTokens: 2},
+		At:           at,
+	})
+	logExchange(exchange{Model: "gpt-4o", UserPrompt: "x", Err: errors.New("timeout"), At: at})
+
+	got := buf.String()
+	for _, want := range []string{
+		"=== 2026-01-02T03:04:05Z gpt-4o (max 200 tokens) ===",
+		"--- prompt ---\n+KEY=[REDACTED]\n",
+		"--- response (1.5s, 10 prompt + 2 completion tokens) ---\nAdd key\n",
+		"--- error (0s) ---\ntimeout\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("debug log missing %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "sk-secret") {
+		t.Error("debug log should be redacted")
+	}
+}
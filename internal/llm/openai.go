@@ -4,153 +4,1498 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
-	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	openai "github.com/sashabaranov/go-openai"
+
+	"github.com/user/vibe/internal/auth"
+	"github.com/user/vibe/internal/changelog"
+	"github.com/user/vibe/internal/config"
 )
 
-const (
-	// DefaultModel is the default OpenAI model to use
-	DefaultModel = openai.GPT4o
+const (
+	// DefaultModel is the default OpenAI model to use
+	DefaultModel = openai.GPT4o
+
+	// maxDiffLength is the maximum length of diff to send to the API
+	maxDiffLength = 10000
+
+	// defaultTemperature keeps generated text focused, regenerating uses
+	// regenerateTemperature for a different take
+	defaultTemperature    = 0.3
+	regenerateTemperature = 0.8
+)
+
+// Client wraps the OpenAI client
+type Client struct {
+	client       *openai.Client
+	model        string
+	commitPrompt string
+	prPrompt     string
+
+	usage Usage
+}
+
+// Usage counts the tokens of the requests made by a client
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// PRContent holds the generated PR title and description
+type PRContent struct {
+	Title       string
+	Description string
+}
+
+// CommitGroup is a logical commit proposed by SuggestCommitSplit
+type CommitGroup struct {
+	Message string
+	Files   []string
+}
+
+// CommitOptions customizes commit message generation
+type CommitOptions struct {
+	// Template is the commit message template configured for the
+	// repository. When set, the message follows its structure.
+	Template string
+
+	// Previous is a message the user asked to regenerate, and Hint their
+	// optional instruction for the new one
+	Previous string
+	Hint     string
+}
+
+// PROptions customizes PR content generation
+type PROptions struct {
+	// Template is the repository's pull request template. When set, the
+	// description fills in the template instead of being free-form.
+	Template string
+
+	// Sections are the headings the description is made of, in order. They
+	// take precedence over Template.
+	Sections []string
+
+	// TestPlan asks for a "How to test" section with concrete steps and
+	// commands. TestFiles are the changed test files it can point to.
+	TestPlan  bool
+	TestFiles []string
+
+	// Risk asks for a low/medium/high risk rating with its reasons.
+	// HotFiles are the changed files on the repository's hot paths.
+	Risk     bool
+	HotFiles []string
+
+	// Previous is a title and description the user asked to regenerate,
+	// and Hint their optional instruction for the new ones
+	Previous string
+	Hint     string
+}
+
+// NewClient creates a new OpenAI client using OPENAI_API_KEY or the OS keyring.
+// The model and system prompts are taken from cfg when set.
+func NewClient(cfg *config.Config) (*Client, error) {
+	apiKey := auth.OpenAIKey()
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+	}
+
+	c := &Client{
+		client:       openai.NewClient(apiKey),
+		model:        DefaultModel,
+		commitPrompt: commitSystemPrompt,
+		prPrompt:     prSystemPrompt,
+	}
+
+	if cfg != nil {
+		if cfg.Model != "" {
+			c.model = cfg.Model
+		}
+		if cfg.Prompts.Commit != "" {
+			c.commitPrompt = cfg.Prompts.Commit
+		}
+		if cfg.Prompts.PR != "" {
+			c.prPrompt = cfg.Prompts.PR
+		}
+	}
+
+	return c, nil
+}
+
+// Model returns the name of the model used for generation
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Usage returns the tokens used by the client's requests so far
+func (c *Client) Usage() Usage {
+	return c.usage
+}
+
+// CommitPrompt returns the system prompt used for commit messages
+func (c *Client) CommitPrompt() string {
+	return c.commitPrompt
+}
+
+// GenerateCommitMessage generates a commit message from a diff
+func (c *Client) GenerateCommitMessage(diff string, opts CommitOptions) (string, error) {
+	// Truncate diff if too long
+	diff, _ = TruncateDiff(diff)
+
+	return c.commitMessage(buildCommitPrompt(diff), opts)
+}
+
+// GenerateSquashMessage generates a single commit message for commits being
+// squashed together, from their messages and combined diff
+func (c *Client) GenerateSquashMessage(commits []string, diff string, opts CommitOptions) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	return c.commitMessage(buildSquashPrompt(commits, diff), opts)
+}
+
+// GenerateRewordMessage generates a better message for an existing commit
+// from its diff, with its current message as context
+func (c *Client) GenerateRewordMessage(message, diff string, opts CommitOptions) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	return c.commitMessage(buildRewordPrompt(message, diff), opts)
+}
+
+// GenerateCherryPickMessage generates the message of a cherry-picked
+// commit from its original message and the changes as applied to branch,
+// whose recent commits give the context
+func (c *Client) GenerateCherryPickMessage(original, branch string, recent []string, diff string, opts CommitOptions) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	return c.commitMessage(buildCherryPickPrompt(original, branch, recent, diff), opts)
+}
+
+// GenerateRevertMessage generates the message of a revert commit that
+// explains what is undone and why, from the reverted commit's message and
+// diff and the reason given by the user, if any
+func (c *Client) GenerateRevertMessage(original, reason, diff string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(revertSystemPrompt, buildRevertPrompt(original, reason, diff), 400)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(content), "\"'`"), nil
+}
+
+// commitMessage generates a commit message with the commit system prompt,
+// following the commit template, if any
+func (c *Client) commitMessage(prompt string, opts CommitOptions) (string, error) {
+	maxTokens := 200
+	if opts.Template != "" {
+		prompt += buildCommitTemplateInstructions(opts.Template)
+		// Messages following a template have a body
+		maxTokens = 500
+	}
+
+	prompt += buildRegenerateInstructions(opts.Previous, opts.Hint)
+
+	content, err := c.completeAt(c.commitPrompt, prompt, maxTokens, temperature(opts.Previous))
+	if err != nil {
+		return "", err
+	}
+
+	message := strings.TrimSpace(content)
+	if opts.Template != "" {
+		message = stripComments(message)
+	}
+
+	// Remove any quotes if the model wrapped the message
+	message = strings.Trim(message, "\"'`")
+
+	return message, nil
+}
+
+// GenerateEmptyCommitMessage generates a message for a commit without
+// changes, from the branch name and the subjects of the latest commits
+func (c *Client) GenerateEmptyCommitMessage(branch string, recent []string) (string, error) {
+	content, err := c.complete(c.commitPrompt, buildEmptyCommitPrompt(branch, recent), 200)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Trim(strings.TrimSpace(content), "\"'`"), nil
+}
+
+// GenerateTagMessage generates the message of an annotated release tag,
+// summarizing the subjects of the commits since the previous tag
+func (c *Client) GenerateTagMessage(tag, previous string, commits []string) (string, error) {
+	content, err := c.complete(tagSystemPrompt, buildTagPrompt(tag, previous, commits), 500)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Trim(strings.TrimSpace(content), "\"'`"), nil
+}
+
+// SummarizeChangelog turns commit messages into human-readable changelog
+// entries, grouped by kind. Trivial commits are left out.
+func (c *Client) SummarizeChangelog(commits []string) ([]changelog.Entry, error) {
+	content, err := c.complete(changelogSystemPrompt, buildChangelogPrompt(commits), 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseChangelogEntries(content), nil
+}
+
+// GenerateReleaseNotes summarizes the commits of a range, e.g.
+// "v1.2.0..v1.3.0", into polished release notes in Markdown, or with
+// announcement, into a short announcement paragraph
+func (c *Client) GenerateReleaseNotes(rangeSpec string, commits []string, announcement bool) (string, error) {
+	systemPrompt := notesSystemPrompt
+	if announcement {
+		systemPrompt = announcementSystemPrompt
+	}
+
+	content, err := c.complete(systemPrompt, buildNotesPrompt(rangeSpec, commits), 1000)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// ExplainCommit explains in plain English what a commit changed and why
+// it might matter, from its message and diff
+func (c *Client) ExplainCommit(message, diff string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(explainCommitSystemPrompt, buildExplainCommitPrompt(message, diff), 700)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// LineCommit is a commit that last changed the lines being explained
+type LineCommit struct {
+	Hash    string
+	Author  string
+	Date    string
+	Message string
+	Diff    string
+}
+
+// ExplainLines explains why lines of a file are the way they are, from the
+// commits that last changed them
+func (c *Client) ExplainLines(path, code string, commits []LineCommit) (string, error) {
+	content, err := c.complete(whySystemPrompt, buildWhyPrompt(path, code, commits), 600)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// ExplainChanges summarizes uncommitted changes in prose, as a look at
+// what is about to be committed
+func (c *Client) ExplainChanges(diff string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(explainChangesSystemPrompt, buildExplainChangesPrompt(diff), 700)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// Kinds of review findings
+const (
+	FindingBug  = "bug"
+	FindingTest = "test"
+	FindingRisk = "risk"
+)
+
+// ReviewFinding is a problem found by a code review
+type ReviewFinding struct {
+	// Kind is FindingBug, FindingTest or FindingRisk
+	Kind string
+
+	Path string
+
+	// Line is the line in the new version of the file, 0 if the finding
+	// is about the file as a whole
+	Line int
+
+	Message string
+}
+
+// ReviewDiff reviews a diff for potential bugs, missing tests and risky
+// changes. The diff should have line numbers (see git.NumberLines) so
+// findings can point at lines. No findings means nothing worth reporting.
+func (c *Client) ReviewDiff(diff string) ([]ReviewFinding, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(reviewSystemPrompt, buildReviewPrompt(diff), 1500)
+	if err != nil {
+		return nil, err
+	}
+	return parseReviewFindings(content), nil
+}
+
+// SummarizeBranch writes a one-paragraph summary of a branch's commits
+// and diff, for tickets and status updates
+func (c *Client) SummarizeBranch(commits, diff string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(summarizeSystemPrompt, buildSummarizePrompt(commits, diff), 400)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// GenerateStandup writes a "What I did / What's next" standup update from
+// the recent commits and the branch currently checked out
+func (c *Client) GenerateStandup(commits []string, currentBranch string) (string, error) {
+	content, err := c.complete(standupSystemPrompt, buildStandupPrompt(commits, currentBranch), 500)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// GenerateReport writes an activity summary for period, such as a week,
+// from the commits and merged pull requests in it
+func (c *Client) GenerateReport(period string, commits, prs []string) (string, error) {
+	content, err := c.complete(reportSystemPrompt, buildReportPrompt(period, commits, prs), 800)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// SummarizeRepo writes a one-paragraph "state of the repo" summary from
+// activity statistics and the recent commits
+func (c *Client) SummarizeRepo(stats string, commits []string) (string, error) {
+	content, err := c.complete(pulseSystemPrompt, buildPulsePrompt(stats, commits), 400)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// ExplainChurn comments on the most changed files of a repository, each
+// listed with its commit count, changed lines and size
+func (c *Client) ExplainChurn(files []string) (string, error) {
+	content, err := c.complete(churnSystemPrompt, buildChurnPrompt(files), 600)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// Conflict is a conflicted region of a file with the lines around it
+type Conflict struct {
+	Path   string
+	Before string
+	After  string
+
+	// OursLabel and TheirsLabel name the sides, e.g. "HEAD" and a branch
+	OursLabel   string
+	TheirsLabel string
+
+	// Ours and Theirs are the conflicting versions, Base the common
+	// ancestor's version if known
+	Ours   string
+	Base   string
+	Theirs string
+}
+
+// ConflictResolution is a proposed resolution of a conflict
+type ConflictResolution struct {
+	// Code replaces the conflicted region, it may be empty
+	Code string
+
+	// Reason explains how the sides were combined
+	Reason string
+}
+
+// ResolveConflict proposes code that replaces a conflicted region, keeping
+// the intent of both sides
+func (c *Client) ResolveConflict(conflict Conflict) (*ConflictResolution, error) {
+	content, err := c.complete(resolveSystemPrompt, buildResolvePrompt(conflict), 1500)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := parseResolution(content)
+	if resolution == nil {
+		return nil, fmt.Errorf("the model returned no resolution")
+	}
+	return resolution, nil
+}
+
+// BumpSuggestion is a recommended semver bump and the reasons for it
+type BumpSuggestion struct {
+	// Level is changelog.Major, changelog.Minor or changelog.Patch
+	Level string
+
+	// Reason explains which changes drove the decision
+	Reason string
+}
+
+// SuggestBump recommends a semver bump for the commits since version
+func (c *Client) SuggestBump(version string, commits []string) (*BumpSuggestion, error) {
+	content, err := c.complete(bumpSystemPrompt, buildBumpPrompt(version, commits), 400)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := parseBumpSuggestion(content)
+	if suggestion == nil {
+		return nil, fmt.Errorf("the model returned no bump level")
+	}
+	return suggestion, nil
+}
+
+// stashPrefix starts every generated stash message
+const stashPrefix = "WIP: "
+
+// GenerateStashMessage generates a short message describing unfinished
+// work for git stash, e.g. "WIP: migrate auth middleware to context"
+func (c *Client) GenerateStashMessage(diff string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(stashSystemPrompt, buildStashPrompt(diff), 60)
+	if err != nil {
+		return "", err
+	}
+
+	message := parseStashMessage(content)
+	if message == "" {
+		return "", fmt.Errorf("the model returned no usable stash message")
+	}
+	return message, nil
+}
+
+// GeneratePRContent generates a PR title and description
+func (c *Client) GeneratePRContent(commits string, diff string, opts PROptions) (*PRContent, error) {
+	// Truncate diff if too long
+	diff, _ = TruncateDiff(diff)
+
+	prompt := buildPRPrompt(commits, diff)
+
+	maxTokens := 500
+	structured := true
+	switch {
+	case len(opts.Sections) > 0:
+		prompt += buildTemplateInstructions(sectionsTemplate(opts.Sections))
+		maxTokens = 1000
+	case opts.Template != "":
+		prompt += buildTemplateInstructions(opts.Template)
+		// Filled-in templates are longer than free-form descriptions
+		maxTokens = 1000
+	default:
+		structured = false
+	}
+	if opts.TestPlan {
+		prompt += buildTestPlanInstructions(opts.TestFiles, structured)
+		maxTokens += 300
+	}
+	if opts.Risk {
+		prompt += buildRiskInstructions(opts.HotFiles, structured)
+		maxTokens += 200
+	}
+
+	prompt += buildRegenerateInstructions(opts.Previous, opts.Hint)
+
+	content, err := c.completeAt(c.prPrompt, prompt, maxTokens, temperature(opts.Previous))
+	if err != nil {
+		return nil, err
+	}
+
+	pr := parsePRContent(content)
+	if len(opts.Sections) > 0 {
+		pr.Description = arrangeSections(pr.Description, opts.Sections)
+	}
+	return pr, nil
+}
+
+// SuggestLabels asks the model to pick the labels from available that fit
+// the changes. Only names from available are ever returned.
+func (c *Client) SuggestLabels(available []string, commits, diff string) ([]string, error) {
+	if len(available) == 0 {
+		return nil, nil
+	}
+
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(labelSystemPrompt, buildLabelPrompt(available, commits, diff), 100)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLabels(content, available), nil
+}
+
+// GenerateDiagram asks the model for a small Mermaid flowchart of the
+// modules the changes touch and how they relate. modules are the changed
+// directories with their number of changed files.
+func (c *Client) GenerateDiagram(modules []string, commits, diff string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(diagramSystemPrompt, buildDiagramPrompt(modules, commits, diff), 500)
+	if err != nil {
+		return "", err
+	}
+
+	diagram := parseDiagram(content)
+	if diagram == "" {
+		return "", fmt.Errorf("the model returned no usable diagram")
+	}
+	return diagram, nil
+}
+
+// SuggestCommitSplit asks the model to group the staged files into logical
+// commits, each with its own message. Every file in files ends up in
+// exactly one group; a single group means the changes belong together.
+func (c *Client) SuggestCommitSplit(files []string, diff string) ([]CommitGroup, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(splitSystemPrompt, buildSplitPrompt(files, diff), 600)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitGroups(content, files), nil
+}
+
+// SuggestBranchName asks the model for a kebab-case branch name for the
+// work described by description or, if that's empty, by diff. The name
+// starts with one of prefixes when any fit; other prefixes are dropped.
+func (c *Client) SuggestBranchName(description, diff string, prefixes []string) (string, error) {
+	diff, _ = TruncateDiff(diff)
+
+	content, err := c.complete(branchSystemPrompt, buildBranchPrompt(description, diff, prefixes), 50)
+	if err != nil {
+		return "", err
+	}
+
+	name := parseBranchName(content, prefixes)
+	if name == "" {
+		return "", fmt.Errorf("the model returned no usable branch name")
+	}
+	return name, nil
+}
+
+// complete sends a system and user prompt to the model and returns the
+// content of the first choice
+func (c *Client) complete(systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	return c.completeAt(systemPrompt, userPrompt, maxTokens, defaultTemperature)
+}
+
+// completeAt is complete with the given sampling temperature
+func (c *Client) completeAt(systemPrompt, userPrompt string, maxTokens int, temperature float32) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: c.model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: systemPrompt,
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: userPrompt,
+				},
+			},
+			Temperature: temperature,
+			MaxTokens:   maxTokens,
+		},
+	)
+
+	logged := exchange{
+		Model:        c.model,
+		MaxTokens:    maxTokens,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Err:          err,
+		Duration:     time.Since(start),
+		At:           start,
+	}
+	if err != nil {
+		slog.Debug("openai request failed", "model", c.model, "duration", logged.Duration, "error", err)
+		logExchange(logged)
+		return "", formatAPIError(err)
+	}
+	slog.Debug("openai request", "model", c.model, "max_tokens", maxTokens, "duration", logged.Duration,
+		"prompt_tokens", resp.Usage.PromptTokens, "completion_tokens", resp.Usage.CompletionTokens)
+
+	logged.Usage = Usage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	}
+	if len(resp.Choices) > 0 {
+		logged.Response = resp.Choices[0].Message.Content
+	}
+	logExchange(logged)
+
+	c.usage.PromptTokens += resp.Usage.PromptTokens
+	c.usage.CompletionTokens += resp.Usage.CompletionTokens
+	c.usage.TotalTokens += resp.Usage.TotalTokens
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from OpenAI")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// TruncateDiff shortens diff to the maximum length sent to the API and
+// reports whether it had to be truncated
+func TruncateDiff(diff string) (string, bool) {
+	if len(diff) <= maxDiffLength {
+		return diff, false
+	}
+	return diff[:maxDiffLength] + "\n\n[diff truncated due to length]", true
+}
+
+// buildCommitPrompt creates the user prompt for commit message generation
+func buildCommitPrompt(diff string) string {
+	return fmt.Sprintf(`Generate a commit message for the following changes:
+
+%s`, diff)
+}
+
+// buildSquashPrompt creates the user prompt for the message of squashed
+// commits
+func buildSquashPrompt(commits []string, diff string) string {
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength/4 {
+		list = list[:maxDiffLength/4] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`These commits are being squashed into one. Generate a single commit message
+for the combined changes, covering what they achieve together rather than
+listing each commit. Leave out fixups, typo fixes and work that later
+commits undid.
+
+Commits being squashed (newest first):
+%s
+
+Combined changes:
+%s`, list, diff)
+}
+
+// buildRewordPrompt creates the user prompt for rewording a commit
+func buildRewordPrompt(message, diff string) string {
+	return fmt.Sprintf(`Generate a better commit message for an existing commit. Its current
+message may be vague or outdated; use it only as a hint of the intent and
+keep any issue references it has.
+
+Current message:
+%s
+
+Changes:
+%s`, message, diff)
+}
+
+// buildCherryPickPrompt creates the user prompt for a cherry-picked commit
+func buildCherryPickPrompt(original, branch string, recent []string, diff string) string {
+	return fmt.Sprintf(`Generate a commit message for a commit cherry-picked onto the branch %s.
+Start from the original message, but adapt it to the target branch: describe
+the changes as they were applied here, and mention that it is a backport
+when the branch looks like a release or maintenance branch. Do not mention
+the original commit hash, it is added separately.
+
+Original message:
+%s
+
+Recent commits on %s:
+%s
+
+Changes as applied:
+%s`, branch, original, branch, strings.Join(recent, "\n"), diff)
+}
+
+// buildRevertPrompt creates the user prompt for a revert commit message
+func buildRevertPrompt(original, reason, diff string) string {
+	if reason == "" {
+		reason = "(none given, do not guess one)"
+	}
+	return fmt.Sprintf(`Generate the message of a commit that reverts the following commit.
+
+Reason for the revert:
+%s
+
+Reverted commit message:
+%s
+
+Changes of the reverted commit:
+%s`, reason, original, diff)
+}
+
+// buildEmptyCommitPrompt creates the user prompt for an empty commit
+func buildEmptyCommitPrompt(branch string, recent []string) string {
+	if branch == "" {
+		branch = "(detached HEAD)"
+	}
+	return fmt.Sprintf(`Generate a commit message for an empty commit: it changes no files and is
+usually made to trigger CI or mark a point in history. Say so plainly.
+
+Branch: %s
+
+Recent commits:
+%s`, branch, strings.Join(recent, "\n"))
+}
+
+// buildTagPrompt creates the user prompt for a release tag message
+func buildTagPrompt(tag, previous string, commits []string) string {
+	since := "the start of the project"
+	if previous != "" {
+		since = previous
+	}
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength {
+		list = list[:maxDiffLength] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`Write the message for the annotated tag %s.
+
+Commits since %s:
+%s`, tag, since, list)
+}
+
+// buildChangelogPrompt creates the user prompt for changelog entries
+func buildChangelogPrompt(commits []string) string {
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength {
+		list = list[:maxDiffLength] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`Write changelog entries for the following commits:
+
+%s`, list)
+}
+
+// changelogKinds maps the kinds the model labels entries with
+var changelogKinds = map[string]changelog.Kind{
+	"breaking": changelog.Breaking,
+	"feature":  changelog.Feature,
+	"fix":      changelog.Fix,
+	"other":    changelog.Other,
+}
+
+// parseChangelogEntries parses "<kind>: <text>" lines, skipping lines with
+// unknown kinds and repeated entries
+func parseChangelogEntries(content string) []changelog.Entry {
+	var entries []changelog.Entry
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "-* ")
+		label, text, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		kind, ok := changelogKinds[strings.ToLower(strings.TrimSpace(label))]
+		text = strings.Trim(strings.TrimSpace(text), "\"'`")
+		if !ok || text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		entries = append(entries, changelog.Entry{Kind: kind, Text: text})
+	}
+	return entries
+}
+
+// buildNotesPrompt creates the user prompt for release notes of a range
+func buildNotesPrompt(rangeSpec string, commits []string) string {
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength {
+		list = list[:maxDiffLength] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`Summarize the changes in %s from these commits:
+
+%s`, rangeSpec, list)
+}
+
+// buildExplainCommitPrompt creates the user prompt for explaining a commit
+func buildExplainCommitPrompt(message, diff string) string {
+	return fmt.Sprintf(`Explain the following commit.
+
+Commit message:
+%s
+
+Diff:
+%s`, message, diff)
+}
+
+// buildWhyPrompt creates the user prompt for explaining lines of a file.
+// The commits share the diff length limit.
+func buildWhyPrompt(path, code string, commits []LineCommit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Explain why these lines of %s are the way they are:\n\n%s\n", path, code)
+
+	budget := maxDiffLength / max(len(commits), 1)
+	for _, commit := range commits {
+		diff := commit.Diff
+		if len(diff) > budget {
+			diff = diff[:budget] + "\n\n[diff truncated due to length]"
+		}
+		fmt.Fprintf(&b, "\nCommit %s by %s on %s:\n%s\n\nDiff:\n%s\n", commit.Hash, commit.Author, commit.Date, commit.Message, diff)
+	}
+	return b.String()
+}
+
+// buildExplainChangesPrompt creates the user prompt for explaining
+// uncommitted changes
+func buildExplainChangesPrompt(diff string) string {
+	return fmt.Sprintf(`Explain the following uncommitted changes:
+
+%s`, diff)
+}
+
+// buildReviewPrompt creates the user prompt for a code review
+func buildReviewPrompt(diff string) string {
+	return fmt.Sprintf(`Review the following changes:
+
+%s`, diff)
+}
+
+// reviewFindingPattern matches "<kind> <path>[:<line>]: <message>" lines
+var reviewFindingPattern = regexp.MustCompile("(?i)^[-*\\s]*(bug|test|risk)\\s+`?([^\\s:`]+)`?(?::(\\d+))?:\\s*(.+)$")
+
+// parseReviewFindings parses review findings, one per line, ignoring any
+// other text
+func parseReviewFindings(content string) []ReviewFinding {
+	var findings []ReviewFinding
+	for _, line := range strings.Split(content, "\n") {
+		m := reviewFindingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(m[3])
+		findings = append(findings, ReviewFinding{
+			Kind:    strings.ToLower(m[1]),
+			Path:    m[2],
+			Line:    number,
+			Message: strings.TrimSpace(m[4]),
+		})
+	}
+	return findings
+}
+
+// buildSummarizePrompt creates the user prompt for a branch summary
+func buildSummarizePrompt(commits, diff string) string {
+	return fmt.Sprintf(`Summarize the work on this branch.
+
+Commits:
+%s
+
+Diff:
+%s`, commits, diff)
+}
+
+// buildStandupPrompt creates the user prompt for a standup update
+func buildStandupPrompt(commits []string, currentBranch string) string {
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength {
+		list = list[:maxDiffLength] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`Write my standup update. I am currently on the branch %s.
+
+My commits, newest first, with the branch each is on:
+%s`, currentBranch, list)
+}
+
+// buildReportPrompt creates the user prompt for an activity report. The
+// commits share the length limit with the pull requests, which carry more
+// weight and are listed first.
+func buildReportPrompt(period string, commits, prs []string) string {
+	merged := "(none)"
+	if len(prs) > 0 {
+		merged = strings.Join(prs, "\n")
+	}
+	list := strings.Join(commits, "\n")
+	if limit := maxDiffLength - len(merged); len(list) > limit {
+		list = list[:max(limit, 0)] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`Write the activity report for %s.
+
+Merged pull requests:
+%s
+
+Commits, newest first, with their branch and author:
+%s`, period, merged, list)
+}
+
+// buildPulsePrompt creates the user prompt for a state of the repo summary
+func buildPulsePrompt(stats string, commits []string) string {
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength {
+		list = list[:maxDiffLength] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`Summarize the state of this repository.
+
+Activity:
+%s
+
+Recent commits, newest first:
+%s`, stats, list)
+}
+
+// buildChurnPrompt creates the user prompt for churn commentary
+func buildChurnPrompt(files []string) string {
+	return fmt.Sprintf(`These are the most frequently changed files in the repository, most changed first:
+
+%s`, strings.Join(files, "\n"))
+}
+
+// buildResolvePrompt creates the user prompt for resolving a conflict.
+// Each side gets its share of the diff length limit.
+func buildResolvePrompt(conflict Conflict) string {
+	budget := maxDiffLength / 4
+	cut := func(s string) string {
+		if len(s) > budget {
+			return s[:budget] + "\n[truncated due to length]"
+		}
+		return s
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resolve this conflict in %s.\n", conflict.Path)
+	fmt.Fprintf(&b, "\nLines before the conflict:\n%s\n", cut(conflict.Before))
+	fmt.Fprintf(&b, "\nOurs (%s):\n%s\n", conflict.OursLabel, cut(conflict.Ours))
+	if conflict.Base != "" {
+		fmt.Fprintf(&b, "\nCommon ancestor:\n%s\n", cut(conflict.Base))
+	}
+	fmt.Fprintf(&b, "\nTheirs (%s):\n%s\n", conflict.TheirsLabel, cut(conflict.Theirs))
+	fmt.Fprintf(&b, "\nLines after the conflict:\n%s\n", cut(conflict.After))
+	return b.String()
+}
+
+// parseResolution parses a "Reason: <text>" line followed by a
+// "Resolution:" line and the code, which may be wrapped in a code fence.
+// It returns nil without a "Resolution:" line.
+func parseResolution(content string) *ConflictResolution {
+	var reason []string
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		label, value, _ := strings.Cut(line, ":")
+		switch strings.ToLower(strings.Trim(strings.TrimSpace(label), "*`")) {
+		case "reason":
+			reason = append(reason, strings.TrimSpace(value))
+			continue
+		case "resolution":
+			if strings.Trim(strings.TrimSpace(value), "*`") != "" {
+				break
+			}
+			code := lines[i+1:]
+			// Drop blank lines and a code fence around the code
+			for len(code) > 0 && strings.TrimSpace(code[0]) == "" {
+				code = code[1:]
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			if len(code) >= 2 && strings.HasPrefix(code[0], "```") && strings.TrimSpace(code[len(code)-1]) == "```" {
+				code = code[1 : len(code)-1]
+			}
+			return &ConflictResolution{
+				Code:   strings.Join(code, "\n"),
+				Reason: strings.TrimSpace(strings.Join(reason, " ")),
+			}
+		}
+		if len(reason) > 0 && strings.TrimSpace(line) != "" {
+			reason = append(reason, strings.TrimSpace(line))
+		}
+	}
+	return nil
+}
+
+// buildBumpPrompt creates the user prompt for a semver bump suggestion
+func buildBumpPrompt(version string, commits []string) string {
+	list := strings.Join(commits, "\n")
+	if len(list) > maxDiffLength {
+		list = list[:maxDiffLength] + "\n[commit list truncated due to length]"
+	}
+	return fmt.Sprintf(`The current version is %s. Recommend the next version bump for these commits:
 
-	// maxDiffLength is the maximum length of diff to send to the API
-	maxDiffLength = 10000
-)
+%s`, version, list)
+}
 
-// Client wraps the OpenAI client
-type Client struct {
-	client *openai.Client
-	model  string
+// parseBumpSuggestion parses a "Bump: <level>" line and takes the rest of
+// the answer as the reason. It returns nil without a valid level.
+func parseBumpSuggestion(content string) *BumpSuggestion {
+	var suggestion *BumpSuggestion
+	var reason []string
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		label, value, ok := strings.Cut(line, ":")
+		if suggestion == nil && ok && strings.EqualFold(strings.Trim(strings.TrimSpace(label), "*`"), "bump") {
+			level := strings.ToLower(strings.Trim(strings.TrimSpace(value), "\"'`*."))
+			switch level {
+			case changelog.Major, changelog.Minor, changelog.Patch:
+				suggestion = &BumpSuggestion{Level: level}
+			}
+			continue
+		}
+		reason = append(reason, line)
+	}
+
+	if suggestion != nil {
+		suggestion.Reason = strings.TrimSpace(strings.Join(reason, "\n"))
+	}
+	return suggestion
 }
 
-// PRContent holds the generated PR title and description
-type PRContent struct {
-	Title       string
-	Description string
+// buildStashPrompt creates the user prompt for a stash message
+func buildStashPrompt(diff string) string {
+	return fmt.Sprintf(`Describe the unfinished work in the following changes:
+
+%s`, diff)
 }
 
-// NewClient creates a new OpenAI client from environment variable
-func NewClient() (*Client, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+// parseStashMessage takes the first line of the model's answer and makes
+// sure it starts with stashPrefix exactly once
+func parseStashMessage(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	line = strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "\"'`"))
+	if len(line) >= len(stashPrefix) && strings.EqualFold(line[:len(stashPrefix)], stashPrefix) {
+		line = strings.TrimSpace(line[len(stashPrefix):])
+	}
+	if line == "" {
+		return ""
 	}
+	return stashPrefix + line
+}
+
+// buildPRPrompt creates the user prompt for PR content generation
+func buildPRPrompt(commits, diff string) string {
+	return fmt.Sprintf(`Generate a PR title and description for the following changes.
+
+Commits:
+%s
 
-	return &Client{
-		client: openai.NewClient(apiKey),
-		model:  DefaultModel,
-	}, nil
+Diff:
+%s`, commits, diff)
 }
 
-// GenerateCommitMessage generates a commit message from a diff
-func (c *Client) GenerateCommitMessage(diff string) (string, error) {
-	// Truncate diff if too long
-	if len(diff) > maxDiffLength {
-		diff = diff[:maxDiffLength] + "\n\n[diff truncated due to length]"
+// buildTemplateInstructions asks the model to fill in the repository's PR
+// template rather than writing a free-form description
+func buildTemplateInstructions(template string) string {
+	return fmt.Sprintf(`
+
+The repository has a pull request template. Write the description by filling
+in this template:
+- Keep every heading exactly as written and in the same order
+- Replace placeholder text and HTML comments with content for these changes
+- Keep checklist items as "- [ ]" and only check ("- [x]") the ones the
+  changes clearly satisfy
+- Write "N/A" under sections that don't apply
+
+Template:
+%s`, strings.TrimSpace(template))
+}
+
+// sectionsTemplate turns configured section names into a template with
+// one heading each
+func sectionsTemplate(sections []string) string {
+	var b strings.Builder
+	for _, section := range sections {
+		fmt.Fprintf(&b, "## %s\n\n<!-- %s of these changes -->\n\n", section, section)
 	}
+	return b.String()
+}
 
-	prompt := buildCommitPrompt(diff)
+// buildTestPlanInstructions asks for a "How to test" section in the PR
+// description, pointing at the changed test files
+func buildTestPlanInstructions(testFiles []string, template bool) string {
+	var b strings.Builder
+	b.WriteString(`
+
+End the description with a "## How to test" section: a short numbered list of
+concrete steps a reviewer can follow to verify the changes, derived from the
+diff. Include the exact commands to run (e.g. the test command for the
+changed packages, or how to start the app and what to try) in backticks, and
+what the reviewer should see. Don't invent scripts or flags that the diff
+doesn't show exist.`)
+	if template {
+		b.WriteString(`
+If the template already has a testing section, fill it in with these steps
+instead of adding another one.`)
+	}
+	if len(testFiles) > 0 {
+		b.WriteString("\n\nThese test files were changed, mention running them:\n")
+		for _, f := range testFiles {
+			b.WriteString("- " + f + "\n")
+		}
+	} else {
+		b.WriteString("\n\nNo test files were changed, so describe how to check the behavior manually.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	defer cancel()
+// buildRiskInstructions asks for a risk rating in the PR description,
+// weighing in the changed files on hot paths
+func buildRiskInstructions(hotFiles []string, template bool) string {
+	var b strings.Builder
+	b.WriteString(`
+
+Add a "## Risk" section before any "How to test" section. Start it with
+"**Risk: low**", "**Risk: medium**" or "**Risk: high**", then give the reasons
+in one to three short bullets, based on the diff:
+- Database or data migrations, and whether they can be rolled back
+- Behavior changes users, API clients or other services will notice
+- Changes to critical or performance-sensitive code paths
+- Configuration, dependency or infrastructure changes
+- Whether the changes are covered by tests
+Rate refactors, docs and test-only changes low, and don't overstate the risk.`)
+	if template {
+		b.WriteString(`
+If the template already has a risk or impact section, fill it in instead of
+adding another one.`)
+	}
+	if len(hotFiles) > 0 {
+		b.WriteString("\n\nThese changed files are on paths the repository marks as critical:\n")
+		for _, f := range hotFiles {
+			b.WriteString("- " + f + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
 
-	resp, err := c.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: c.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: commitSystemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			Temperature: 0.3,
-			MaxTokens:   200,
-		},
-	)
+// temperature returns the sampling temperature for a generation, higher
+// when the previous result is regenerated
+func temperature(previous string) float32 {
+	if previous != "" {
+		return regenerateTemperature
+	}
+	return defaultTemperature
+}
 
-	if err != nil {
-		return "", formatAPIError(err)
+// buildRegenerateInstructions asks for a different result than previous,
+// following the user's hint, if any
+func buildRegenerateInstructions(previous, hint string) string {
+	var b strings.Builder
+	if previous != "" {
+		fmt.Fprintf(&b, "\n\nThe user asked for another version of this one, write a different one:\n---\n%s\n---", previous)
+	}
+	if hint = strings.TrimSpace(hint); hint != "" {
+		fmt.Fprintf(&b, "\n\nFollow this instruction from the user: %s", hint)
 	}
+	return b.String()
+}
 
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+// buildCommitTemplateInstructions asks the model to follow the commit
+// message template instead of writing a subject line only
+func buildCommitTemplateInstructions(template string) string {
+	return fmt.Sprintf(`
+
+The repository has a commit message template. Write the message following
+its structure, overriding the single-line rule:
+- Start with a subject line under 72 characters
+- Keep every section and label of the template (e.g. "Why:") in the same
+  order and fill each one in for these changes
+- Lines starting with "#" are guidance for you, follow them but leave them
+  out of the message
+
+Template:
+%s`, strings.TrimSpace(template))
+}
+
+// stripComments removes "#" comment lines from a message, as git does with
+// messages written from a template
+func stripComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
 	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
 
-	message := strings.TrimSpace(resp.Choices[0].Message.Content)
+// buildLabelPrompt creates the user prompt for label suggestion
+func buildLabelPrompt(available []string, commits, diff string) string {
+	return fmt.Sprintf(`Pick the labels that apply to the following changes.
 
-	// Remove any quotes if the model wrapped the message
-	message = strings.Trim(message, "\"'`")
+Available labels:
+%s
 
-	return message, nil
+Commits:
+%s
+
+Diff:
+%s`, strings.Join(available, "\n"), commits, diff)
 }
 
-// GeneratePRContent generates a PR title and description
-func (c *Client) GeneratePRContent(commits string, diff string) (*PRContent, error) {
-	// Truncate diff if too long
-	if len(diff) > maxDiffLength {
-		diff = diff[:maxDiffLength] + "\n\n[diff truncated due to length]"
+// buildDiagramPrompt creates the user prompt for the diagram of the
+// structural changes
+func buildDiagramPrompt(modules []string, commits, diff string) string {
+	return fmt.Sprintf(`Draw a diagram of the modules the following changes touch and how they
+relate.
+
+Changed modules:
+%s
+
+Commits:
+%s
+
+Diff:
+%s`, strings.Join(modules, "\n"), commits, diff)
+}
+
+// buildSplitPrompt creates the user prompt for splitting staged changes
+func buildSplitPrompt(files []string, diff string) string {
+	return fmt.Sprintf(`Group the following staged changes into logical commits.
+
+Files:
+%s
+
+Diff:
+%s`, strings.Join(files, "\n"), diff)
+}
+
+// buildBranchPrompt creates the user prompt for naming a branch
+func buildBranchPrompt(description, diff string, prefixes []string) string {
+	var b strings.Builder
+	b.WriteString("Suggest a branch name for the following work.\n\n")
+	if len(prefixes) > 0 {
+		fmt.Fprintf(&b, "Start the name with one of these prefixes: %s\n\n", strings.Join(prefixes, ", "))
+	} else {
+		b.WriteString("Do not use a prefix.\n\n")
 	}
+	if description != "" {
+		fmt.Fprintf(&b, "Description:\n%s\n", description)
+	} else {
+		fmt.Fprintf(&b, "Changes:\n%s\n", diff)
+	}
+	return b.String()
+}
 
-	prompt := buildPRPrompt(commits, diff)
+// maxBranchNameLength caps generated branch names, without the prefix
+const maxBranchNameLength = 50
+
+// parseBranchName turns the model's answer into a valid branch name: the
+// first line, lowercased, in kebab-case. A leading prefix is kept only when
+// it's one of prefixes.
+func parseBranchName(content string, prefixes []string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	line = strings.ToLower(strings.Trim(strings.TrimSpace(line), "\"'`"))
+
+	prefix := ""
+	for _, p := range prefixes {
+		if strings.HasPrefix(line, strings.ToLower(p)) {
+			prefix = p
+			line = line[len(p):]
+			break
+		}
+	}
+	if prefix == "" {
+		// Drop any other prefix the model made up
+		if i := strings.LastIndex(line, "/"); i >= 0 {
+			line = line[i+1:]
+		}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	defer cancel()
+	var b strings.Builder
+	dash := false
+	for _, r := range line {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
 
-	resp, err := c.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: c.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: prSystemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			Temperature: 0.3,
-			MaxTokens:   500,
-		},
-	)
+	name := b.String()
+	if len(name) > maxBranchNameLength {
+		name = name[:maxBranchNameLength]
+		// Cut at a word boundary when there is one
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			name = name[:i]
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	return prefix + name
+}
 
-	if err != nil {
-		return nil, formatAPIError(err)
+// parseCommitGroups parses "Commit: <message>" lines each followed by a
+// list of files. Unknown files and repeats are dropped, and files the model
+// left out are added to the last group so nothing staged is lost.
+func parseCommitGroups(content string, files []string) []CommitGroup {
+	known := make(map[string]bool, len(files))
+	for _, f := range files {
+		known[f] = true
 	}
 
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+	var groups []CommitGroup
+	assigned := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(line), "commit:") {
+			message := strings.TrimSpace(line[len("commit:"):])
+			groups = append(groups, CommitGroup{Message: strings.Trim(message, "\"'`")})
+			continue
+		}
+		if len(groups) == 0 {
+			continue
+		}
+
+		path := strings.Trim(strings.TrimLeft(line, "-* "), "\"'`")
+		if known[path] && !assigned[path] {
+			assigned[path] = true
+			last := &groups[len(groups)-1]
+			last.Files = append(last.Files, path)
+		}
+	}
+
+	// Groups without files or without a message can't become commits
+	valid := groups[:0]
+	for _, g := range groups {
+		if g.Message != "" && len(g.Files) > 0 {
+			valid = append(valid, g)
+		}
 	}
+	groups = valid
 
-	content := resp.Choices[0].Message.Content
-	return parsePRContent(content), nil
+	var missing []string
+	for _, f := range files {
+		if !assigned[f] {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) > 0 {
+		if len(groups) == 0 {
+			return nil
+		}
+		last := &groups[len(groups)-1]
+		last.Files = append(last.Files, missing...)
+	}
+
+	return groups
 }
 
-// buildCommitPrompt creates the user prompt for commit message generation
-func buildCommitPrompt(diff string) string {
-	return fmt.Sprintf(`Generate a commit message for the following changes:
+// parseLabels extracts label names from a comma or newline separated
+// response, keeping only names present in available (matched case-insensitively)
+func parseLabels(content string, available []string) []string {
+	known := make(map[string]string, len(available))
+	for _, name := range available {
+		known[strings.ToLower(name)] = name
+	}
 
-%s`, diff)
+	var labels []string
+	seen := make(map[string]bool)
+
+	fields := strings.FieldsFunc(content, func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		field = strings.TrimLeft(field, "-* ")
+		field = strings.Trim(field, "\"'`")
+
+		name, ok := known[strings.ToLower(field)]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		labels = append(labels, name)
+	}
+
+	return labels
 }
 
-// buildPRPrompt creates the user prompt for PR content generation
-func buildPRPrompt(commits, diff string) string {
-	return fmt.Sprintf(`Generate a PR title and description for the following changes.
+// markdownHeading matches a Markdown heading of level 1 to 3
+var markdownHeading = regexp.MustCompile(`^#{1,3}\s+(.+?)\s*#*\s*$`)
+
+// arrangeSections puts the sections of a description in the order of
+// sections, as "##" headings, adding the missing ones with "N/A". Other
+// sections, such as a requested test plan, follow them, and text before
+// the first heading stays on top.
+func arrangeSections(description string, sections []string) string {
+	var (
+		intro   []string
+		order   []string
+		titles  = make(map[string]string)
+		content = make(map[string][]string)
+		current = ""
+		inFence = false
+	)
+	for _, line := range strings.Split(description, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if m := markdownHeading.FindStringSubmatch(line); m != nil && !inFence {
+			title := strings.Trim(m[1], "*_: ")
+			current = strings.ToLower(title)
+			if _, ok := titles[current]; !ok {
+				order = append(order, current)
+				titles[current] = title
+			}
+			continue
+		}
+		if current == "" {
+			intro = append(intro, line)
+		} else {
+			content[current] = append(content[current], line)
+		}
+	}
 
-Commits:
-%s
+	var b strings.Builder
+	if text := strings.TrimSpace(strings.Join(intro, "\n")); text != "" {
+		b.WriteString(text + "\n\n")
+	}
+	write := func(heading string, lines []string) {
+		text := strings.TrimSpace(strings.Join(lines, "\n"))
+		if text == "" {
+			text = "N/A"
+		}
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", heading, text)
+	}
 
-Diff:
-%s`, commits, diff)
+	configured := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		key := strings.ToLower(section)
+		configured[key] = true
+		write(section, content[key])
+	}
+	for _, key := range order {
+		if !configured[key] {
+			write(titles[key], content[key])
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseDiagram extracts the Mermaid flowchart from the model's response,
+// without code fences. It returns "" if there is no flowchart.
+func parseDiagram(content string) string {
+	content = strings.TrimSpace(content)
+	if start := strings.Index(content, "```"); start >= 0 {
+		content = content[start+3:]
+		content = strings.TrimPrefix(content, "mermaid")
+		if end := strings.Index(content, "```"); end >= 0 {
+			content = content[:end]
+		}
+	}
+	content = strings.TrimSpace(content)
+
+	first, _, _ := strings.Cut(content, "\n")
+	if !strings.HasPrefix(first, "flowchart ") && !strings.HasPrefix(first, "graph ") {
+		return ""
+	}
+	return content
 }
 
 // parsePRContent parses the PR response into title and description
@@ -275,12 +1620,301 @@ Key changes:
 
 Note: Requires REDIS_URL environment variable for session storage.`
 
+const labelSystemPrompt = `You are a helpful assistant that labels GitHub Pull Requests.
+
+Rules:
+1. Only choose labels from the provided list of available labels
+2. Choose at most 3 labels, only the ones that clearly apply
+3. Return ONLY the label names, separated by commas
+4. Return nothing if no label applies`
+
+const diagramSystemPrompt = `You are a helpful assistant that draws Mermaid diagrams of code changes for Pull Request descriptions.
+
+Rules:
+1. Return ONLY a Mermaid flowchart starting with "flowchart LR" or
+   "flowchart TD", no explanation
+2. Nodes are the changed modules (packages, directories), and the unchanged
+   ones they clearly depend on in the diff; use at most 12 nodes
+3. Edges show dependencies or calls between modules that the diff shows,
+   labeled briefly when it helps (e.g. "calls", "imports")
+4. Mark new modules with ":::added" and removed ones with ":::removed", and
+   define both classes
+5. Quote node labels that contain "/", "." or spaces, e.g. git["internal/git"]
+6. Keep it small and readable, leave out tests and generated files`
+
+const splitSystemPrompt = `You are a helpful assistant that splits staged git changes into logical commits.
+
+Rules:
+1. Group files that belong to the same logical change; unrelated changes go
+   into separate commits
+2. Do not split changes that belong together, a single commit is fine
+3. Order the commits so each one builds on the previous ones
+4. Put every file in exactly one commit, using the paths exactly as listed
+5. Write each message in imperative mood, under 72 characters, without
+   prefixes like "feat:" or "fix:"
+6. Format your response as:
+   Commit: <message>
+   - <file>
+   - <file>
+
+   Commit: <message>
+   - <file>`
+
+const changelogSystemPrompt = `You are a helpful assistant that writes changelogs for software releases.
+
+Rules:
+1. Write one entry per user-visible change, merging related commits
+2. Leave out trivial commits: typo fixes, merges, refactors, tests, CI
+   and version bumps
+3. Label each entry with its kind: breaking (incompatible changes, also
+   commits marked with "!" or a BREAKING CHANGE footer), feature, fix or
+   other
+4. Write entries as short sentences in imperative mood for the reader of
+   the changelog, not for developers, e.g. "Add dark mode to the settings"
+5. Format every line as:
+   <kind>: <entry>`
+
+const notesSystemPrompt = `You are a helpful assistant that writes release notes for software projects.
+
+Rules:
+1. Open with one or two sentences on the highlights of the release
+2. Follow with Markdown sections (### Highlights, ### Fixes, ### Breaking
+   Changes, ### Other Changes), leaving out empty ones
+3. Describe what changed for users, merging related commits and leaving
+   out trivial ones such as typo fixes, merges, tests and version bumps
+4. Do not mention commit hashes
+5. Return ONLY the release notes`
+
+const announcementSystemPrompt = `You are a helpful assistant that announces software releases.
+
+Rules:
+1. Write a single friendly paragraph of 3 to 5 sentences, suitable for a
+   blog post, mailing list or chat channel
+2. Lead with the most important changes for users, and mention breaking
+   changes plainly
+3. Leave out internal and trivial changes, and do not mention commit hashes
+4. Use plain text, no headings or bullet lists
+5. Return ONLY the paragraph`
+
+const explainCommitSystemPrompt = `You are a helpful assistant that explains git commits to developers exploring a codebase's history.
+
+Rules:
+1. Start with one or two sentences on what the commit does overall
+2. Then explain the notable changes and how they fit together
+3. Say why the change might matter: the problem it likely solved, behavior
+   it changed, and risks or follow-ups it suggests; mark guesses as such
+4. Refer to files, functions and types by name, but do not repeat the diff
+5. Use plain prose with short paragraphs, at most about 200 words`
+
+const whySystemPrompt = `You are a helpful assistant that explains to a developer why a piece of code is the way it is, using the commits that introduced it.
+
+Rules:
+1. Start with one or two sentences on what the lines do
+2. Then explain the likely intent behind them: the problem the commit
+   solved, the constraint or bug it worked around, or the feature it was
+   part of, quoting the commit message where it says so
+3. With several commits, explain how the lines evolved
+4. Say what to keep in mind before changing the lines, if anything
+5. Base everything on the commits given and mark guesses as such
+6. Use plain prose with short paragraphs, at most about 200 words`
+
+const explainChangesSystemPrompt = `You are a helpful assistant that explains a developer's uncommitted changes before they commit them.
+
+Rules:
+1. Start with one or two sentences on what the changes do overall
+2. Then walk through the notable changes and how they fit together,
+   referring to files, functions and types by name
+3. Point out unrelated changes that could go into separate commits, and
+   leftovers such as debug output, commented-out code or TODOs
+4. Do not write a commit message and do not repeat the diff
+5. Use plain prose with short paragraphs, at most about 200 words`
+
+const reviewSystemPrompt = `You are an experienced software engineer reviewing a pull request.
+
+Rules:
+1. Report only real problems: potential bugs (logic errors, unhandled
+   errors, races, leaks, security issues), missing tests for new or
+   changed behavior, and risky changes (breaking changes, migrations,
+   changed defaults, performance traps)
+2. Do not report style, naming or formatting nits
+3. Lines of the diff are prefixed with their line number in the new file;
+   point at the most relevant line
+4. Keep each message to one or two sentences, saying what is wrong and
+   how to fix it
+5. Format every finding on its own line as:
+   <bug|test|risk> <path>:<line>: <message>
+   Use <path>: <message> for findings about a file as a whole
+6. Return nothing if there are no problems worth reporting`
+
+const standupSystemPrompt = `You are a helpful assistant that writes a developer's daily standup update from their recent commits.
+
+Rules:
+1. Use exactly two sections, "What I did:" and "What's next:", each a short
+   list of "- " bullets
+2. Under "What I did:", group related commits into 2 to 5 bullets that
+   describe outcomes, not individual commits or hashes
+3. Under "What's next:", infer 1 to 3 likely next steps from unfinished
+   work, such as feature branches that are not merged yet, WIP commits or
+   follow-ups the commits suggest; keep them tentative
+4. Write in the first person but drop the leading "I", e.g. "Added retries to the
+   uploader", and keep each bullet to one line
+5. Output only the two sections, no greeting or other text`
+
+const reportSystemPrompt = `You are a helpful assistant that writes a team's activity report for a period, to be pasted into Slack or a status document.
+
+Rules:
+1. Start with a title line "Summary for <period>" followed by one or two
+   sentences on the overall theme of the period
+2. Then use these sections, each a short list of "- " bullets, leaving out
+   empty ones:
+   "Shipped:" for merged pull requests, grouped by theme, with their
+   numbers like (#123)
+   "In progress:" for work on branches that is not merged yet
+   "Maintenance:" for fixes, dependency updates, refactoring and tooling
+3. Describe outcomes for readers outside the team; do not list commits
+   one by one or mention hashes
+4. Mention who did what only when several people contributed
+5. Keep each bullet to one line and the whole report under about 250 words
+6. Use plain text with "- " bullets only, no Markdown headings, bold text
+   or tables, so it pastes cleanly anywhere`
+
+const pulseSystemPrompt = `You are a helpful assistant that sums up the state of a software repository from its recent activity.
+
+Rules:
+1. Write a single paragraph of 3 to 5 sentences in plain text
+2. Say what the team is focused on, judging by the themes of the commits
+   and the active branches
+3. Comment on the pace (speeding up, steady or slowing down) and on how
+   the work is spread across contributors, using the numbers given
+4. Point out anything worth attention, such as many open pull requests,
+   a single contributor carrying most of the work or long-running branches
+5. Do not use headings, bullet lists or commit hashes`
+
+const churnSystemPrompt = `You are an experienced software engineer analyzing which files of a codebase change most often.
+
+Rules:
+1. Files that are both large and changed often are the likeliest refactor
+   candidates; files marked as refactor candidates deserve the most
+   attention
+2. For the notable files, suggest in one or two sentences why they may
+   change so often (e.g. a god object, central configuration, a shared
+   registry, generated code or active feature work) and what could help,
+   such as splitting them up or moving data out of code
+3. Say when high churn looks healthy, e.g. changelogs, lockfiles or tests
+   growing alongside features
+4. Judge only from the paths and numbers given and mark guesses as such
+5. Use plain text with "- " bullets, at most about 200 words`
+
+const resolveSystemPrompt = `You are an experienced software engineer resolving a git merge conflict.
+
+Rules:
+1. Combine the intent of both sides: keep the changes of each side unless
+   they contradict, and use the common ancestor, when given, to tell what
+   each side changed
+2. When the sides truly contradict, prefer the one that fits the
+   surrounding lines and say so
+3. Return only the lines that replace the conflicted region, without
+   conflict markers and without repeating the lines before or after it
+4. Keep the file's indentation, style and line endings
+5. Format your response as:
+   Reason: <one or two sentences on how the sides were combined>
+   Resolution:
+   <the resolved lines>`
+
+const revertSystemPrompt = `You are a helpful assistant that writes git commit messages for reverts.
+
+Rules:
+1. Start with a subject line under 72 characters in imperative mood that
+   starts with "Revert" and says in plain words what is undone, e.g.
+   "Revert retry backoff in the upload client"
+2. After a blank line, explain in 2 to 4 sentences what the reverted
+   commit did and what behavior returns with the revert
+3. Then state the reason for the revert as given; without a reason, do
+   not make one up
+4. Do not mention the reverted commit's hash, it is added separately
+5. Return ONLY the commit message, without quotes`
+
+const summarizeSystemPrompt = `You are a helpful assistant that summarizes the work on a git branch for tickets and status updates.
+
+Rules:
+1. Write a single paragraph of 3 to 5 sentences in plain text
+2. Say what the branch achieves and why, then the main changes
+3. Write for teammates and managers, not only for developers: describe
+   behavior and impact rather than code details
+4. Do not use headings, bullet lists or commit hashes
+5. Return ONLY the paragraph`
+
+const bumpSystemPrompt = `You are a helpful assistant that recommends semantic version bumps.
+
+Rules:
+1. Recommend major for incompatible changes to the public API or behavior
+   (including commits marked with "!" or a BREAKING CHANGE footer), minor
+   for new backwards-compatible functionality, and patch for fixes only
+2. Pick the highest level any single commit requires
+3. Explain which commits drove the decision in 1 to 3 short bullets
+4. Format your response as:
+   Bump: <major|minor|patch>
+   - <reason>`
+
+const tagSystemPrompt = `You are a helpful assistant that writes release notes for annotated git tags.
+
+Rules:
+1. Start with a one-line summary of the release, under 72 characters
+2. Follow with a blank line and a bulleted list of the notable changes,
+   grouped by kind (features, fixes, other) when there are many
+3. Merge related commits into one bullet and leave out trivial ones such
+   as typo fixes, merges and version bumps
+4. Use plain text, no Markdown headings
+5. Return ONLY the tag message
+
+Example:
+Add SSH commit signing and commit hooks
+
+- Sign commits with SSH keys when commit.gpgsign is set
+- Run pre-commit and commit-msg hooks for every commit
+- Fix empty commits failing on the go-git backend`
+
+const stashSystemPrompt = `You are a helpful assistant that describes unfinished work for git stash.
+
+Rules:
+1. Write a single line under 60 characters
+2. Describe what the work in progress is about, in present participle or
+   imperative mood, e.g. "migrating auth middleware to context"
+3. Be specific, mention the feature or component being changed
+4. Return ONLY the description, without quotes or explanations`
+
+const branchSystemPrompt = `You are a helpful assistant that names git branches.
+
+Rules:
+1. Use lowercase kebab-case, e.g. feat/add-user-login
+2. Keep it short: 2 to 5 words describing the change
+3. Pick the prefix that fits the kind of change, if prefixes are given
+4. Return ONLY the branch name, without quotes or explanations`
+
+// APIError is a failed OpenAI API request, with a user-friendly message
+type APIError struct {
+	// Auth is set when the API key was rejected
+	Auth bool
+	err  error
+}
+
+func (e *APIError) Error() string { return e.err.Error() }
+func (e *APIError) Unwrap() error { return e.err }
+
 // formatAPIError converts OpenAI API errors into user-friendly messages
 func formatAPIError(err error) error {
 	if err == nil {
 		return nil
 	}
+	errStr := err.Error()
+	return &APIError{
+		Auth: strings.Contains(errStr, "401") || strings.Contains(errStr, "invalid_api_key"),
+		err:  describeAPIError(err),
+	}
+}
 
+// describeAPIError explains an OpenAI API error and how to fix it
+func describeAPIError(err error) error {
 	errStr := err.Error()
 
 	// Check for network errors
@@ -1,8 +1,12 @@
 package llm
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/user/vibe/internal/changelog"
 )
 
 func TestParsePRContent(t *testing.T) {
@@ -89,6 +93,19 @@ func TestBuildCommitPrompt(t *testing.T) {
 	}
 }
 
+func TestBuildEmptyCommitPrompt(t *testing.T) {
+	got := buildEmptyCommitPrompt("feature/auth", []string{"Add login", "Add logout"})
+
+	for _, want := range []string{"empty commit", "Branch: feature/auth", "Add login\nAdd logout"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildEmptyCommitPrompt() missing %q in %q", want, got)
+		}
+	}
+	if got := buildEmptyCommitPrompt("", nil); !strings.Contains(got, "(detached HEAD)") {
+		t.Errorf("buildEmptyCommitPrompt() without branch = %q", got)
+	}
+}
+
 func TestBuildPRPrompt(t *testing.T) {
 	commits := "abc123 First commit\ndef456 Second commit"
 	diff := "diff --git a/file.go b/file.go\n+new line"
@@ -141,3 +158,597 @@ func TestParseDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateDiff(t *testing.T) {
+	short := "diff --git a/file.go b/file.go\n+new line"
+	got, truncated := TruncateDiff(short)
+	if truncated || got != short {
+		t.Errorf("TruncateDiff() should leave short diffs unchanged")
+	}
+
+	long := strings.Repeat("+line\n", maxDiffLength)
+	got, truncated = TruncateDiff(long)
+	if !truncated {
+		t.Errorf("TruncateDiff() should report truncation of long diffs")
+	}
+	if !strings.HasSuffix(got, "[diff truncated due to length]") {
+		t.Errorf("TruncateDiff() should mark truncated diffs")
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	available := []string{"bug", "enhancement", "documentation", "good first issue"}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "Comma separated",
+			content: "bug, documentation",
+			want:    []string{"bug", "documentation"},
+		},
+		{
+			name:    "Bulleted list with different case",
+			content: "- Enhancement\n- Good First Issue",
+			want:    []string{"enhancement", "good first issue"},
+		},
+		{
+			name:    "Unknown labels are dropped",
+			content: "bug, security, bug",
+			want:    []string{"bug"},
+		},
+		{
+			name:    "Empty response",
+			content: "",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLabels(tt.content, available)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("parseLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCommitTemplateInstructions(t *testing.T) {
+	got := buildCommitTemplateInstructions("\n# Explain why\nWhy:\n\nRefs:\n")
+
+	if !strings.Contains(got, "# Explain why\nWhy:\n\nRefs:") {
+		t.Errorf("buildCommitTemplateInstructions() should include the template, got %q", got)
+	}
+	if !strings.Contains(got, "72 characters") {
+		t.Errorf("buildCommitTemplateInstructions() should keep the subject rule")
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	got := stripComments("Add retries\n\n# Explain why\nWhy: requests fail under load\n#\n")
+	want := "Add retries\n\nWhy: requests fail under load"
+	if got != want {
+		t.Errorf("stripComments() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildTemplateInstructions(t *testing.T) {
+	template := "## Summary\n\n<!-- What does this PR do? -->\n\n## Checklist\n- [ ] Tests added\n"
+	got := buildTemplateInstructions(template)
+
+	if !strings.Contains(got, "## Summary") || !strings.Contains(got, "- [ ] Tests added") {
+		t.Errorf("buildTemplateInstructions() should include the template, got %q", got)
+	}
+	if !strings.Contains(got, "heading") {
+		t.Errorf("buildTemplateInstructions() should ask to preserve headings")
+	}
+}
+
+func TestArrangeSections(t *testing.T) {
+	sections := []string{"Summary", "Changes", "Rollback plan"}
+
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "Reordered, renamed level and missing section",
+			description: "### Changes\n- Add retries\n\n# summary\nRetries failed uploads.",
+			want:        "## Summary\n\nRetries failed uploads.\n\n## Changes\n\n- Add retries\n\n## Rollback plan\n\nN/A",
+		},
+		{
+			name:        "Extra sections follow, code comments are not headings",
+			description: "Intro\n## Summary\nDone.\n## How to test\n```sh\n# run the tests\ngo test ./...\n```",
+			want:        "Intro\n\n## Summary\n\nDone.\n\n## Changes\n\nN/A\n\n## Rollback plan\n\nN/A\n\n## How to test\n\n```sh\n# run the tests\ngo test ./...\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := arrangeSections(tt.description, sections); got != tt.want {
+				t.Errorf("arrangeSections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiagram(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Fenced",
+			content: "Here is the diagram:\n```mermaid\nflowchart LR\n  cmd --> git\n```\n",
+			want:    "flowchart LR\n  cmd --> git",
+		},
+		{
+			name:    "Bare",
+			content: "graph TD\n  a --> b",
+			want:    "graph TD\n  a --> b",
+		},
+		{
+			name:    "Not a flowchart",
+			content: "```mermaid\nsequenceDiagram\n  a->>b: hi\n```",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDiagram(tt.content); got != tt.want {
+				t.Errorf("parseDiagram() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTestPlanInstructions(t *testing.T) {
+	got := buildTestPlanInstructions([]string{"internal/git/stat_test.go"}, false)
+	if !strings.Contains(got, "## How to test") || !strings.Contains(got, "- internal/git/stat_test.go") {
+		t.Errorf("buildTestPlanInstructions() should ask for the section and list the test files, got %q", got)
+	}
+	if strings.Contains(got, "template") {
+		t.Errorf("buildTestPlanInstructions() should not mention a template when there is none")
+	}
+
+	got = buildTestPlanInstructions(nil, true)
+	if !strings.Contains(got, "testing section") || !strings.Contains(got, "manually") {
+		t.Errorf("buildTestPlanInstructions() should reuse the template's section and ask for manual steps, got %q", got)
+	}
+}
+
+func TestBuildRiskInstructions(t *testing.T) {
+	got := buildRiskInstructions([]string{"internal/billing/charge.go"}, false)
+	if !strings.Contains(got, "## Risk") || !strings.Contains(got, "**Risk: high**") {
+		t.Errorf("buildRiskInstructions() should ask for a rated section, got %q", got)
+	}
+	if !strings.Contains(got, "- internal/billing/charge.go") {
+		t.Errorf("buildRiskInstructions() should list the files on hot paths, got %q", got)
+	}
+
+	got = buildRiskInstructions(nil, true)
+	if !strings.Contains(got, "risk or impact section") || strings.Contains(got, "critical:") {
+		t.Errorf("buildRiskInstructions() should reuse the template's section and list no files, got %q", got)
+	}
+}
+
+func TestBuildRegenerateInstructions(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		hint     string
+		want     []string
+		notWant  []string
+	}{
+		{name: "First generation", notWant: []string{"another version", "instruction"}},
+		{name: "Previous only", previous: "Fix login", want: []string{"another version", "---\nFix login\n---"}, notWant: []string{"instruction"}},
+		{name: "Hint only", hint: " mention the issue ", want: []string{"instruction from the user: mention the issue"}, notWant: []string{"another version"}},
+		{name: "Both", previous: "Fix login", hint: "shorter", want: []string{"Fix login", "shorter"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildRegenerateInstructions(tt.previous, tt.hint)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("buildRegenerateInstructions() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("buildRegenerateInstructions() = %q, should not contain %q", got, w)
+				}
+			}
+		})
+	}
+
+	if temperature("") != defaultTemperature || temperature("Fix login") != regenerateTemperature {
+		t.Errorf("temperature() should only be raised when regenerating")
+	}
+}
+
+func TestParseCommitGroups(t *testing.T) {
+	files := []string{"api/auth.go", "api/auth_test.go", "README.md", "go.sum"}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []CommitGroup
+	}{
+		{
+			name:    "Two groups, missing file goes last",
+			content: "Commit: Add token refresh\n- api/auth.go\n- `api/auth_test.go`\n\nCommit: \"Document token refresh\"\n- README.md\n",
+			want: []CommitGroup{
+				{Message: "Add token refresh", Files: []string{"api/auth.go", "api/auth_test.go"}},
+				{Message: "Document token refresh", Files: []string{"README.md", "go.sum"}},
+			},
+		},
+		{
+			name:    "Unknown files, repeats and empty groups are dropped",
+			content: "commit: Add token refresh\n- api/auth.go\n- main.go\nCommit: Empty\n- api/auth.go\nCommit: Rest\n* README.md",
+			want: []CommitGroup{
+				{Message: "Add token refresh", Files: []string{"api/auth.go"}},
+				{Message: "Rest", Files: []string{"README.md", "api/auth_test.go", "go.sum"}},
+			},
+		},
+		{
+			name:    "No groups",
+			content: "These changes belong together.",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCommitGroups(tt.content, files)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parseCommitGroups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBranchName(t *testing.T) {
+	prefixes := []string{"feat/", "fix/"}
+
+	tests := []struct {
+		name     string
+		content  string
+		prefixes []string
+		want     string
+	}{
+		{"Plain name", "feat/add-user-login", prefixes, "feat/add-user-login"},
+		{"Quoted with explanation", "`fix/Null Pointer in parser`\nBecause the parser crashes", prefixes, "fix/null-pointer-in-parser"},
+		{"Unknown prefix is dropped", "hotfix/retry_uploads", prefixes, "retry-uploads"},
+		{"No prefixes allowed", "feat/add-login", nil, "add-login"},
+		{"Repeated separators collapse", "feat/--add   OAuth2 -- support!", prefixes, "feat/add-oauth2-support"},
+		{"Long names are cut at a word", "feat/" + strings.Repeat("word-", 20), prefixes, "feat/" + strings.TrimSuffix(strings.Repeat("word-", 9), "-") + "-word"},
+		{"Nothing usable", "feat/!!!", prefixes, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBranchName(tt.content, tt.prefixes); got != tt.want {
+				t.Errorf("parseBranchName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStashMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"Plain description", "migrating auth middleware to context", "WIP: migrating auth middleware to context"},
+		{"Prefix is not repeated", "WIP: migrating auth middleware", "WIP: migrating auth middleware"},
+		{"Lowercase prefix", "wip: retry uploads", "WIP: retry uploads"},
+		{"Quoted with explanation", "\"Add rate limiting\"\nThe diff adds a limiter.", "WIP: Add rate limiting"},
+		{"Empty", "  ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStashMessage(tt.content); got != tt.want {
+				t.Errorf("parseStashMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTagPrompt(t *testing.T) {
+	prompt := buildTagPrompt("v1.4.0", "v1.3.0", []string{"a1b2c3d Add stash", "e4f5a6b Fix tag push"})
+	for _, want := range []string{"annotated tag v1.4.0", "Commits since v1.3.0:", "e4f5a6b Fix tag push"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildTagPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+
+	if prompt := buildTagPrompt("v0.1.0", "", nil); !strings.Contains(prompt, "since the start of the project") {
+		t.Errorf("buildTagPrompt() for a first tag = %q, want the whole history", prompt)
+	}
+}
+
+func TestParseChangelogEntries(t *testing.T) {
+	content := "feature: Add vibe stash\n- fix: \"Fix tag pushes over SSH\"\nBREAKING: Drop the --draft flag\n" +
+		"chore: Bump dependencies\nSome explanation\nfeature: Add vibe stash\nother:\n"
+
+	want := []changelog.Entry{
+		{Kind: changelog.Feature, Text: "Add vibe stash"},
+		{Kind: changelog.Fix, Text: "Fix tag pushes over SSH"},
+		{Kind: changelog.Breaking, Text: "Drop the --draft flag"},
+	}
+	if got := parseChangelogEntries(content); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("parseChangelogEntries() = %v, want %v", got, want)
+	}
+}
+
+func TestParseBumpSuggestion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *BumpSuggestion
+	}{
+		{
+			name:    "Level and reasons",
+			content: "Bump: minor\n- a1b2c3d adds vibe stash\n- the rest are fixes",
+			want:    &BumpSuggestion{Level: changelog.Minor, Reason: "- a1b2c3d adds vibe stash\n- the rest are fixes"},
+		},
+		{
+			name:    "Formatted level",
+			content: "**Bump**: **MAJOR**\nThe --draft flag was removed.",
+			want:    &BumpSuggestion{Level: changelog.Major, Reason: "The --draft flag was removed."},
+		},
+		{
+			name:    "Level with punctuation",
+			content: "Bump: Patch.\nOnly fixes.",
+			want:    &BumpSuggestion{Level: changelog.Patch, Reason: "Only fixes."},
+		},
+		{
+			name:    "Unknown level",
+			content: "Bump: huge\n- everything changed",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseBumpSuggestion(tt.content)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parseBumpSuggestion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildNotesPrompt(t *testing.T) {
+	prompt := buildNotesPrompt("v1.2.0..v1.3.0", []string{"a1b2c3d Add stash"})
+	for _, want := range []string{"changes in v1.2.0..v1.3.0", "a1b2c3d Add stash"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildNotesPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+
+	long := []string{strings.Repeat("x", maxDiffLength+10)}
+	if prompt := buildNotesPrompt("v1..v2", long); !strings.Contains(prompt, "[commit list truncated due to length]") {
+		t.Error("buildNotesPrompt() with a long commit list was not truncated")
+	}
+}
+
+func TestBuildStandupPrompt(t *testing.T) {
+	prompt := buildStandupPrompt([]string{"[feat/retry] a1b2c3d Add retries"}, "feat/retry")
+	for _, want := range []string{"on the branch feat/retry", "[feat/retry] a1b2c3d Add retries"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildStandupPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+}
+
+func TestBuildReportPrompt(t *testing.T) {
+	prompt := buildReportPrompt("May 1 to May 8", []string{"[main] a1b2c3d Add retries (Ann)"}, []string{"#12 Add retries (@ann)"})
+	for _, want := range []string{"report for May 1 to May 8", "#12 Add retries (@ann)", "[main] a1b2c3d Add retries (Ann)"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildReportPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+
+	if prompt := buildReportPrompt("May", nil, nil); !strings.Contains(prompt, "Merged pull requests:\n(none)") {
+		t.Errorf("buildReportPrompt() without PRs = %q, want (none)", prompt)
+	}
+
+	long := []string{strings.Repeat("x", maxDiffLength+10)}
+	if prompt := buildReportPrompt("May", long, []string{"#12 Add retries"}); !strings.Contains(prompt, "[commit list truncated due to length]") {
+		t.Error("buildReportPrompt() with a long commit list was not truncated")
+	}
+}
+
+func TestBuildPulsePrompt(t *testing.T) {
+	prompt := buildPulsePrompt("Open pull requests: 3", []string{"a1b2c3d Add retries"})
+	for _, want := range []string{"Open pull requests: 3", "a1b2c3d Add retries"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildPulsePrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+}
+
+func TestBuildChurnPrompt(t *testing.T) {
+	prompt := buildChurnPrompt([]string{"cmd/root.go: 12 commits", "go.mod: 4 commits"})
+	if !strings.Contains(prompt, "cmd/root.go: 12 commits\ngo.mod: 4 commits") {
+		t.Errorf("buildChurnPrompt() = %q, want the files one per line", prompt)
+	}
+}
+
+func TestBuildWhyPrompt(t *testing.T) {
+	commits := []LineCommit{
+		{Hash: "a1b2c3d", Author: "Ann", Date: "2024-05-01", Message: "Retry uploads", Diff: strings.Repeat("x", maxDiffLength)},
+		{Hash: "e4f5a6b", Author: "Bob", Date: "2024-05-02", Message: "Cap retries", Diff: "+retries := 3"},
+	}
+	prompt := buildWhyPrompt("upload.go", "12  retries := 3", commits)
+	for _, want := range []string{"lines of upload.go", "12  retries := 3", "Commit a1b2c3d by Ann on 2024-05-01:\nRetry uploads", "[diff truncated due to length]", "+retries := 3"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildWhyPrompt() = %.200q..., want it to contain %q", prompt, want)
+		}
+	}
+	if len(prompt) > maxDiffLength+1000 {
+		t.Errorf("buildWhyPrompt() is %d bytes long, want the diffs to share the limit", len(prompt))
+	}
+}
+
+func TestParseReviewFindings(t *testing.T) {
+	content := "Here is what I found:\n" +
+		"bug internal/git/tag.go:42: The error from ResolveRevision is ignored.\n" +
+		"- TEST `cmd/tag.go`: No tests cover the --push flag.\n" +
+		"risk go.mod:7: Upgrading go-git changes push behavior.\n" +
+		"nit main.go:3: Rename x.\n"
+
+	want := []ReviewFinding{
+		{Kind: FindingBug, Path: "internal/git/tag.go", Line: 42, Message: "The error from ResolveRevision is ignored."},
+		{Kind: FindingTest, Path: "cmd/tag.go", Message: "No tests cover the --push flag."},
+		{Kind: FindingRisk, Path: "go.mod", Line: 7, Message: "Upgrading go-git changes push behavior."},
+	}
+	if got := parseReviewFindings(content); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("parseReviewFindings() = %+v, want %+v", got, want)
+	}
+
+	if got := parseReviewFindings("No problems found."); got != nil {
+		t.Errorf("parseReviewFindings() = %+v, want none", got)
+	}
+}
+
+func TestBuildResolvePrompt(t *testing.T) {
+	conflict := Conflict{
+		Path: "retry.go", Before: "func retry() {", After: "}",
+		OursLabel: "HEAD", TheirsLabel: "feat/backoff",
+		Ours: "\tattempts := 3", Base: "\tattempts := 1", Theirs: "\tattempts := 1\n\tbackoff := time.Second",
+	}
+	prompt := buildResolvePrompt(conflict)
+	for _, want := range []string{"conflict in retry.go", "before the conflict:\nfunc retry() {", "Ours (HEAD):\n\tattempts := 3", "Common ancestor:\n\tattempts := 1", "Theirs (feat/backoff):", "after the conflict:\n}"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildResolvePrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+
+	conflict.Base = ""
+	if prompt := buildResolvePrompt(conflict); strings.Contains(prompt, "Common ancestor") {
+		t.Errorf("buildResolvePrompt() without a base = %q, want no ancestor section", prompt)
+	}
+}
+
+func TestParseResolution(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *ConflictResolution
+	}{
+		{
+			name:    "Reason and code",
+			content: "Reason: Keeps the higher attempt count and the backoff.\nResolution:\n\tattempts := 3\n\tbackoff := time.Second",
+			want:    &ConflictResolution{Code: "\tattempts := 3\n\tbackoff := time.Second", Reason: "Keeps the higher attempt count and the backoff."},
+		},
+		{
+			name:    "Fenced code",
+			content: "**Reason**: Both imports are needed.\n\n**Resolution**:\n```go\nimport \"os\"\nimport \"time\"\n```\n",
+			want:    &ConflictResolution{Code: "import \"os\"\nimport \"time\"", Reason: "Both imports are needed."},
+		},
+		{
+			name:    "Empty resolution",
+			content: "Reason: Both sides removed the function.\nResolution:\n",
+			want:    &ConflictResolution{Code: "", Reason: "Both sides removed the function."},
+		},
+		{
+			name:    "No resolution",
+			content: "Reason: I am not sure.\nattempts := 3",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseResolution(tt.content)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parseResolution() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSquashPrompt(t *testing.T) {
+	prompt := buildSquashPrompt([]string{"e4f5a6b Fix typo", "a1b2c3d Add retries"}, "+retries := 3")
+	for _, want := range []string{"squashed into one", "e4f5a6b Fix typo\na1b2c3d Add retries", "Combined changes:\n+retries := 3"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildSquashPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+
+	long := []string{strings.Repeat("x", maxDiffLength)}
+	if prompt := buildSquashPrompt(long, ""); !strings.Contains(prompt, "[commit list truncated due to length]") {
+		t.Error("buildSquashPrompt() with a long commit list was not truncated")
+	}
+}
+
+func TestBuildRewordPrompt(t *testing.T) {
+	prompt := buildRewordPrompt("wip (#12)", "+retries := 3")
+	for _, want := range []string{"Current message:\nwip (#12)", "Changes:\n+retries := 3"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildRewordPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+}
+
+func TestBuildCherryPickPrompt(t *testing.T) {
+	prompt := buildCherryPickPrompt("Fix retry loop", "release/1.2", []string{"a1b2c3d Bump version"}, "+retries := 3")
+	for _, want := range []string{"onto the branch release/1.2", "Original message:\nFix retry loop", "Recent commits on release/1.2:\na1b2c3d Bump version", "Changes as applied:\n+retries := 3"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildCherryPickPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+}
+
+func TestBuildRevertPrompt(t *testing.T) {
+	prompt := buildRevertPrompt("Add retry backoff", "It doubled upload times", "+backoff := time.Second")
+	for _, want := range []string{"Reason for the revert:\nIt doubled upload times", "Reverted commit message:\nAdd retry backoff", "+backoff := time.Second"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("buildRevertPrompt() = %q, want it to contain %q", prompt, want)
+		}
+	}
+
+	if prompt := buildRevertPrompt("Add retry backoff", "", ""); !strings.Contains(prompt, "do not guess one") {
+		t.Errorf("buildRevertPrompt() without a reason = %q, want no reason guessed", prompt)
+	}
+}
+
+func TestFormatAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantAuth bool
+		wantMsg  string
+	}{
+		{"Invalid key", errors.New("error, status code: 401, message: Incorrect API key provided"), true, "invalid OpenAI API key"},
+		{"Rate limit", errors.New("error, status code: 429, message: Rate limit reached"), false, "rate limit exceeded"},
+		{"Other", errors.New("boom"), false, "OpenAI API error: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr *APIError
+			err := fmt.Errorf("failed to generate: %w", formatAPIError(tt.err))
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("formatAPIError() = %T, want *APIError", err)
+			}
+			if apiErr.Auth != tt.wantAuth {
+				t.Errorf("Auth = %v, want %v", apiErr.Auth, tt.wantAuth)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Error() = %q, want it to contain %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMultiHandler(t *testing.T) {
+	var warnings, all bytes.Buffer
+	logger := slog.New(multiHandler{
+		newTerminalHandler(&warnings, slog.LevelWarn),
+		slog.NewTextHandler(&all, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	})
+
+	logger.Debug("git", "args", "status")
+	logger.With("host", "github.com").Warn("rate limited")
+
+	if strings.Contains(warnings.String(), "args=") || !strings.Contains(warnings.String(), "rate limited") {
+		t.Errorf("terminal handler got %q, want the warning only", warnings.String())
+	}
+	if strings.Contains(warnings.String(), "time=") {
+		t.Errorf("terminal handler should leave out timestamps, got %q", warnings.String())
+	}
+	if !strings.Contains(all.String(), "args=status") || !strings.Contains(all.String(), "host=github.com") {
+		t.Errorf("debug handler got %q, want both records", all.String())
+	}
+	if !logger.Handler().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled() should be true when any handler is enabled")
+	}
+}
+
+func TestSetupFile(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	file := filepath.Join(t.TempDir(), "vibe.log")
+	closeFile, err := Setup(slog.LevelError, file)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	slog.Debug("openai request", "model", "gpt-4o")
+	if err := closeFile(); err != nil {
+		t.Fatalf("close error = %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"model":"gpt-4o"`) {
+		t.Errorf("log file = %q, want the debug record as JSON", data)
+	}
+}
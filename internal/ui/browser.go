@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// OpenBrowser opens url in the user's default browser. The BROWSER
+// environment variable takes precedence over the platform default.
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+
+	switch {
+	case os.Getenv("BROWSER") != "":
+		cmd = exec.Command(os.Getenv("BROWSER"), url)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf(i18n.T("failed to open browser: %w"), err)
+	}
+
+	// Don't wait for the browser, just reap the process when it exits
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
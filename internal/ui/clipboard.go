@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/atotto/clipboard"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// CopyToClipboard places text on the system clipboard
+func CopyToClipboard(text string) error {
+	if clipboard.Unsupported {
+		return errors.New(i18n.T("no clipboard utility available (install xclip, xsel or wl-clipboard)"))
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		return fmt.Errorf(i18n.T("failed to copy to clipboard: %w"), err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// ANSI colors of the output
+const (
+	green = "2"
+	red   = "1"
+	cyan  = "6"
+)
+
+// Colors are only used on terminals and without NO_COLOR, which stdout and
+// stderr detect separately since either may be redirected. Prompts render
+// with the stdout renderer too.
+var (
+	stdoutRenderer = lipgloss.DefaultRenderer()
+	stderrRenderer = lipgloss.NewRenderer(os.Stderr)
+)
+
+// SetNoColor disables colors everywhere, prompts included
+func SetNoColor(noColor bool) {
+	if noColor {
+		stdoutRenderer.SetColorProfile(termenv.Ascii)
+		stderrRenderer.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// paint colors s for the output r renders, if it supports colors
+func paint(r *lipgloss.Renderer, color, s string) string {
+	return termenv.String(s).Foreground(r.ColorProfile().Color(color)).String()
+}
+
+// Added colors added lines or counts for stdout
+func Added(s string) string {
+	return paint(stdoutRenderer, green, s)
+}
+
+// Removed colors removed lines or counts for stdout
+func Removed(s string) string {
+	return paint(stdoutRenderer, red, s)
+}
+
+// ErrorLine formats err for stderr, with a colored i18n.T("Error:") label
+func ErrorLine(err error) string {
+	return paint(stderrRenderer, red, i18n.T("Error:")) + " " + err.Error()
+}
+
+// colorDiff colors the added and removed lines and the hunk headers of a
+// unified diff for stdout
+func colorDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			lines[i] = Added(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = Removed(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = paint(stdoutRenderer, cyan, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// editorCommand returns the editor from VISUAL or EDITOR, falling back to
+// vi like git (notepad on Windows)
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// EditInEditor opens content in the user's editor on a temp file and
+// returns the saved content. pattern names the temp file as for
+// os.CreateTemp, so that its extension can pick the editor's syntax.
+func EditInEditor(content, pattern string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to create temp file: %w"), err)
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to write temp file: %w"), err)
+	}
+
+	// Like git, run the editor through the shell so it can have arguments
+	editor := editorCommand()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		args := strings.Fields(editor)
+		cmd = exec.Command(args[0], append(args[1:], path)...)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf(i18n.T(`editor %q failed: %w
+
+Set VISUAL or EDITOR to the editor to use`), editor, err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("failed to read edited file: %w"), err)
+	}
+	return string(edited), nil
+}
+
+// splitTitle splits an edited PR into the title on its first line and the
+// description after it
+func splitTitle(content string) (string, string) {
+	title, description, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(description)
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// Keys remaps the keys of prompts and vibe tui. Each action is triggered by
+// any of its keys, written like "y", "ctrl+y", "up" or "space"; an empty
+// list keeps the default.
+type Keys struct {
+	// Accept, Edit, Regenerate and Cancel pick that choice in prompts
+	// directly, which have no such shortcuts by default. In vibe tui,
+	// Accept commits or opens the PR (c, enter), Edit edits the message
+	// (e) and Regenerate generates it again (g).
+	Accept     []string
+	Edit       []string
+	Regenerate []string
+	Cancel     []string
+
+	// Up and Down move through the choices of prompts and the files of
+	// vibe tui (up, k and down, j)
+	Up   []string
+	Down []string
+
+	// Stage stages or unstages the selected file in vibe tui (space)
+	Stage []string
+
+	// Quit leaves vibe tui (q, ctrl+c always works)
+	Quit []string
+}
+
+// keys are the configured keys, see SetKeys
+var keys Keys
+
+// SetKeys sets the keys of prompts and vibe tui
+func SetKeys(k Keys) {
+	keys = k
+}
+
+// binding returns a binding for the configured keys, or the defaults when
+// none are configured
+func binding(configured []string, defaults ...string) key.Binding {
+	if len(configured) == 0 {
+		configured = defaults
+	}
+	names := make([]string, 0, len(configured))
+	for _, k := range configured {
+		// Bubble Tea names the space bar " "
+		if k == "space" {
+			k = " "
+		}
+		names = append(names, k)
+	}
+	return key.NewBinding(key.WithKeys(names...), key.WithHelp(keyName(configured), ""))
+}
+
+// keyName returns how the first of keys is shown in help
+func keyName(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	switch keys[0] {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	}
+	return keys[0]
+}
+
+// keyMap returns huh's key map with the configured navigation keys
+func keyMap() *huh.KeyMap {
+	km := huh.NewDefaultKeyMap()
+	if len(keys.Up) > 0 {
+		km.Select.Up = binding(keys.Up)
+		km.MultiSelect.Up = binding(keys.Up)
+	}
+	if len(keys.Down) > 0 {
+		km.Select.Down = binding(keys.Down)
+		km.MultiSelect.Down = binding(keys.Down)
+	}
+	return km
+}
+
+// run runs a single prompt like huh's Field.Run, with the configured keys
+func run(field huh.Field) error {
+	return runForm(huh.NewForm(huh.NewGroup(field)).WithShowHelp(false))
+}
+
+// runForm runs a form with the configured keys
+func runForm(form *huh.Form, opts ...tea.ProgramOption) error {
+	return form.WithKeyMap(keyMap()).WithProgramOptions(opts...).Run()
+}
+
+// selectAction asks to pick one of options, whose values are actions such
+// as "accept" or "cancel". The configured shortcuts of the actions pick
+// them directly and are shown next to them.
+func selectAction(title string, options []huh.Option[string], choice *string) error {
+	shortcuts := map[string][]string{
+		"accept":     keys.Accept,
+		"edit":       keys.Edit,
+		"regenerate": keys.Regenerate,
+		"cancel":     keys.Cancel,
+	}
+
+	actions := make(map[string]string)
+	for i, option := range options {
+		configured := shortcuts[option.Value]
+		if len(configured) == 0 {
+			continue
+		}
+		for _, k := range binding(configured).Keys() {
+			actions[k] = option.Value
+		}
+		options[i].Key = fmt.Sprintf("%s (%s)", option.Key, keyName(configured))
+	}
+
+	picked := false
+	pick := func(_ tea.Model, msg tea.Msg) tea.Msg {
+		if k, ok := msg.(tea.KeyMsg); ok {
+			if action, ok := actions[k.String()]; ok {
+				*choice, picked = action, true
+				return tea.QuitMsg{}
+			}
+		}
+		return msg
+	}
+
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title(title).
+			Options(options...).
+			Value(choice),
+	)).WithShowHelp(false)
+	err := runForm(form, tea.WithFilter(pick))
+	if picked {
+		// Quitting leaves the cursor after the prompt, unlike submitting
+		fmt.Println()
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// defaultPager is used when PAGER isn't set, like git
+const defaultPager = "less"
+
+// noPager prints long output directly, see SetNoPager
+var noPager bool
+
+// SetNoPager disables the pager for long output
+func SetNoPager(disable bool) {
+	noPager = disable
+}
+
+// Page prints text to stdout, through $PAGER when stdout is a terminal and
+// text doesn't fit on the screen. Like git, less is the default and gets
+// LESS=FRX unless LESS is set, so colors are kept and the output stays on
+// the screen after quitting.
+func Page(text string) {
+	pager := pagerCommand()
+	if pager == nil || fitsScreen(text) {
+		fmt.Print(text)
+		return
+	}
+
+	pager.Stdin = strings.NewReader(text)
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	err := pager.Run()
+	if err != nil && (pager.ProcessState == nil || pager.ProcessState.ExitCode() == 127) {
+		// The pager couldn't be started or wasn't found
+		fmt.Print(text)
+	}
+}
+
+// pagerCommand returns the pager to run, or nil to print directly
+func pagerCommand() *exec.Cmd {
+	if noPager || !isTerminal(os.Stdout) {
+		return nil
+	}
+
+	pager := strings.TrimSpace(os.Getenv("PAGER"))
+	if pager == "" {
+		pager = defaultPager
+	}
+	if pager == "cat" {
+		return nil
+	}
+
+	// Like git, run the pager through the shell so it can have arguments
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		args := strings.Fields(pager)
+		cmd = exec.Command(args[0], args[1:]...)
+	} else {
+		cmd = exec.Command("sh", "-c", pager)
+	}
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+	return cmd
+}
+
+// fitsScreen reports whether text fits on the terminal without scrolling
+func fitsScreen(text string) bool {
+	_, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || height <= 0 {
+		return true
+	}
+	return strings.Count(text, "\n") < height
+}
@@ -2,9 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/huh"
+
+	"github.com/user/vibe/internal/i18n"
 )
 
 // Action represents the user's choice
@@ -14,12 +18,16 @@ const (
 	ActionAccept Action = iota
 	ActionEdit
 	ActionCancel
+	ActionRegenerate
 )
 
 // CommitResult holds the result of the commit confirmation
 type CommitResult struct {
 	Action  Action
 	Message string
+
+	// Hint is the user's instruction for ActionRegenerate, if any
+	Hint string
 }
 
 // PRResult holds the result of the PR confirmation
@@ -27,28 +35,60 @@ type PRResult struct {
 	Action      Action
 	Title       string
 	Description string
+	Labels      []string
+
+	// Hint is the user's instruction for ActionRegenerate, if any
+	Hint string
+}
+
+// ConfirmCommit shows the commit message and asks for confirmation.
+// canRegenerate offers to generate it again, see ActionRegenerate.
+func ConfirmCommit(message string, canRegenerate bool) (*CommitResult, error) {
+	return confirmMessage(i18n.T("Generated commit message:"), i18n.T("Edit commit message"), message, canRegenerate)
+}
+
+// ConfirmTag shows the message of the tag about to be created and asks for
+// confirmation
+func ConfirmTag(name, message string) (*CommitResult, error) {
+	return confirmMessage(fmt.Sprintf(i18n.T("Generated message for tag %s:"), name), i18n.T("Edit tag message"), message, false)
 }
 
-// ConfirmCommit shows the commit message and asks for confirmation
-func ConfirmCommit(message string) (*CommitResult, error) {
-	fmt.Println("\nGenerated commit message:")
+// ConfirmSquash shows the message of the commit about to replace n
+// squashed commits and asks for confirmation
+func ConfirmSquash(n int, message string) (*CommitResult, error) {
+	return confirmMessage(fmt.Sprintf(i18n.T("Generated message for the %d squashed commits:"), n), i18n.T("Edit commit message"), message, false)
+}
+
+// confirmMessage shows a generated message under heading and lets the user
+// accept, edit, regenerate if canRegenerate, or cancel it
+func confirmMessage(heading, editTitle, message string, canRegenerate bool) (*CommitResult, error) {
+	fmt.Println("\n" + heading)
 	fmt.Println(strings.Repeat("-", 50))
 	fmt.Println(message)
 	fmt.Println(strings.Repeat("-", 50))
 
+	if assumeYes {
+		return &CommitResult{Action: ActionAccept, Message: message}, nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return nil, err
+	}
+
+	options := []huh.Option[string]{
+		huh.NewOption(i18n.T("Accept"), "accept"),
+		huh.NewOption(i18n.T("Edit"), "edit"),
+		huh.NewOption(i18n.T("Edit in editor"), "editor"),
+	}
+	if canRegenerate {
+		options = append(options, huh.NewOption(i18n.T("Regenerate"), "regenerate"))
+	}
+	options = append(options, huh.NewOption(i18n.T("Cancel"), "cancel"))
+
 	var choice string
-	err := huh.NewSelect[string]().
-		Title("What would you like to do?").
-		Options(
-			huh.NewOption("Accept", "accept"),
-			huh.NewOption("Edit", "edit"),
-			huh.NewOption("Cancel", "cancel"),
-		).
-		Value(&choice).
-		Run()
+	err := selectAction(i18n.T("What would you like to do?"), options, &choice)
 
 	if err != nil {
-		return nil, fmt.Errorf("prompt failed: %w", err)
+		return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
 	}
 
 	result := &CommitResult{Message: message}
@@ -60,17 +100,83 @@ func ConfirmCommit(message string) (*CommitResult, error) {
 		result.Action = ActionEdit
 		// Allow editing the message
 		var editedMessage string
-		err := huh.NewText().
-			Title("Edit commit message").
+		err := run(huh.NewText().
+			Title(editTitle).
 			Value(&editedMessage).
-			CharLimit(500).
-			Run()
+			CharLimit(2000))
 		if err != nil {
-			return nil, fmt.Errorf("edit prompt failed: %w", err)
+			return nil, fmt.Errorf(i18n.T("edit prompt failed: %w"), err)
 		}
 		if editedMessage != "" {
 			result.Message = strings.TrimSpace(editedMessage)
 		}
+	case "editor":
+		result.Action = ActionEdit
+		edited, err := EditInEditor(message+"\n", "vibe-message-*.txt")
+		if err != nil {
+			return nil, err
+		}
+		if edited = strings.TrimSpace(edited); edited != "" {
+			result.Message = edited
+		}
+	case "regenerate":
+		result.Action = ActionRegenerate
+		if result.Hint, err = askHint(); err != nil {
+			return nil, err
+		}
+	case "cancel":
+		result.Action = ActionCancel
+	}
+
+	return result, nil
+}
+
+// BranchResult holds the result of the branch name confirmation
+type BranchResult struct {
+	Action Action
+	Name   string
+}
+
+// ConfirmBranch shows the proposed branch name and asks for confirmation
+func ConfirmBranch(name string) (*BranchResult, error) {
+	fmt.Printf(i18n.T("\nProposed branch name: %s\n\n"), name)
+
+	if assumeYes {
+		return &BranchResult{Action: ActionAccept, Name: name}, nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return nil, err
+	}
+
+	var choice string
+	err := selectAction(i18n.T("What would you like to do?"), []huh.Option[string]{
+		huh.NewOption(i18n.T("Create and switch to it"), "accept"),
+		huh.NewOption(i18n.T("Edit"), "edit"),
+		huh.NewOption(i18n.T("Cancel"), "cancel"),
+	}, &choice)
+
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+
+	result := &BranchResult{Name: name}
+
+	switch choice {
+	case "accept":
+		result.Action = ActionAccept
+	case "edit":
+		result.Action = ActionEdit
+		var editedName string
+		err := run(huh.NewInput().
+			Title(i18n.T("Branch name")).
+			Value(&editedName).
+			Placeholder(name))
+		if err != nil {
+			return nil, fmt.Errorf(i18n.T("edit prompt failed: %w"), err)
+		}
+		if editedName = strings.TrimSpace(editedName); editedName != "" {
+			result.Name = editedName
+		}
 	case "cancel":
 		result.Action = ActionCancel
 	}
@@ -79,32 +185,43 @@ func ConfirmCommit(message string) (*CommitResult, error) {
 }
 
 // ConfirmPR shows the PR details and asks for confirmation
-func ConfirmPR(title, description string) (*PRResult, error) {
-	fmt.Println("\nGenerated PR:")
-	fmt.Println(strings.Repeat("-", 50))
-	fmt.Printf("Title: %s\n\n", title)
-	fmt.Println("Description:")
-	fmt.Println(description)
-	fmt.Println(strings.Repeat("-", 50))
+func ConfirmPR(title, description string, labels []string) (*PRResult, error) {
+	var b strings.Builder
+	b.WriteString(i18n.T("\nGenerated PR:\n"))
+	b.WriteString(strings.Repeat("-", 50) + "\n")
+	fmt.Fprintf(&b, i18n.T("Title: %s\n\n"), title)
+	b.WriteString(i18n.T("Description:\n"))
+	b.WriteString(description + "\n")
+	if len(labels) > 0 {
+		fmt.Fprintf(&b, i18n.T("\nLabels: %s\n"), strings.Join(labels, ", "))
+	}
+	b.WriteString(strings.Repeat("-", 50) + "\n")
+	Page(b.String())
+
+	if assumeYes {
+		return &PRResult{Action: ActionAccept, Title: title, Description: description, Labels: labels}, nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return nil, err
+	}
 
 	var choice string
-	err := huh.NewSelect[string]().
-		Title("What would you like to do?").
-		Options(
-			huh.NewOption("Accept", "accept"),
-			huh.NewOption("Edit", "edit"),
-			huh.NewOption("Cancel", "cancel"),
-		).
-		Value(&choice).
-		Run()
+	err := selectAction(i18n.T("What would you like to do?"), []huh.Option[string]{
+		huh.NewOption(i18n.T("Accept"), "accept"),
+		huh.NewOption(i18n.T("Edit"), "edit"),
+		huh.NewOption(i18n.T("Edit in editor"), "editor"),
+		huh.NewOption(i18n.T("Regenerate"), "regenerate"),
+		huh.NewOption(i18n.T("Cancel"), "cancel"),
+	}, &choice)
 
 	if err != nil {
-		return nil, fmt.Errorf("prompt failed: %w", err)
+		return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
 	}
 
 	result := &PRResult{
 		Title:       title,
 		Description: description,
+		Labels:      labels,
 	}
 
 	switch choice {
@@ -112,25 +229,29 @@ func ConfirmPR(title, description string) (*PRResult, error) {
 		result.Action = ActionAccept
 	case "edit":
 		result.Action = ActionEdit
-		// Allow editing title and description
-		var newTitle, newDescription string
+		// Allow editing title, description and labels
+		var newTitle, newDescription, newLabels string
 
 		form := huh.NewForm(
 			huh.NewGroup(
 				huh.NewInput().
-					Title("PR Title").
+					Title(i18n.T("PR Title")).
 					Value(&newTitle).
 					Placeholder(title),
 				huh.NewText().
-					Title("PR Description").
+					Title(i18n.T("PR Description")).
 					Value(&newDescription).
 					CharLimit(2000),
+				huh.NewInput().
+					Title(i18n.T("Labels (comma-separated, '-' for none)")).
+					Value(&newLabels).
+					Placeholder(strings.Join(labels, ", ")),
 			),
 		)
 
-		err := form.Run()
+		err := runForm(form)
 		if err != nil {
-			return nil, fmt.Errorf("edit prompt failed: %w", err)
+			return nil, fmt.Errorf(i18n.T("edit prompt failed: %w"), err)
 		}
 
 		if newTitle != "" {
@@ -139,6 +260,24 @@ func ConfirmPR(title, description string) (*PRResult, error) {
 		if newDescription != "" {
 			result.Description = strings.TrimSpace(newDescription)
 		}
+		if newLabels != "" {
+			result.Labels = parseList(newLabels)
+		}
+	case "editor":
+		result.Action = ActionEdit
+		// The title goes on the first line, like the subject of a commit
+		edited, err := EditInEditor(title+"\n\n"+description+"\n", "vibe-pr-*.md")
+		if err != nil {
+			return nil, err
+		}
+		if newTitle, newDescription := splitTitle(edited); newTitle != "" {
+			result.Title, result.Description = newTitle, newDescription
+		}
+	case "regenerate":
+		result.Action = ActionRegenerate
+		if result.Hint, err = askHint(); err != nil {
+			return nil, err
+		}
 	case "cancel":
 		result.Action = ActionCancel
 	}
@@ -146,26 +285,461 @@ func ConfirmPR(title, description string) (*PRResult, error) {
 	return result, nil
 }
 
+// ConfirmUpdateExisting shows the open PR of the current branch and asks
+// whether to regenerate its title and description. It returns false when the
+// user only wants the existing PR.
+func ConfirmUpdateExisting(number int, title, url string) (bool, error) {
+	fmt.Printf(i18n.T("\nThis branch already has an open PR #%d: %s\n"), number, title)
+	fmt.Printf("  %s\n\n", url)
+
+	if assumeYes {
+		return false, nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return false, err
+	}
+
+	var choice string
+	err := selectAction(i18n.T("What would you like to do?"), []huh.Option[string]{
+		huh.NewOption(i18n.T("Update its title and description"), "update"),
+		huh.NewOption(i18n.T("Keep it as it is"), "keep"),
+	}, &choice)
+
+	if err != nil {
+		return false, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+
+	return choice == "update", nil
+}
+
+// SyncAction is the user's choice for a branch that is behind its base
+type SyncAction int
+
+const (
+	SyncContinue SyncAction = iota
+	SyncRebase
+	SyncMerge
+	SyncCancel
+)
+
+// ConfirmSync warns that the branch is behind baseRef, listing the files
+// that may conflict, and asks whether to rebase, merge or continue as is
+func ConfirmSync(baseRef string, behind int, conflicts []string) (SyncAction, error) {
+	fmt.Printf(i18n.T("\nYour branch is %d commit(s) behind %s.\n"), behind, baseRef)
+	if len(conflicts) > 0 {
+		fmt.Println(i18n.T("These files changed on both sides and may conflict:"))
+		for _, file := range conflicts {
+			fmt.Printf("  - %s\n", file)
+		}
+	}
+	fmt.Println()
+
+	if assumeYes {
+		return SyncContinue, nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return SyncCancel, err
+	}
+
+	var choice SyncAction
+	err := run(huh.NewSelect[SyncAction]().
+		Title(fmt.Sprintf(i18n.T("Update your branch with %s first?"), baseRef)).
+		Options(
+			huh.NewOption(fmt.Sprintf(i18n.T("Rebase onto %s"), baseRef), SyncRebase),
+			huh.NewOption(fmt.Sprintf(i18n.T("Merge %s"), baseRef), SyncMerge),
+			huh.NewOption(i18n.T("Continue without updating"), SyncContinue),
+			huh.NewOption(i18n.T("Cancel"), SyncCancel),
+		).
+		Value(&choice))
+
+	if err != nil {
+		return SyncCancel, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return choice, nil
+}
+
+// parseList splits a comma-separated input into trimmed, non-empty items.
+// A single "-" clears the list.
+func parseList(input string) []string {
+	if strings.TrimSpace(input) == "-" {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// FileChoice is a changed file offered by SelectFiles
+type FileChoice struct {
+	Path string
+
+	// Status is the git status --short code shown next to the path
+	Status string
+
+	// Selected preselects the file
+	Selected bool
+}
+
+// SelectFiles lets the user check the files to include and returns their
+// paths. An empty selection is not an error.
+func SelectFiles(files []FileChoice) ([]string, error) {
+	if err := checkTerminal(false); err != nil {
+		return nil, err
+	}
+
+	options := make([]huh.Option[string], 0, len(files))
+	for _, f := range files {
+		label := fmt.Sprintf("%s %s", f.Status, f.Path)
+		options = append(options, huh.NewOption(label, f.Path).Selected(f.Selected))
+	}
+
+	var selected []string
+	err := run(huh.NewMultiSelect[string]().
+		Title(i18n.T("Which files should this commit include?")).
+		Description(i18n.T("Space toggles a file, enter confirms")).
+		Options(options...).
+		Height(min(len(options), 15) + 2).
+		Value(&selected))
+
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return selected, nil
+}
+
+// SelectCommit lets the user pick one of commits, given as "hash subject"
+// labels, and returns its position
+func SelectCommit(title string, commits []string) (int, error) {
+	if err := checkTerminal(false); err != nil {
+		return 0, err
+	}
+
+	options := make([]huh.Option[int], 0, len(commits))
+	for i, c := range commits {
+		options = append(options, huh.NewOption(c, i))
+	}
+
+	var choice int
+	err := run(huh.NewSelect[int]().
+		Title(title).
+		Options(options...).
+		Height(min(len(options), 15) + 2).
+		Value(&choice))
+
+	if err != nil {
+		return 0, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return choice, nil
+}
+
+// SplitAction is the user's choice for a proposed split of staged changes
+type SplitAction int
+
+const (
+	SplitAccept SplitAction = iota
+	SplitSingle
+	SplitCancel
+)
+
+// ConfirmSplit shows the proposed commits, each message followed by its
+// files, and asks whether to create them
+func ConfirmSplit(messages []string, files [][]string) (SplitAction, error) {
+	fmt.Printf(i18n.T("\nProposed %d commits:\n"), len(messages))
+	fmt.Println(strings.Repeat("-", 50))
+	for i, message := range messages {
+		fmt.Printf("%d. %s\n", i+1, message)
+		for _, f := range files[i] {
+			fmt.Printf("     %s\n", f)
+		}
+	}
+	fmt.Println(strings.Repeat("-", 50))
+
+	if assumeYes {
+		return SplitAccept, nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return SplitCancel, err
+	}
+
+	var choice SplitAction
+	err := run(huh.NewSelect[SplitAction]().
+		Title(i18n.T("Create these commits?")).
+		Options(
+			huh.NewOption(fmt.Sprintf(i18n.T("Create %d commits"), len(messages)), SplitAccept),
+			huh.NewOption(i18n.T("Make a single commit instead"), SplitSingle),
+			huh.NewOption(i18n.T("Cancel"), SplitCancel),
+		).
+		Value(&choice))
+
+	if err != nil {
+		return SplitCancel, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return choice, nil
+}
+
+// ConfirmReword shows the current and proposed subjects of commits side by
+// side and asks which commits to reword. It returns their positions, none
+// when the user cancels.
+func ConfirmReword(hashes, before, after []string) ([]int, error) {
+	width := utf8.RuneCountInString(i18n.T("Before"))
+	for _, b := range before {
+		width = max(width, min(utf8.RuneCountInString(b), 50))
+	}
+
+	fmt.Printf(i18n.T("\nProposed messages for %d commits:\n"), len(hashes))
+	fmt.Printf("  %-7s  %-*s  %s\n", i18n.T("Commit"), width, i18n.T("Before"), i18n.T("After"))
+	for i, hash := range hashes {
+		fmt.Printf("  %-7s  %-*s  %s\n", hash, width, truncate(before[i], 50), after[i])
+	}
+	fmt.Println()
+
+	choice := "all"
+	if !assumeYes {
+		if err := checkTerminal(true); err != nil {
+			return nil, err
+		}
+		err := run(huh.NewSelect[string]().
+			Title(i18n.T("Reword these commits?")).
+			Options(
+				huh.NewOption(fmt.Sprintf(i18n.T("Reword all %d commits"), len(hashes)), "all"),
+				huh.NewOption(i18n.T("Choose which commits to reword"), "choose"),
+				huh.NewOption(i18n.T("Cancel"), "cancel"),
+			).
+			Value(&choice))
+
+		if err != nil {
+			return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+		}
+	}
+
+	switch choice {
+	case "all":
+		all := make([]int, len(hashes))
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	case "choose":
+		options := make([]huh.Option[int], 0, len(hashes))
+		for i, hash := range hashes {
+			options = append(options, huh.NewOption(fmt.Sprintf("%s %s", hash, after[i]), i).Selected(true))
+		}
+		var selected []int
+		err := run(huh.NewMultiSelect[int]().
+			Title(i18n.T("Which commits should be reworded?")).
+			Description(i18n.T("Space toggles a commit, enter confirms")).
+			Options(options...).
+			Height(min(len(options), 15) + 2).
+			Value(&selected))
+		if err != nil {
+			return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+		}
+		return selected, nil
+	}
+	return nil, nil
+}
+
+// truncate shortens s to at most n characters, marking the cut with "..."
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+// HunkAction is the user's choice for a hunk offered by ConfirmHunk
+type HunkAction int
+
+const (
+	HunkStage HunkAction = iota
+	HunkSkip
+	HunkStageFile
+	HunkSkipFile
+	HunkDone
+)
+
+// ConfirmHunk shows hunk n of total in path and asks whether to stage it
+func ConfirmHunk(path, hunk string, n, total int) (HunkAction, error) {
+	if err := checkTerminal(false); err != nil {
+		return HunkDone, err
+	}
+
+	Page(fmt.Sprintf(i18n.T("\n%s (hunk %d/%d)\n%s\n\n"), path, n, total, colorDiff(hunk)))
+
+	var choice HunkAction
+	err := run(huh.NewSelect[HunkAction]().
+		Title(i18n.T("Stage this hunk?")).
+		Options(
+			huh.NewOption(i18n.T("Stage"), HunkStage),
+			huh.NewOption(i18n.T("Skip"), HunkSkip),
+			huh.NewOption(i18n.T("Stage the rest of this file"), HunkStageFile),
+			huh.NewOption(i18n.T("Skip the rest of this file"), HunkSkipFile),
+			huh.NewOption(i18n.T("Done, skip everything left"), HunkDone),
+		).
+		Value(&choice))
+
+	if err != nil {
+		return HunkDone, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return choice, nil
+}
+
+// ResolveAction is the user's choice for a conflict offered by
+// ConfirmResolution
+type ResolveAction int
+
+const (
+	ResolveAccept ResolveAction = iota
+	ResolveOurs
+	ResolveTheirs
+	ResolveSkip
+	ResolveDone
+)
+
+// ResolutionResult holds the result of the conflict resolution confirmation
+type ResolutionResult struct {
+	Action ResolveAction
+
+	// Code is the accepted or edited resolution with ResolveAccept
+	Code string
+}
+
+// ConfirmResolution shows conflict n of total in path and the proposed
+// resolution, and lets the user accept, edit or replace it with one side
+func ConfirmResolution(path string, n, total int, conflict, proposal, reason string) (*ResolutionResult, error) {
+	if err := checkTerminal(false); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf(i18n.T("\n%s (conflict %d/%d)\n"), path, n, total)
+	fmt.Println(conflict)
+	fmt.Println(i18n.T("\nProposed resolution:"))
+	if reason != "" {
+		fmt.Println(reason)
+	}
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(proposal)
+	fmt.Println(strings.Repeat("-", 50))
+
+	var choice string
+	err := selectAction(i18n.T("Resolve this conflict?"), []huh.Option[string]{
+		huh.NewOption(i18n.T("Accept"), "accept"),
+		huh.NewOption(i18n.T("Edit"), "edit"),
+		huh.NewOption(i18n.T("Edit in editor"), "editor"),
+		huh.NewOption(i18n.T("Keep ours"), "ours"),
+		huh.NewOption(i18n.T("Keep theirs"), "theirs"),
+		huh.NewOption(i18n.T("Skip, leave the markers"), "skip"),
+		huh.NewOption(i18n.T("Done, skip everything left"), "done"),
+	}, &choice)
+
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+
+	result := &ResolutionResult{Code: proposal}
+	switch choice {
+	case "accept":
+		result.Action = ResolveAccept
+	case "edit":
+		result.Action = ResolveAccept
+		// Start from the proposal, the code is rarely rewritten from scratch
+		edited := proposal
+		err := run(huh.NewText().
+			Title(i18n.T("Edit resolution")).
+			Value(&edited).
+			CharLimit(20000))
+		if err != nil {
+			return nil, fmt.Errorf(i18n.T("edit prompt failed: %w"), err)
+		}
+		result.Code = edited
+	case "editor":
+		result.Action = ResolveAccept
+		edited, err := EditInEditor(proposal, "vibe-resolution-*"+filepath.Ext(path))
+		if err != nil {
+			return nil, err
+		}
+		// Editors usually end the file with a newline the proposal may lack
+		if !strings.HasSuffix(proposal, "\n") {
+			edited = strings.TrimSuffix(edited, "\n")
+		}
+		result.Code = edited
+	case "ours":
+		result.Action = ResolveOurs
+	case "theirs":
+		result.Action = ResolveTheirs
+	case "skip":
+		result.Action = ResolveSkip
+	case "done":
+		result.Action = ResolveDone
+	}
+	return result, nil
+}
+
+// askHint asks for an optional one-line instruction for regenerating
+func askHint() (string, error) {
+	var hint string
+	err := run(huh.NewInput().
+		Title(i18n.T("Anything to change? (optional)")).
+		Placeholder(i18n.T("e.g. mention the migration, keep it shorter")).
+		Value(&hint))
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return strings.TrimSpace(hint), nil
+}
+
+// AskReason asks for a short free-form reason, e.g. why a commit is
+// reverted. An empty answer is not an error.
+func AskReason(title string) (string, error) {
+	if assumeYes {
+		return "", nil
+	}
+	if err := checkTerminal(true); err != nil {
+		return "", err
+	}
+
+	var reason string
+	err := run(huh.NewText().
+		Title(title).
+		Description(i18n.T("Leave empty to skip")).
+		Value(&reason).
+		CharLimit(1000))
+
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("prompt failed: %w"), err)
+	}
+	return strings.TrimSpace(reason), nil
+}
+
 // ShowError displays an error message with formatting
 func ShowError(err error) {
-	fmt.Printf("\nError: %s\n", err.Error())
+	fmt.Printf("\n%s %s\n", paint(stdoutRenderer, red, i18n.T("Error:")), err.Error())
 }
 
 // ShowSuccess displays a success message
 func ShowSuccess(message string) {
-	fmt.Printf("\n%s\n", message)
+	fmt.Printf("\n%s\n", paint(stdoutRenderer, green, message))
 }
 
-// ShowInfo displays an informational message
-func ShowInfo(message string) {
-	fmt.Println(message)
+// quiet hides informational messages, see SetQuiet
+var quiet bool
+
+// SetQuiet hides informational messages, such as progress, leaving errors,
+// results and prompts
+func SetQuiet(q bool) {
+	quiet = q
 }
 
-// ShowSpinner displays a spinner with a message while an operation is in progress
-// Returns a function to stop the spinner
-func ShowSpinner(message string) func() {
-	// For now, just print the message
-	// In a future enhancement, we could use a proper spinner from bubbletea
-	fmt.Printf("%s...\n", message)
-	return func() {}
+// ShowInfo displays an informational message, unless in quiet mode
+func ShowInfo(message string) {
+	if quiet {
+		return
+	}
+	fmt.Println(message)
 }
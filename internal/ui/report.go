@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// Report collects notes about degraded behavior during a run (truncated
+// diffs, excluded files, cached results, ...) so they can be shown together
+// at the end and users know how much to trust the generated content
+type Report struct {
+	notes []string
+}
+
+// NewReport creates an empty report
+func NewReport() *Report {
+	return &Report{}
+}
+
+// Add records a note about degraded behavior, translating format into the
+// current locale
+func (r *Report) Add(format string, args ...interface{}) {
+	r.notes = append(r.notes, fmt.Sprintf(i18n.T(format), args...))
+}
+
+// Notes returns the recorded notes
+func (r *Report) Notes() []string {
+	return r.notes
+}
+
+// Show prints the recorded notes, if any
+func (r *Report) Show() {
+	r.ShowTo(os.Stdout)
+}
+
+// ShowTo prints the recorded notes to w, if any, e.g. to stderr when
+// stdout is piped
+func (r *Report) ShowTo(w io.Writer) {
+	if len(r.notes) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, i18n.T("\nNotes on this run:"))
+	for _, note := range r.notes {
+		fmt.Fprintf(w, "  - %s\n", note)
+	}
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// onInterrupt runs when Ctrl-C is pressed during a spinner, see
+// SetInterruptHandler
+var onInterrupt = func() { os.Exit(130) }
+
+// SetInterruptHandler sets what Ctrl-C does while a spinner is shown. The
+// terminal is restored before handler runs, which is expected to exit.
+func SetInterruptHandler(handler func()) {
+	onInterrupt = handler
+}
+
+// ShowSpinner displays a spinner with a message and the elapsed time on
+// stderr while an operation is in progress, so that stdout only gets
+// results. Without a terminal the message is printed once. It returns a
+// function to stop the spinner.
+func ShowSpinner(message string) func() {
+	if quiet {
+		return func() {}
+	}
+	if !isTerminal(os.Stderr) {
+		fmt.Fprintf(os.Stderr, "%s...\n", message)
+		return func() {}
+	}
+
+	style := stderrRenderer.NewStyle().Foreground(lipgloss.Color(cyan))
+	model := spinnerModel{
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(style)),
+		message: message,
+		start:   time.Now(),
+	}
+	// Without input the terminal stays as it is, and Ctrl-C is a SIGINT
+	// the program turns into ErrInterrupted
+	p := tea.NewProgram(model, tea.WithInput(nil), tea.WithOutput(os.Stderr))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := p.Run(); errors.Is(err, tea.ErrInterrupted) {
+			onInterrupt()
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.Send(stopSpinner{})
+			<-done
+		})
+	}
+}
+
+// stopSpinner ends the spinner, leaving the message and the elapsed time
+type stopSpinner struct{}
+
+// spinnerModel is the bubbletea model of ShowSpinner
+type spinnerModel struct {
+	spinner spinner.Model
+	message string
+	start   time.Time
+	stopped bool
+}
+
+func (m spinnerModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(stopSpinner); ok {
+		m.stopped = true
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m spinnerModel) View() string {
+	elapsed := fmt.Sprintf("%.1fs", time.Since(m.start).Seconds())
+	if m.stopped {
+		return fmt.Sprintf("%s... %s\n", m.message, elapsed)
+	}
+	return fmt.Sprintf("%s %s... %s", m.spinner.View(), m.message, elapsed)
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// ErrNoTerminal is returned by prompts when stdin or stdout isn't a
+// terminal, e.g. in a pipe or in CI, instead of hanging on the prompt
+var ErrNoTerminal = errors.New("stdin or stdout is not a terminal")
+
+// assumeYes answers prompts without showing them, see SetAssumeYes
+var assumeYes bool
+
+// SetAssumeYes makes prompts take the answer that goes ahead without
+// changing anything else: accept generated messages and names, keep
+// existing PRs, don't update the branch first and skip optional questions
+func SetAssumeYes(yes bool) {
+	assumeYes = yes
+}
+
+// Interactive reports whether prompts can be shown, that is whether stdin
+// and stdout are terminals
+func Interactive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// checkTerminal returns an error when a prompt can't be shown. answerable
+// prompts are answered by --yes, which the error then suggests.
+func checkTerminal(answerable bool) error {
+	switch {
+	case Interactive():
+		return nil
+	case answerable:
+		return fmt.Errorf(i18n.T(`%w, can't prompt
+
+Run vibe in a terminal, or pass --yes to accept without prompting`), ErrNoTerminal)
+	default:
+		return fmt.Errorf(i18n.T("%w, can't prompt\n\nRun vibe in a terminal to choose interactively"), ErrNoTerminal)
+	}
+}
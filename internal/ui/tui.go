@@ -0,0 +1,516 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/user/vibe/internal/i18n"
+)
+
+// TUIActions do the work behind the keys of RunTUI. They run while the
+// TUI owns the terminal, so they must not print or prompt.
+type TUIActions struct {
+	// Files returns the changed files, Selected set for staged ones
+	Files func() ([]FileChoice, error)
+
+	// Diff returns the staged and unstaged changes of a file
+	Diff func(path string) (string, error)
+
+	// Stage stages a file, or unstages it when stage is false
+	Stage func(path string, stage bool) error
+
+	// GenerateCommit returns a commit message for the staged changes
+	GenerateCommit func() (string, error)
+
+	// Commit commits the staged changes and returns a summary, e.g. the
+	// hash
+	Commit func(message string) (string, error)
+
+	// GeneratePR returns a title and description for a PR of the branch
+	GeneratePR func() (string, string, error)
+}
+
+// TUIResult is what the user chose to do once the TUI is closed
+type TUIResult struct {
+	// CreatePR is set when the user asked to open a PR with Title and
+	// Description
+	CreatePR    bool
+	Title       string
+	Description string
+}
+
+// RunTUI shows changed files, the diff of the selected file and the
+// generated commit message or PR in panes, until the user quits or asks for
+// a PR. Informational output is hidden meanwhile.
+func RunTUI(actions TUIActions) (*TUIResult, error) {
+	if err := checkTerminal(false); err != nil {
+		return nil, err
+	}
+
+	wasQuiet := quiet
+	quiet = true
+	defer func() { quiet = wasQuiet }()
+
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 20000
+
+	m := tuiModel{
+		actions: actions,
+		keys:    newTUIKeyMap(),
+		diff:    viewport.New(0, 0),
+		message: viewport.New(0, 0),
+		editor:  editor,
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(stdoutRenderer.NewStyle().Foreground(lipgloss.Color(cyan)))),
+	}
+
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("TUI failed: %w"), err)
+	}
+	return final.(tuiModel).result, nil
+}
+
+// tuiPane is a pane of the TUI that can have the focus
+type tuiPane int
+
+const (
+	filesPane tuiPane = iota
+	diffPane
+	messagePane
+	paneCount
+)
+
+// tuiKeyMap holds the configurable keys of the TUI, see Keys
+type tuiKeyMap struct {
+	up, down, stage, generate, edit, submit, quit key.Binding
+}
+
+func newTUIKeyMap() tuiKeyMap {
+	return tuiKeyMap{
+		up:       binding(keys.Up, "up", "k"),
+		down:     binding(keys.Down, "down", "j"),
+		stage:    binding(keys.Stage, " "),
+		generate: binding(keys.Regenerate, "g"),
+		edit:     binding(keys.Edit, "e"),
+		submit:   binding(keys.Accept, "c", "enter"),
+		quit:     binding(keys.Quit, "q"),
+	}
+}
+
+// help lists the keys of the TUI
+func (k tuiKeyMap) help() string {
+	return fmt.Sprintf(i18n.T("%s/%s move · %s stage · tab switch pane · %s generate · %s edit · p commit/PR · %s commit or open PR · %s quit"),
+		k.up.Help().Key, k.down.Help().Key, k.stage.Help().Key, k.generate.Help().Key, k.edit.Help().Key, k.submit.Help().Key, k.quit.Help().Key)
+}
+
+// Messages of the TUI's background work
+type (
+	tuiFilesMsg struct {
+		files []FileChoice
+		err   error
+	}
+	tuiDiffMsg struct {
+		path, diff string
+		err        error
+	}
+	tuiGeneratedMsg struct {
+		pr   bool
+		text string
+		err  error
+	}
+	tuiCommittedMsg struct {
+		summary string
+		err     error
+	}
+	tuiStagedMsg struct{ err error }
+)
+
+// tuiModel is the bubbletea model of RunTUI
+type tuiModel struct {
+	actions       TUIActions
+	keys          tuiKeyMap
+	width, height int
+	focus         tuiPane
+
+	files  []FileChoice
+	cursor int
+
+	diff    viewport.Model
+	message viewport.Model
+	editor  textarea.Model
+	editing bool
+
+	// prMode shows the PR instead of the commit message, the PR title is
+	// the first line of prText
+	prMode     bool
+	commitText string
+	prText     string
+
+	// busy describes the work in progress, empty when idle
+	busy    string
+	spinner spinner.Model
+	status  string
+	failed  bool
+
+	result *TUIResult
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return tea.Batch(m.loadFiles(), m.spinner.Tick)
+}
+
+func (m tuiModel) loadFiles() tea.Cmd {
+	return func() tea.Msg {
+		files, err := m.actions.Files()
+		return tuiFilesMsg{files, err}
+	}
+}
+
+func (m tuiModel) loadDiff() tea.Cmd {
+	if len(m.files) == 0 {
+		return nil
+	}
+	path := m.files[m.cursor].Path
+	return func() tea.Msg {
+		diff, err := m.actions.Diff(path)
+		return tuiDiffMsg{path, diff, err}
+	}
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tuiFilesMsg:
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+			return m, nil
+		}
+		m.files = msg.files
+		m.cursor = min(m.cursor, max(len(m.files)-1, 0))
+		if len(m.files) == 0 {
+			m.diff.SetContent(i18n.T("No changes"))
+		}
+		return m, m.loadDiff()
+
+	case tuiDiffMsg:
+		if len(m.files) == 0 || m.files[m.cursor].Path != msg.path {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.diff.SetContent(msg.err.Error())
+		} else {
+			m.diff.SetContent(colorDiff(msg.diff))
+		}
+		m.diff.GotoTop()
+		return m, nil
+
+	case tuiGeneratedMsg:
+		m.busy = ""
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+			return m, nil
+		}
+		if msg.pr {
+			m.prText = msg.text
+		} else {
+			m.commitText = msg.text
+		}
+		m.setStatus(fmt.Sprintf(i18n.T("Generated, press %s to edit"), m.keys.edit.Help().Key), false)
+		m.showMessage()
+		return m, nil
+
+	case tuiCommittedMsg:
+		m.busy = ""
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+			return m, nil
+		}
+		m.commitText = ""
+		m.setStatus(msg.summary, false)
+		m.showMessage()
+		return m, m.loadFiles()
+
+	case tuiStagedMsg:
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+		}
+		return m, m.loadFiles()
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEditor(msg)
+		}
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// updateEditor handles keys while the message is edited, esc keeps the
+// edit
+func (m tuiModel) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.editor.Blur()
+		text := strings.TrimSpace(m.editor.Value())
+		if m.prMode {
+			m.prText = text
+		} else {
+			m.commitText = text
+		}
+		m.showMessage()
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c", key.Matches(msg, m.keys.quit):
+		return m, tea.Quit
+
+	case msg.String() == "tab":
+		m.focus = (m.focus + 1) % paneCount
+		return m, nil
+	case msg.String() == "shift+tab":
+		m.focus = (m.focus + paneCount - 1) % paneCount
+		return m, nil
+
+	case m.focus == filesPane && key.Matches(msg, m.keys.up):
+		m.cursor = max(m.cursor-1, 0)
+		return m, m.loadDiff()
+	case m.focus == filesPane && key.Matches(msg, m.keys.down):
+		m.cursor = min(m.cursor+1, max(len(m.files)-1, 0))
+		return m, m.loadDiff()
+
+	case key.Matches(msg, m.keys.stage):
+		if len(m.files) == 0 {
+			return m, nil
+		}
+		f := m.files[m.cursor]
+		return m, func() tea.Msg {
+			return tuiStagedMsg{m.actions.Stage(f.Path, !f.Selected)}
+		}
+
+	case msg.String() == "p":
+		m.prMode = !m.prMode
+		m.showMessage()
+		return m, nil
+
+	case key.Matches(msg, m.keys.generate):
+		if m.busy != "" {
+			return m, nil
+		}
+		return m.generate()
+
+	case key.Matches(msg, m.keys.edit):
+		m.editing = true
+		m.focus = messagePane
+		m.editor.SetValue(m.text())
+		return m, m.editor.Focus()
+
+	case key.Matches(msg, m.keys.submit):
+		if m.busy != "" {
+			return m, nil
+		}
+		return m.submit()
+	}
+
+	// Other keys scroll the focused pane
+	var cmd tea.Cmd
+	switch m.focus {
+	case diffPane:
+		m.diff, cmd = m.diff.Update(msg)
+	case messagePane:
+		m.message, cmd = m.message.Update(msg)
+	}
+	return m, cmd
+}
+
+// generate regenerates the commit message or the PR in the background
+func (m tuiModel) generate() (tea.Model, tea.Cmd) {
+	if m.prMode {
+		m.busy = i18n.T("Generating the PR title and description")
+		return m, func() tea.Msg {
+			title, description, err := m.actions.GeneratePR()
+			return tuiGeneratedMsg{pr: true, text: title + "\n\n" + description, err: err}
+		}
+	}
+	m.busy = i18n.T("Generating commit message")
+	return m, func() tea.Msg {
+		message, err := m.actions.GenerateCommit()
+		return tuiGeneratedMsg{text: message, err: err}
+	}
+}
+
+// submit commits the message, or closes the TUI to open the PR
+func (m tuiModel) submit() (tea.Model, tea.Cmd) {
+	text := m.text()
+	if text == "" {
+		m.setStatus(fmt.Sprintf(i18n.T("Nothing to submit yet, press %s to generate or %s to write it"), m.keys.generate.Help().Key, m.keys.edit.Help().Key), true)
+		return m, nil
+	}
+
+	if m.prMode {
+		title, description, _ := strings.Cut(text, "\n")
+		m.result = &TUIResult{CreatePR: true, Title: strings.TrimSpace(title), Description: strings.TrimSpace(description)}
+		return m, tea.Quit
+	}
+
+	m.busy = i18n.T("Committing")
+	return m, func() tea.Msg {
+		summary, err := m.actions.Commit(text)
+		return tuiCommittedMsg{summary, err}
+	}
+}
+
+// text returns the commit message or the PR, whichever is shown
+func (m tuiModel) text() string {
+	if m.prMode {
+		return m.prText
+	}
+	return m.commitText
+}
+
+func (m *tuiModel) setStatus(status string, failed bool) {
+	// Errors may explain how to fix them over several lines
+	m.status, _, _ = strings.Cut(status, "\n")
+	m.failed = failed
+}
+
+// showMessage puts the commit message or the PR in its pane, wrapped to
+// the pane's width
+func (m *tuiModel) showMessage() {
+	text := m.text()
+	if text == "" {
+		text = fmt.Sprintf(i18n.T("Press %s to generate, or %s to write it yourself"), m.keys.generate.Help().Key, m.keys.edit.Help().Key)
+	}
+	m.message.SetContent(lipgloss.NewStyle().Width(m.message.Width).Render(text))
+	m.message.GotoTop()
+}
+
+// layout sizes the panes to the terminal: files on the left, the diff
+// above the message on the right, and two lines for the status and help
+func (m *tuiModel) layout() {
+	left := m.filesWidth()
+	right := max(m.width-left, 10)
+	body := max(m.height-2, 6)
+	diffHeight := body * 3 / 5
+
+	// The border takes a line or column on each side, the title a line
+	m.diff.Width, m.diff.Height = right-2, max(diffHeight-3, 1)
+	m.message.Width, m.message.Height = right-2, max(body-diffHeight-3, 1)
+	m.editor.SetWidth(m.message.Width)
+	m.editor.SetHeight(m.message.Height)
+	m.showMessage()
+}
+
+func (m tuiModel) filesWidth() int {
+	return max(m.width/3, 24)
+}
+
+func (m tuiModel) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	body := max(m.height-2, 6)
+	diffHeight := body * 3 / 5
+	right := max(m.width-m.filesWidth(), 10)
+
+	files := m.pane(filesPane, i18n.T("Changed files"), m.filesView(body-3), m.filesWidth(), body)
+	diffTitle := i18n.T("Diff")
+	if len(m.files) > 0 {
+		diffTitle = fmt.Sprintf(i18n.T("Diff of %s"), m.files[m.cursor].Path)
+	}
+	diff := m.pane(diffPane, diffTitle, m.diff.View(), right, diffHeight)
+
+	messageTitle := i18n.T("Commit message")
+	if m.prMode {
+		messageTitle = i18n.T("Pull request, title on the first line")
+	}
+	content := m.message.View()
+	if m.editing {
+		messageTitle += i18n.T(" (editing, esc to finish)")
+		content = m.editor.View()
+	}
+	message := m.pane(messagePane, messageTitle, content, right, body-diffHeight)
+
+	status := m.status
+	if m.busy != "" {
+		status = m.spinner.View() + " " + m.busy + "..."
+	} else if m.failed {
+		status = paint(stdoutRenderer, red, status)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, files, lipgloss.JoinVertical(lipgloss.Left, diff, message)),
+		truncate(status, max(m.width, 4)),
+		stdoutRenderer.NewStyle().Faint(true).Render(truncate(m.keys.help(), max(m.width, 4))),
+	)
+}
+
+// pane draws content under a title in a border, highlighted when focused
+func (m tuiModel) pane(p tuiPane, title, content string, width, height int) string {
+	color := lipgloss.Color("8")
+	if m.focus == p {
+		color = lipgloss.Color(cyan)
+	}
+	style := stdoutRenderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		Width(width - 2).
+		Height(height - 2).
+		MaxHeight(height)
+	heading := stdoutRenderer.NewStyle().Bold(true).Render(truncate(title, max(width-2, 4)))
+	return style.Render(heading + "\n" + content)
+}
+
+// filesView lists the files around the cursor, staged ones checked
+func (m tuiModel) filesView(height int) string {
+	if len(m.files) == 0 {
+		return i18n.T("No changes")
+	}
+	height = max(height, 1)
+	start := max(0, min(m.cursor-height/2, len(m.files)-height))
+	end := min(start+height, len(m.files))
+
+	width := max(m.filesWidth()-2, 4)
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		f := m.files[i]
+		check := "[ ]"
+		if f.Selected {
+			check = "[x]"
+		}
+		line := truncate(fmt.Sprintf("%s %s %s", check, f.Status, f.Path), width)
+		switch {
+		case i == m.cursor:
+			line = stdoutRenderer.NewStyle().Reverse(true).Render(line)
+		case f.Selected:
+			line = Added(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}